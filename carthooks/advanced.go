@@ -58,6 +58,10 @@ type WatchDataOptions struct {
 	Filters          map[string]interface{} `json:"filters,omitempty"`
 	Age              int                    `json:"age,omitempty"`
 	WatchStartTime   int64                  `json:"watch_start_time,omitempty"`
+	Secret           string                 `json:"secret,omitempty"` // Shared HMAC secret for webhook endpoints
+	EventBusARN      string                 `json:"event_bus_arn,omitempty"` // Target bus for eventbridge endpoints
+	AWSRegion        string                 `json:"aws_region,omitempty"`    // Region of the eventbridge bus
+	MessageGroupBy   string                 `json:"message_group_by,omitempty"` // Field used as MessageGroupId for FIFO queues (default: item id)
 }
 
 // WatchDataResponse represents a watch data response
@@ -72,10 +76,7 @@ func (c *Client) GetSubmissionToken(appID, collectionID uint, options *Submissio
 	
 	resp, err := c.makeRequest("POST", path, options, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 	
 	return c.parseResponse(resp)
@@ -87,10 +88,7 @@ func (c *Client) UpdateSubmissionToken(appID, collectionID, itemID uint, options
 	
 	resp, err := c.makeRequest("POST", path, options, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 	
 	return c.parseResponse(resp)
@@ -102,10 +100,7 @@ func (c *Client) GetUploadToken() *Result {
 	
 	resp, err := c.makeRequest("POST", path, nil, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 	
 	return c.parseResponse(resp)
@@ -115,15 +110,7 @@ func (c *Client) GetUploadToken() *Result {
 func (c *Client) GetUser(userID uint) *Result {
 	path := fmt.Sprintf("/v1/users/%d", userID)
 	
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-	
-	return c.parseResponse(resp)
+	return c.readRequest("GET", path, nil, nil)
 }
 
 // GetUserByToken gets user information by token
@@ -132,10 +119,7 @@ func (c *Client) GetUserByToken(token string) *Result {
 	
 	resp, err := c.makeRequest("GET", path, nil, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 	
 	return c.parseResponse(resp)
@@ -147,10 +131,7 @@ func (c *Client) StartWatchData(options *WatchDataOptions) *Result {
 	
 	resp, err := c.makeRequest("POST", path, options, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 	
 	return c.parseResponse(resp)
@@ -160,67 +141,43 @@ func (c *Client) StartWatchData(options *WatchDataOptions) *Result {
 func (c *Client) GetCollections(appID uint) *Result {
 	path := fmt.Sprintf("/v1/apps/%d/collections", appID)
 	
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-	
-	return c.parseResponse(resp)
+	return c.cachedRead(path, nil)
 }
 
 // GetCollection gets a specific collection
 func (c *Client) GetCollection(appID, collectionID uint) *Result {
 	path := fmt.Sprintf("/v1/apps/%d/collections/%d", appID, collectionID)
 	
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-	
-	return c.parseResponse(resp)
+	return c.cachedRead(path, nil)
 }
 
 // GetApps gets available apps
 func (c *Client) GetApps() *Result {
 	path := "/v1/apps"
 	
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-	
-	return c.parseResponse(resp)
+	return c.readRequest("GET", path, nil, nil)
 }
 
 // GetApp gets a specific app
 func (c *Client) GetApp(appID uint) *Result {
 	path := fmt.Sprintf("/v1/apps/%d", appID)
 	
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-	
-	return c.parseResponse(resp)
+	return c.cachedRead(path, nil)
 }
 
 // Collection represents a collection structure
 type Collection struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Fields      []CollectionField `json:"fields,omitempty"`
+}
+
+// CollectionField describes a field of a collection's schema
+type CollectionField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
 }
 
 // App represents an application structure
@@ -0,0 +1,147 @@
+package amqpsource
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeBroker is the acknowledger of the deliveries it queues; nacks with
+// requeue put the delivery back, flagged as redelivered, as RabbitMQ does
+type fakeBroker struct {
+	mu         sync.Mutex
+	deliveries chan amqp.Delivery
+	acked      []uint64
+	nacked     []uint64
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{deliveries: make(chan amqp.Delivery, 10)}
+}
+
+func (b *fakeBroker) publish(tag uint64, id, body string) {
+	b.deliveries <- amqp.Delivery{
+		Acknowledger: b,
+		DeliveryTag:  tag,
+		MessageId:    id,
+		Body:         []byte(body),
+		Headers:      amqp.Table{"tenant": int32(3)},
+	}
+}
+
+func (b *fakeBroker) Ack(tag uint64, multiple bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.acked = append(b.acked, tag)
+	return nil
+}
+
+func (b *fakeBroker) Nack(tag uint64, multiple, requeue bool) error {
+	b.mu.Lock()
+	b.nacked = append(b.nacked, tag)
+	b.mu.Unlock()
+	if requeue {
+		b.deliveries <- amqp.Delivery{Acknowledger: b, DeliveryTag: tag + 100, MessageId: "redelivered", Redelivered: true}
+	}
+	return nil
+}
+
+func (b *fakeBroker) Reject(tag uint64, requeue bool) error {
+	return b.Nack(tag, false, requeue)
+}
+
+func newTestSource(broker *fakeBroker) *Source {
+	return &Source{
+		config:     Config{Prefetch: 10, PollInterval: 20 * time.Millisecond},
+		deliveries: broker.deliveries,
+	}
+}
+
+func TestSource_ReceiveBuffered(t *testing.T) {
+	broker := newFakeBroker()
+	broker.publish(1, "m-1", `{"version":"1"}`)
+	broker.publish(2, "m-2", `{"version":"1"}`)
+	source := newTestSource(broker)
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != "m-1" || string(messages[0].Body) != `{"version":"1"}` {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+	if messages[0].Attributes["tenant"] != "3" || messages[0].Attributes["redelivered"] != "" {
+		t.Errorf("Unexpected attributes %v", messages[0].Attributes)
+	}
+
+	// No deliveries: Receive returns empty after the poll interval
+	messages, err = source.Receive(context.Background())
+	if err != nil || len(messages) != 0 {
+		t.Errorf("Expected no messages, got %d, %v", len(messages), err)
+	}
+}
+
+func TestSource_AckAndNackRedelivers(t *testing.T) {
+	broker := newFakeBroker()
+	broker.publish(1, "m-1", `{}`)
+	broker.publish(2, "m-2", `{}`)
+	source := newTestSource(broker)
+	ctx := context.Background()
+
+	messages, err := source.Receive(ctx)
+	if err != nil || len(messages) != 2 {
+		t.Fatalf("Receive() = %d messages, %v", len(messages), err)
+	}
+	if err := source.Ack(ctx, messages[0]); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if err := source.Nack(ctx, messages[1]); err != nil {
+		t.Fatalf("Nack() failed: %v", err)
+	}
+	if len(broker.acked) != 1 || broker.acked[0] != 1 || len(broker.nacked) != 1 || broker.nacked[0] != 2 {
+		t.Errorf("Unexpected acks %v and nacks %v", broker.acked, broker.nacked)
+	}
+
+	messages, err = source.Receive(ctx)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Receive() = %d messages, %v", len(messages), err)
+	}
+	if messages[0].Attributes["redelivered"] != "true" {
+		t.Errorf("Expected the nacked message requeued and redelivered, got %+v", messages[0])
+	}
+}
+
+func TestSource_ReceiveCancelled(t *testing.T) {
+	source := newTestSource(newFakeBroker())
+	source.config.PollInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := source.Receive(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSource_RejectsForeignMessages(t *testing.T) {
+	source := newTestSource(newFakeBroker())
+	message := &carthooks.Message{ID: "x", Raw: "not a delivery"}
+	if err := source.Ack(context.Background(), message); err == nil {
+		t.Error("Expected Ack to reject a non-AMQP message")
+	}
+	if err := source.Nack(context.Background(), message); err == nil {
+		t.Error("Expected Nack to reject a non-AMQP message")
+	}
+}
+
+func TestNew_RequiresURLAndQueue(t *testing.T) {
+	if _, err := New(Config{Queue: "events"}); err == nil {
+		t.Error("Expected an error without a URL")
+	}
+	if _, err := New(Config{URL: "amqp://localhost"}); err == nil {
+		t.Error("Expected an error without a queue")
+	}
+}
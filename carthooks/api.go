@@ -41,15 +41,7 @@ func (c *Client) GetItems(appID, collectionID uint, limit, start int, options ma
 		params[k] = v
 	}
 
-	resp, err := c.makeRequest("GET", path, nil, params)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.readRequest("GET", path, nil, params)
 }
 
 // GetItemByID retrieves a specific item by ID
@@ -69,15 +61,7 @@ func (c *Client) GetItemByID(appID, collectionID, itemID uint, fields []string)
 		params["fields"] = fieldsStr
 	}
 
-	resp, err := c.makeRequest("GET", path, nil, params)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.cachedRead(path, params)
 }
 
 // QueryItems queries items with advanced filtering and sorting
@@ -92,15 +76,7 @@ func (c *Client) QueryItems(appID, collectionID uint, options *QueryOptions) *Re
 
 	path := fmt.Sprintf("/v1/apps/%d/collections/%d/items/query", appID, collectionID)
 
-	resp, err := c.makeRequest("POST", path, options, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.readRequest("POST", path, options, nil)
 }
 
 // CreateItem creates a new item in a collection
@@ -111,15 +87,7 @@ func (c *Client) CreateItem(appID, collectionID uint, data map[string]interface{
 		"data": data,
 	}
 
-	resp, err := c.makeRequest("POST", path, body, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.writeRequest("POST", path, body)
 }
 
 // UpdateItem updates an existing item
@@ -130,15 +98,11 @@ func (c *Client) UpdateItem(appID, collectionID, itemID uint, data map[string]in
 		"data": data,
 	}
 
-	resp, err := c.makeRequest("PUT", path, body, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+	result := c.writeRequest("PUT", path, body)
+	if result.Success {
+		c.InvalidateItem(appID, collectionID, itemID)
 	}
-
-	return c.parseResponse(resp)
+	return result
 }
 
 // DeleteItem deletes an item from a collection
@@ -147,13 +111,14 @@ func (c *Client) DeleteItem(appID, collectionID, itemID uint) *Result {
 
 	resp, err := c.makeRequest("DELETE", path, nil, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
-	return c.parseResponse(resp)
+	result := c.parseResponse(resp)
+	if result.Success {
+		c.InvalidateItem(appID, collectionID, itemID)
+	}
+	return result
 }
 
 // LockItem locks an item to prevent concurrent modifications
@@ -175,10 +140,7 @@ func (c *Client) LockItem(appID, collectionID, itemID uint, options *LockOptions
 
 	resp, err := c.makeRequest("POST", path, body, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -195,10 +157,7 @@ func (c *Client) UnlockItem(appID, collectionID, itemID uint, lockID string) *Re
 
 	resp, err := c.makeRequest("POST", path, body, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -214,10 +173,7 @@ func (c *Client) CreateSubItem(appID, collectionID, itemID, fieldID uint, data m
 
 	resp, err := c.makeRequest("POST", path, body, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -233,10 +189,7 @@ func (c *Client) UpdateSubItem(appID, collectionID, itemID, fieldID, subItemID u
 
 	resp, err := c.makeRequest("PUT", path, body, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -248,10 +201,7 @@ func (c *Client) DeleteSubItem(appID, collectionID, itemID, fieldID, subItemID u
 
 	resp, err := c.makeRequest("DELETE", path, nil, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -263,10 +213,7 @@ func (c *Client) CreateConnection(appID uint, request *CreateConnectionRequest)
 
 	resp, err := c.makeRequest("POST", path, request, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -278,10 +225,7 @@ func (c *Client) CreateConnectionLog(appID, connectionID uint, request *CreateCo
 
 	resp, err := c.makeRequest("POST", path, request, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -293,10 +237,7 @@ func (c *Client) CreateConnectionUsage(appID, connectionID uint, request *Create
 
 	resp, err := c.makeRequest("POST", path, request, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -308,10 +249,7 @@ func (c *Client) UpdateConnection(appID, connectionID uint, request *UpdateConne
 
 	resp, err := c.makeRequest("PUT", path, request, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -321,15 +259,7 @@ func (c *Client) UpdateConnection(appID, connectionID uint, request *UpdateConne
 func (c *Client) GetConnection(appID, connectionID uint) *Result {
 	path := fmt.Sprintf("/v1/apps/%d/connections/%d", appID, connectionID)
 
-	resp, err := c.makeRequest("GET", path, nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.readRequest("GET", path, nil, nil)
 }
 
 // DeleteConnection deletes a connection
@@ -338,10 +268,7 @@ func (c *Client) DeleteConnection(appID, connectionID uint) *Result {
 
 	resp, err := c.makeRequest("DELETE", path, nil, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -353,10 +280,7 @@ func (c *Client) StopWatchData(options *WatchDataOptions) *Result {
 
 	resp, err := c.makeRequest("DELETE", path, options, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
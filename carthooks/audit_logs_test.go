@@ -0,0 +1,61 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_GetAuditLogs(t *testing.T) {
+	from := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/v1/audit-logs" || query.Get("filters[actor_id]") != "7" || query.Get("filters[action]") != "item.delete" ||
+			query.Get("filters[from]") != "1700000000" || query.Get("filters[to]") != "" || query.Get("pagination[page]") != "2" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"data":[{"id":"a1","actor_id":7,"action":"item.delete","resource_type":"item","resource_id":"42","created_at":1700000100}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	entries, err := client.GetAuditLogs(&AuditLogFilter{ActorID: 7, Action: "item.delete", From: from}, &PaginationOptions{Page: 2}).AuditLogs()
+	if err != nil {
+		t.Fatalf("AuditLogs() returned %v", err)
+	}
+	if len(entries) != 1 || entries[0].ResourceID != "42" || !entries[0].Time().Equal(from.Add(100*time.Second)) {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+}
+
+func TestClient_EachAuditLog(t *testing.T) {
+	const total = 150
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("pagination[page]"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pagination[pageSize]"))
+		var entries []string
+		for i := (page - 1) * pageSize; i < page*pageSize && i < total; i++ {
+			entries = append(entries, fmt.Sprintf(`{"id":"a%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var ids []string
+	err := client.EachAuditLog(context.Background(), nil, func(entry AuditLogEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachAuditLog() returned %v", err)
+	}
+	if len(ids) != total || ids[0] != "a0" || ids[total-1] != "a149" {
+		t.Errorf("Got %d entries, first %v", len(ids), ids[:1])
+	}
+}
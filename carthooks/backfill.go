@@ -0,0 +1,94 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TriggerTypeBackfill marks the synthetic events a watcher delivers for
+// existing records in backfill mode
+const TriggerTypeBackfill = "backfill"
+
+const defaultBackfillPageSize = 100
+
+// backfill pages through the records matching the watcher's filters and
+// delivers each one to the handler as a synthetic record created event.
+// It runs after subscribing, so changes made meanwhile are queued and
+// delivered afterwards.
+func (w *Watcher) backfill(ctx context.Context) error {
+	pageSize := w.config.BackfillPageSize
+	if pageSize <= 0 {
+		pageSize = defaultBackfillPageSize
+	}
+
+	w.logger.Info("backfilling existing records")
+	total := 0
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result := w.config.Client.QueryItems(w.config.AppID, w.config.CollectionID, &QueryOptions{
+			Pagination: &PaginationOptions{Page: page, PageSize: pageSize},
+			Filters:    w.config.Filters,
+		})
+		if !result.Success {
+			return fmt.Errorf("backfill failed on page %d: %s", page, result.Error)
+		}
+
+		items, _ := result.Data.([]interface{})
+		events := make([]EventMessage, 0, len(items))
+		for _, item := range items {
+			events = append(events, EventMessage{
+				Version: "1",
+				Meta: EventMessageMeta{
+					CollectionID: w.config.CollectionID,
+					Event:        EventCodeRecordCreated,
+					TriggerType:  TriggerTypeBackfill,
+				},
+				Payload: item,
+			})
+		}
+		if err := w.deliverBackfill(ctx, events); err != nil {
+			return fmt.Errorf("backfill failed on page %d: %w", page, err)
+		}
+		total += len(events)
+
+		pagination := result.GetPagination()
+		if len(items) < pageSize || (pagination != nil && pagination.TotalPages > 0 && page >= pagination.TotalPages) {
+			break
+		}
+	}
+
+	w.logger.Info("backfill complete", "records", total)
+	return nil
+}
+
+// deliverBackfill passes a page of synthetic events to the batch handler,
+// or to the handler one at a time
+func (w *Watcher) deliverBackfill(ctx context.Context, events []EventMessage) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	defer func() { w.metrics.HandlerDuration(time.Since(start)) }()
+
+	if w.config.BatchHandler != nil {
+		return recoverHandler(func() error {
+			return w.config.BatchHandler(ctx, events)
+		})
+	}
+	if w.handler == nil {
+		return nil
+	}
+	for i := range events {
+		if err := recoverHandler(func() error {
+			return w.handler(ctx, &events[i])
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
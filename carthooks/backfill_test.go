@@ -0,0 +1,61 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWatcher_BackfillBeforeLiveMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/apps/1/collections/2/items/query" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		var options QueryOptions
+		json.NewDecoder(r.Body).Decode(&options)
+		if options.Filters["status"] != "open" {
+			t.Errorf("Expected watcher filters, got %v", options.Filters)
+		}
+
+		switch options.Pagination.Page {
+		case 1:
+			fmt.Fprint(w, `{"data":[{"id":1},{"id":2}],"meta":{"pagination":{"page":1,"pageSize":2,"totalPages":2}}}`)
+		case 2:
+			fmt.Fprint(w, `{"data":[{"id":3}],"meta":{"pagination":{"page":2,"pageSize":2,"totalPages":2}}}`)
+		default:
+			t.Errorf("Unexpected page %d", options.Pagination.Page)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []string
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Client:           NewClient(&ClientConfig{BaseURL: server.URL}),
+		AppID:            1,
+		CollectionID:     2,
+		Filters:          map[string]interface{}{"status": "open"},
+		Source:           &onceSource{batch: []*Message{fifoMessage("live", "", 4)}},
+		Backfill:         true,
+		BackfillPageSize: 2,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			received = append(received, fmt.Sprintf("%s:%v", event.Meta.TriggerType, event.Record()["id"]))
+			if len(received) == 4 {
+				cancel()
+			}
+			return nil
+		},
+	})
+
+	if err := watcher.Run(ctx); err != nil {
+		t.Fatalf("Run() returned %v", err)
+	}
+	if fmt.Sprint(received) != "[backfill:1 backfill:2 backfill:3 :4]" {
+		t.Errorf("Expected backfilled records before the live message, got %v", received)
+	}
+}
@@ -0,0 +1,124 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ItemUpdate is one item of a bulk update
+type ItemUpdate struct {
+	ItemID uint
+	Data   map[string]interface{}
+}
+
+// BulkItemResult is the outcome for one item of a bulk operation
+type BulkItemResult struct {
+	// Index is the item's position in the input
+	Index int
+	// ItemID is the item's ID; for creates it is the new item's ID, or 0
+	// if it failed
+	ItemID uint
+	Result *Result
+	// Err is the item's error, or nil if it succeeded
+	Err error
+}
+
+// BulkResult reports the per-item outcomes of a bulk operation. Items are
+// processed in order and a failed item does not stop the others.
+type BulkResult struct {
+	Items []BulkItemResult
+}
+
+// BulkItemError is the error of one failed item of a bulk operation
+type BulkItemError struct {
+	Index  int
+	ItemID uint
+	Err    error
+}
+
+// Error implements the error interface
+func (e *BulkItemError) Error() string {
+	if e.ItemID != 0 {
+		return fmt.Sprintf("item %d (index %d): %v", e.ItemID, e.Index, e.Err)
+	}
+	return fmt.Sprintf("item at index %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the item's error
+func (e *BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+// Succeeded returns the outcomes of the items that succeeded
+func (r *BulkResult) Succeeded() []BulkItemResult {
+	var succeeded []BulkItemResult
+	for _, item := range r.Items {
+		if item.Err == nil {
+			succeeded = append(succeeded, item)
+		}
+	}
+	return succeeded
+}
+
+// Failed returns the outcomes of the items that failed
+func (r *BulkResult) Failed() []BulkItemResult {
+	var failed []BulkItemResult
+	for _, item := range r.Items {
+		if item.Err != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of the failed items, each a *BulkItemError, with
+// errors.Join, or returns nil if every item succeeded. errors.Is and
+// errors.As match any of the item errors.
+func (r *BulkResult) Err() error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Err != nil {
+			errs = append(errs, &BulkItemError{Index: item.Index, ItemID: item.ItemID, Err: item.Err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// add records the outcome of one item
+func (r *BulkResult) add(index int, itemID uint, result *Result) {
+	item := BulkItemResult{Index: index, ItemID: itemID, Result: result, Err: result.Err()}
+	if itemID == 0 && item.Err == nil {
+		if record, err := result.GetRecord(); err == nil {
+			item.ItemID = record.ID
+		}
+	}
+	r.Items = append(r.Items, item)
+}
+
+// BulkCreateItems creates each of items in a collection, reporting the
+// outcome per item
+func (c *Client) BulkCreateItems(appID, collectionID uint, items []map[string]interface{}) *BulkResult {
+	result := &BulkResult{}
+	for i, data := range items {
+		result.add(i, 0, c.CreateItem(appID, collectionID, data))
+	}
+	return result
+}
+
+// BulkUpdateItems applies each of updates, reporting the outcome per item
+func (c *Client) BulkUpdateItems(appID, collectionID uint, updates []ItemUpdate) *BulkResult {
+	result := &BulkResult{}
+	for i, update := range updates {
+		result.add(i, update.ItemID, c.UpdateItem(appID, collectionID, update.ItemID, update.Data))
+	}
+	return result
+}
+
+// BulkDeleteItems deletes each of itemIDs, reporting the outcome per item
+func (c *Client) BulkDeleteItems(appID, collectionID uint, itemIDs []uint) *BulkResult {
+	result := &BulkResult{}
+	for i, itemID := range itemIDs {
+		result.add(i, itemID, c.DeleteItem(appID, collectionID, itemID))
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_BulkUpdateItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/items/2") {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"item not found"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":1}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	result := client.BulkUpdateItems(1, 2, []ItemUpdate{
+		{ItemID: 1, Data: map[string]interface{}{"title": "a"}},
+		{ItemID: 2, Data: map[string]interface{}{"title": "b"}},
+		{ItemID: 3, Data: map[string]interface{}{"title": "c"}},
+	})
+
+	if len(result.Items) != 3 || len(result.Succeeded()) != 2 || len(result.Failed()) != 1 {
+		t.Fatalf("Unexpected outcomes %+v", result.Items)
+	}
+	err := result.Err()
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = false", err)
+	}
+	var itemErr *BulkItemError
+	if !errors.As(err, &itemErr) || itemErr.ItemID != 2 || itemErr.Index != 1 {
+		t.Errorf("errors.As = %+v", itemErr)
+	}
+
+	created := client.BulkCreateItems(1, 2, []map[string]interface{}{{"title": "a"}})
+	if created.Err() != nil || created.Items[0].ItemID != 1 {
+		t.Errorf("BulkCreateItems = %+v, %v", created.Items, created.Err())
+	}
+}
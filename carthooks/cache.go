@@ -0,0 +1,288 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached API result together with the time it was stored
+type CacheEntry struct {
+	Result   *Result
+	StoredAt time.Time
+}
+
+// Cache stores results of read requests. A cache must not be shared between
+// clients that authenticate as different users.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// DefaultMemoryCacheEntries is the bound NewMemoryCache uses when given 0
+const DefaultMemoryCacheEntries = 1000
+
+// MemoryCache is an in-process Cache safe for concurrent use
+type MemoryCache struct {
+	mu         sync.RWMutex
+	entries    map[string]*CacheEntry
+	maxEntries int
+}
+
+// NewMemoryCache creates an in-memory cache holding at most maxEntries
+// results (DefaultMemoryCacheEntries if 0, unbounded if negative). When full,
+// an arbitrary entry is evicted.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries == 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+	return &MemoryCache{
+		entries:    make(map[string]*CacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the entry stored under key
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.entries[key]
+	return entry, ok
+}
+
+// Set stores entry under key
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.entries[key]; !exists && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		for k := range m.entries {
+			delete(m.entries, k)
+			break
+		}
+	}
+	m.entries[key] = entry
+}
+
+// Delete removes the entry stored under key
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+// DeleteMatching removes every entry whose key satisfies match
+func (m *MemoryCache) DeleteMatching(match func(key string) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.entries {
+		if match(key) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// StaleError describes why a stale cached result was returned instead of a
+// fresh one
+type StaleError struct {
+	Cause    string    // error from the failed request
+	StoredAt time.Time // when the cached result was fetched
+}
+
+// Error implements the error interface
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("serving cached result from %s: %s", e.StoredAt.Format(time.RFC3339), e.Cause)
+}
+
+// cacheKey builds a deterministic cache key for a read request
+func cacheKey(method, path string, body interface{}, params map[string]string) string {
+	var b strings.Builder
+	b.WriteString(method)
+	b.WriteString(" ")
+	b.WriteString(path)
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i == 0 {
+			b.WriteString("?")
+		} else {
+			b.WriteString("&")
+		}
+		b.WriteString(k + "=" + params[k])
+	}
+
+	if body != nil {
+		if jsonData, err := json.Marshal(body); err == nil {
+			b.WriteString(" ")
+			b.Write(jsonData)
+		}
+	}
+
+	return b.String()
+}
+
+// copyResult returns a copy of r sharing nothing mutable with it, so
+// callers can't change a cached result
+func copyResult(r *Result) *Result {
+	cp := *r
+	cp.Data = copyValue(r.Data)
+	if r.Meta != nil {
+		cp.Meta = copyValue(r.Meta).(map[string]interface{})
+	}
+	cp.Header = r.Header.Clone()
+	if r.raw != nil {
+		cp.raw = append([]byte(nil), r.raw...)
+	}
+	return &cp
+}
+
+// copyValue deep-copies the maps and slices of a decoded JSON value
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			cp[k] = copyValue(e)
+		}
+		return cp
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for i, e := range v {
+			cp[i] = copyValue(e)
+		}
+		return cp
+	default:
+		return v
+	}
+}
+
+// cachedRead performs a GET that is served from the cache while the cached
+// result is younger than CacheTTL
+func (c *Client) cachedRead(path string, params map[string]string) *Result {
+	if c.cache != nil && c.cacheTTL > 0 {
+		entry, ok := c.cache.Get(cacheKey("GET", path, nil, params))
+		if ok && time.Since(entry.StoredAt) < c.cacheTTL {
+			return copyResult(entry.Result)
+		}
+	}
+
+	return c.read("GET", path, nil, params, c.cacheTTL > 0 || c.staleIfError)
+}
+
+// InvalidateItem drops cached results for an item, whatever fields were
+// requested. UpdateItem and DeleteItem call it on success.
+func (c *Client) InvalidateItem(appID, collectionID, itemID uint) {
+	c.invalidate(fmt.Sprintf("/v1/apps/%d/collections/%d/items/%d", appID, collectionID, itemID), false)
+}
+
+// InvalidateCollection drops the cached collection and the app's cached
+// collection list, e.g. after a schema change
+func (c *Client) InvalidateCollection(appID, collectionID uint) {
+	c.invalidate(fmt.Sprintf("/v1/apps/%d/collections/%d", appID, collectionID), false)
+	c.invalidate(fmt.Sprintf("/v1/apps/%d/collections", appID), false)
+}
+
+// InvalidateApp drops every cached result under an app: the app itself, its
+// collections and their items
+func (c *Client) InvalidateApp(appID uint) {
+	c.invalidate(fmt.Sprintf("/v1/apps/%d", appID), true)
+}
+
+// InvalidateCache drops every cached result
+func (c *Client) InvalidateCache() {
+	c.invalidate("/", true)
+}
+
+// invalidate drops cached results for path, and with subtree for every path
+// below it. Caches that cannot match keys only lose the plain GET of path.
+func (c *Client) invalidate(path string, subtree bool) {
+	if c.cache == nil {
+		return
+	}
+
+	deleter, ok := c.cache.(interface {
+		DeleteMatching(match func(key string) bool)
+	})
+	if !ok {
+		c.cache.Delete(cacheKey("GET", path, nil, nil))
+		return
+	}
+
+	deleter.DeleteMatching(func(key string) bool {
+		// Keys are "METHOD path[?params][ body]"
+		_, rest, _ := strings.Cut(key, " ")
+		keyPath := rest
+		if i := strings.IndexAny(rest, "? "); i >= 0 {
+			keyPath = rest[:i]
+		}
+		if keyPath == path {
+			return true
+		}
+		return subtree && strings.HasPrefix(keyPath, strings.TrimSuffix(path, "/")+"/")
+	})
+}
+
+// readRequest performs a read-only request. With StaleIfError enabled,
+// successful results are stored in the configured cache and the last cached
+// result is returned, flagged as stale, when the API is unreachable.
+func (c *Client) readRequest(method, path string, body interface{}, params map[string]string) *Result {
+	return c.read(method, path, body, params, c.staleIfError)
+}
+
+// read performs a read-only request, storing successful results in the
+// cache if store is set. Only results that cachedRead or StaleIfError can
+// serve are stored, so paging loops don't fill the cache.
+func (c *Client) read(method, path string, body interface{}, params map[string]string, store bool) *Result {
+	if c.cache == nil {
+		store = false
+	}
+	var key string
+	if store {
+		key = cacheKey(method, path, body, params)
+	}
+
+	var result *Result
+	resp, err := c.makeRequest(method, path, body, params)
+	if err != nil {
+		result = requestFailed(err)
+	} else {
+		result = c.parseResponse(resp)
+	}
+
+	if !store {
+		return result
+	}
+
+	if result.Success {
+		c.cache.Set(key, &CacheEntry{Result: copyResult(result), StoredAt: time.Now()})
+		return result
+	}
+
+	// Only fall back when the API itself is unavailable, not on client errors
+	if !c.staleIfError || (err == nil && result.StatusCode < 500) {
+		return result
+	}
+
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		return result
+	}
+
+	stale := copyResult(entry.Result)
+	if stale.Meta == nil {
+		stale.Meta = map[string]interface{}{}
+	}
+	stale.Meta["stale"] = true
+	stale.stale = &StaleError{Cause: result.Error, StoredAt: entry.StoredAt}
+
+	c.logger.Warn("serving stale cached result", "path", path, "error", stale.stale.Cause, "stored_at", entry.StoredAt)
+
+	return stale
+}
@@ -0,0 +1,210 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_StaleIfError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"id": 1, "title": "Cached Item"}}`))
+	}))
+
+	client := NewClient(&ClientConfig{
+		BaseURL:      server.URL,
+		Cache:        NewMemoryCache(0),
+		StaleIfError: true,
+	})
+
+	result := client.GetItemByID(123, 456, 1, nil)
+	if !result.Success || result.IsStale() {
+		t.Fatalf("Expected fresh result, got %s", result)
+	}
+
+	// Simulate an outage
+	server.Close()
+
+	result = client.GetItemByID(123, 456, 1, nil)
+	if !result.Success {
+		t.Fatalf("Expected stale result, got error: %s", result.Error)
+	}
+	if !result.IsStale() || result.Meta["stale"] != true {
+		t.Error("Expected result to be flagged as stale")
+	}
+	if result.StaleError() == nil || result.StaleError().Cause == "" {
+		t.Error("Expected StaleError with a cause")
+	}
+
+	record, err := result.GetRecord()
+	if err != nil || record.Title != "Cached Item" {
+		t.Errorf("Expected cached record, got %v, %v", record, err)
+	}
+
+	// Requests that were never cached still fail
+	result = client.GetItemByID(123, 456, 2, nil)
+	if result.Success {
+		t.Error("Expected failure for uncached item")
+	}
+}
+
+func TestClient_StaleIfErrorIgnoresClientErrors(t *testing.T) {
+	notFound := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if notFound {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"message": "Item not found", "code": "NOT_FOUND"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:      server.URL,
+		Cache:        NewMemoryCache(0),
+		StaleIfError: true,
+	})
+
+	client.GetItemByID(123, 456, 1, nil)
+	notFound = true
+
+	result := client.GetItemByID(123, 456, 1, nil)
+	if result.Success || result.IsStale() {
+		t.Error("Expected 404 to be returned rather than stale data")
+	}
+}
+
+func TestClient_CacheTTL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			requests++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:  server.URL,
+		Cache:    NewMemoryCache(0),
+		CacheTTL: time.Minute,
+	})
+
+	client.GetItemByID(1, 2, 3, nil)
+	client.GetItemByID(1, 2, 3, []string{"title"})
+	if result := client.GetItemByID(1, 2, 3, nil); !result.Success {
+		t.Fatalf("Expected cached result, got %s", result.Error)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	// Updates invalidate every cached variant of the item
+	client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "a"})
+	client.GetItemByID(1, 2, 3, nil)
+	client.GetItemByID(1, 2, 3, []string{"title"})
+	if requests != 4 {
+		t.Errorf("Expected 4 requests after update, got %d", requests)
+	}
+
+	client.GetCollection(1, 2)
+	client.GetApp(1)
+	client.GetApp(10)
+	client.InvalidateApp(1)
+	client.GetCollection(1, 2)
+	client.GetApp(1)
+	client.GetApp(10)
+	if requests != 9 {
+		t.Errorf("Expected 9 requests after InvalidateApp, got %d", requests)
+	}
+}
+
+func TestClient_CacheTTLExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(0)
+	client := NewClient(&ClientConfig{
+		BaseURL:  server.URL,
+		Cache:    cache,
+		CacheTTL: time.Minute,
+	})
+
+	client.GetCollection(1, 2)
+	key := cacheKey("GET", "/v1/apps/1/collections/2", nil, nil)
+	entry, _ := cache.Get(key)
+	cache.Set(key, &CacheEntry{Result: entry.Result, StoredAt: time.Now().Add(-2 * time.Minute)})
+
+	client.GetCollection(1, 2)
+	if requests != 2 {
+		t.Errorf("Expected expired entry to be refetched, got %d requests", requests)
+	}
+}
+
+func TestClient_CacheStoresOnlyServableResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(0)
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: cache, CacheTTL: time.Minute})
+
+	client.GetItems(1, 2, 20, 0, nil)
+	client.QueryItems(1, 2, &QueryOptions{})
+	if len(cache.entries) != 0 {
+		t.Fatalf("Expected reads cachedRead never serves to stay out of the cache, got %d entries", len(cache.entries))
+	}
+	client.GetItemByID(1, 2, 3, nil)
+	if len(cache.entries) != 1 {
+		t.Errorf("Expected the item cached, got %d entries", len(cache.entries))
+	}
+}
+
+func TestNewMemoryCache_DefaultBound(t *testing.T) {
+	cache := NewMemoryCache(0)
+	for i := 0; i < DefaultMemoryCacheEntries+10; i++ {
+		cache.Set(fmt.Sprint(i), &CacheEntry{Result: &Result{}})
+	}
+	if len(cache.entries) != DefaultMemoryCacheEntries {
+		t.Errorf("Expected %d entries, got %d", DefaultMemoryCacheEntries, len(cache.entries))
+	}
+}
+
+func TestClient_CachedResultsAreCopies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"id": 1, "fields": {"tags": ["a"]}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: NewMemoryCache(0), CacheTTL: time.Minute})
+
+	first := client.GetItemByID(1, 2, 3, nil)
+	fields := first.Data.(map[string]interface{})["fields"].(map[string]interface{})
+	fields["tags"].([]interface{})[0] = "changed"
+	fields["title"] = "changed"
+	first.Header.Set("Content-Type", "changed")
+	first.Raw()[0] = 'x'
+
+	second := client.GetItemByID(1, 2, 3, nil)
+	fields = second.Data.(map[string]interface{})["fields"].(map[string]interface{})
+	if fields["tags"].([]interface{})[0] != "a" || fields["title"] != nil {
+		t.Errorf("Expected the cached data unchanged, got %v", fields)
+	}
+	if second.Header.Get("Content-Type") != "application/json" || second.Raw()[0] != '{' {
+		t.Errorf("Expected the cached header and body unchanged, got %v, %s", second.Header, second.Raw())
+	}
+}
@@ -0,0 +1,168 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists how far a watcher has processed events, so a
+// restarted watcher registers its watch from that point and receives the
+// events that occurred while it was down
+type CheckpointStore interface {
+	// Load returns the watcher's checkpoint, or the zero time if it has none
+	Load(ctx context.Context, watcherID string) (time.Time, error)
+	// Save records the watcher's checkpoint
+	Save(ctx context.Context, watcherID string, checkpoint time.Time) error
+}
+
+// MemoryCheckpointStore is an in-process CheckpointStore, mainly for tests
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]time.Time
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]time.Time{}}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryCheckpointStore) Load(ctx context.Context, watcherID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[watcherID], nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryCheckpointStore) Save(ctx context.Context, watcherID string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[watcherID] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps checkpoints in a JSON file, keyed by watcher ID
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore creates a checkpoint store backed by the file at
+// path, which is created on the first Save
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(ctx context.Context, watcherID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, ok := checkpoints[watcherID]
+	if !ok {
+		return time.Time{}, nil
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// Save implements CheckpointStore. The file is replaced atomically.
+func (s *FileCheckpointStore) Save(ctx context.Context, watcherID string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[watcherID] = checkpoint.Unix()
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write checkpoints: %w", err)
+	}
+	return nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]int64, error) {
+	checkpoints := map[string]int64{}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// eventTime returns the updated_at timestamp of the record a message
+// carries, or 0 if it has none
+func eventTime(message *Message) int64 {
+	var body struct {
+		Payload struct {
+			UpdatedAt int64 `json:"updated_at"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(message.Body, &body); err != nil {
+		return 0
+	}
+	return body.Payload.UpdatedAt
+}
+
+// loadCheckpoint sets the start time of the first subscription from the
+// checkpoint store
+func (w *Watcher) loadCheckpoint(ctx context.Context) {
+	if w.config.Checkpoints == nil {
+		return
+	}
+
+	checkpoint, err := w.config.Checkpoints.Load(ctx, w.config.WatcherID)
+	if err != nil {
+		w.logger.Warn("failed to load checkpoint, starting from now", "error", err)
+		return
+	}
+	if !checkpoint.IsZero() {
+		w.logger.Info("resuming from checkpoint", "checkpoint", checkpoint)
+		w.startTime = checkpoint.Unix()
+	}
+}
+
+// saveCheckpoint records the newest event time of a processed batch
+func (w *Watcher) saveCheckpoint(ctx context.Context, messages []*Message) {
+	if w.config.Checkpoints == nil {
+		return
+	}
+
+	var newest int64
+	for _, message := range messages {
+		if t := eventTime(message); t > newest {
+			newest = t
+		}
+	}
+
+	w.checkpointMu.Lock()
+	defer w.checkpointMu.Unlock()
+	if newest <= w.checkpoint {
+		return
+	}
+	if err := w.config.Checkpoints.Save(ctx, w.config.WatcherID, time.Unix(newest, 0)); err != nil {
+		w.logger.Warn("failed to save checkpoint", "error", err)
+		return
+	}
+	w.checkpoint = newest
+}
@@ -0,0 +1,64 @@
+package carthooks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+
+	checkpoint, err := store.Load(ctx, "orders")
+	if err != nil || !checkpoint.IsZero() {
+		t.Fatalf("Expected no checkpoint, got %v, %v", checkpoint, err)
+	}
+
+	want := time.Unix(1700000000, 0)
+	if err := store.Save(ctx, "orders", want); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if err := store.Save(ctx, "invoices", want.Add(time.Hour)); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	checkpoint, err = store.Load(ctx, "orders")
+	if err != nil || !checkpoint.Equal(want) {
+		t.Errorf("Expected %v, got %v, %v", want, checkpoint, err)
+	}
+}
+
+func TestWatcher_SavesNewestProcessedEvent(t *testing.T) {
+	store := NewMemoryCheckpointStore()
+	watcher, _ := NewWatcher(&WatcherConfig{
+		WatcherID:   "orders",
+		Source:      &recordingSource{},
+		Checkpoints: store,
+		Handler:     func(ctx context.Context, event *EventMessage) error { return nil },
+	})
+
+	ctx := context.Background()
+	watcher.processBatch(ctx, []*Message{
+		{ID: "1", Body: []byte(`{"payload":{"id":1,"updated_at":1700000200}}`)},
+		{ID: "2", Body: []byte(`{"payload":{"id":2,"updated_at":1700000100}}`)},
+	})
+	checkpoint, _ := store.Load(ctx, "orders")
+	if checkpoint.Unix() != 1700000200 {
+		t.Errorf("Expected checkpoint 1700000200, got %d", checkpoint.Unix())
+	}
+
+	// An older event never moves the checkpoint back
+	watcher.processBatch(ctx, []*Message{{ID: "3", Body: []byte(`{"payload":{"id":3,"updated_at":1700000000}}`)}})
+	checkpoint, _ = store.Load(ctx, "orders")
+	if checkpoint.Unix() != 1700000200 {
+		t.Errorf("Expected checkpoint to stay at 1700000200, got %d", checkpoint.Unix())
+	}
+
+	resumed, _ := NewWatcher(&WatcherConfig{WatcherID: "orders", Source: &recordingSource{}, Checkpoints: store})
+	resumed.loadCheckpoint(ctx)
+	if resumed.startTime != 1700000200 {
+		t.Errorf("Expected resumed watcher to start at 1700000200, got %d", resumed.startTime)
+	}
+}
@@ -0,0 +1,256 @@
+package carthooks
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureRate    = 0.5
+	defaultBreakerMinRequests    = 10
+	defaultBreakerWindow         = 20
+	defaultBreakerCoolDown       = 30 * time.Second
+	defaultBreakerHalfOpenProbes = 1
+)
+
+// ErrCircuitOpen is returned (via Result.Err) for requests rejected without
+// being sent because the client's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed sends requests normally
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen until the cool-down
+	// has passed
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through to
+	// test whether the API has recovered
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig enables a circuit breaker around API requests. When
+// too many recent requests fail, the breaker opens and requests fail fast
+// with ErrCircuitOpen instead of waiting on a degraded API. After the
+// cool-down, probe requests are let through; if they succeed the breaker
+// closes again, otherwise it reopens.
+//
+// Network errors and 5xx responses count as failures. Other responses,
+// including 4xx, count as successes since the API answered.
+type CircuitBreakerConfig struct {
+	// FailureRate opens the breaker when the share of failed requests in
+	// the window reaches it (default 0.5)
+	FailureRate float64
+	// MinRequests is how many requests the window must hold before the
+	// failure rate is considered (default 10)
+	MinRequests int
+	// Window is how many recent requests the failure rate is computed over
+	// (default 20)
+	Window int
+	// CoolDown is how long the breaker stays open before probing (default
+	// 30s)
+	CoolDown time.Duration
+	// HalfOpenProbes is how many probe requests must succeed to close the
+	// breaker; only that many are in flight at once (default 1)
+	HalfOpenProbes int
+	// OnStateChange, if set, is called in its own goroutine whenever the
+	// breaker changes state
+	OnStateChange func(from, to CircuitState)
+}
+
+// circuitBreaker tracks request outcomes and rejects requests while open
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	outcomes []bool // true for failures
+	next     int
+	failures int
+	openedAt time.Time
+	probes   int // probes in flight while half-open
+	probeOK  int // successful probes while half-open
+	now      func() time.Time
+}
+
+func newCircuitBreaker(config *CircuitBreakerConfig) *circuitBreaker {
+	if config == nil {
+		return nil
+	}
+	b := &circuitBreaker{config: *config, now: time.Now}
+	if b.config.FailureRate <= 0 {
+		b.config.FailureRate = defaultBreakerFailureRate
+	}
+	if b.config.MinRequests <= 0 {
+		b.config.MinRequests = defaultBreakerMinRequests
+	}
+	if b.config.Window <= 0 {
+		b.config.Window = defaultBreakerWindow
+	}
+	if b.config.Window < b.config.MinRequests {
+		b.config.Window = b.config.MinRequests
+	}
+	if b.config.CoolDown <= 0 {
+		b.config.CoolDown = defaultBreakerCoolDown
+	}
+	if b.config.HalfOpenProbes <= 0 {
+		b.config.HalfOpenProbes = defaultBreakerHalfOpenProbes
+	}
+	return b
+}
+
+// State returns the breaker's current state
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.config.CoolDown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, and whether it is a probe
+func (b *circuitBreaker) allow() (bool, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		if b.now().Sub(b.openedAt) < b.config.CoolDown {
+			return false, false
+		}
+		b.setState(CircuitHalfOpen)
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.config.HalfOpenProbes-b.probeOK {
+			return false, false
+		}
+		b.probes++
+		return true, true
+	}
+	return true, false
+}
+
+// record registers the outcome of a request
+func (b *circuitBreaker) record(failed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		if b.state != CircuitHalfOpen {
+			return
+		}
+		b.probes--
+		if failed {
+			b.trip()
+			return
+		}
+		b.probeOK++
+		if b.probeOK >= b.config.HalfOpenProbes {
+			b.reset()
+		}
+		return
+	}
+
+	if b.state != CircuitClosed {
+		return
+	}
+	if len(b.outcomes) < b.config.Window {
+		b.outcomes = append(b.outcomes, failed)
+	} else {
+		if b.outcomes[b.next] {
+			b.failures--
+		}
+		b.outcomes[b.next] = failed
+		b.next = (b.next + 1) % b.config.Window
+	}
+	if failed {
+		b.failures++
+	}
+
+	if len(b.outcomes) >= b.config.MinRequests &&
+		float64(b.failures)/float64(len(b.outcomes)) >= b.config.FailureRate {
+		b.trip()
+	}
+}
+
+// trip opens the breaker
+func (b *circuitBreaker) trip() {
+	b.openedAt = b.now()
+	b.probes = 0
+	b.probeOK = 0
+	b.setState(CircuitOpen)
+}
+
+// reset closes the breaker with an empty window
+func (b *circuitBreaker) reset() {
+	b.outcomes = b.outcomes[:0]
+	b.next = 0
+	b.failures = 0
+	b.probes = 0
+	b.probeOK = 0
+	b.setState(CircuitClosed)
+}
+
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.config.OnStateChange != nil {
+		go b.config.OnStateChange(from, state)
+	}
+}
+
+// wrap returns a RoundTripFunc that sends requests with send unless the
+// breaker is open
+func (b *circuitBreaker) wrap(send RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		allowed, probe := b.allow()
+		if !allowed {
+			return nil, ErrCircuitOpen
+		}
+
+		resp, err := send(req)
+		if err != nil && req.Context().Err() != nil {
+			// Cancelled by the caller; says nothing about the API
+			if probe {
+				b.mu.Lock()
+				if b.state == CircuitHalfOpen && b.probes > 0 {
+					b.probes--
+				}
+				b.mu.Unlock()
+			}
+			return resp, err
+		}
+		b.record(err != nil || resp.StatusCode >= 500, probe)
+		return resp, err
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker, or
+// CircuitClosed if none is configured
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.State()
+}
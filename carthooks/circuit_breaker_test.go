@@ -0,0 +1,113 @@
+package carthooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_CircuitBreakerFailsFast(t *testing.T) {
+	var requests, healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"id":1}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		CircuitBreaker: &CircuitBreakerConfig{
+			MinRequests: 4,
+			CoolDown:    50 * time.Millisecond,
+		},
+	})
+
+	for i := 0; i < 4; i++ {
+		if result := client.GetApp(1); result.Success {
+			t.Fatalf("Expected request %d to fail", i)
+		}
+	}
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("Expected the breaker to open, got %s", state)
+	}
+
+	result := client.GetApp(1)
+	if !errors.Is(result.Err(), ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", result.Err())
+	}
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("Expected no request while open, got %d requests", got)
+	}
+
+	// A failed probe reopens the breaker
+	time.Sleep(60 * time.Millisecond)
+	client.GetApp(1)
+	if state := client.CircuitState(); state != CircuitOpen {
+		t.Fatalf("Expected a failed probe to reopen the breaker, got %s", state)
+	}
+
+	// A successful probe closes it
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	if result := client.GetApp(1); !result.Success {
+		t.Fatalf("Expected the probe to succeed, got %+v", result)
+	}
+	if state := client.CircuitState(); state != CircuitClosed {
+		t.Errorf("Expected the breaker to close, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	b := newCircuitBreaker(&CircuitBreakerConfig{FailureRate: 0.5, MinRequests: 4, Window: 4})
+
+	for _, failed := range []bool{false, false, false, true} {
+		b.record(failed, false)
+	}
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("Expected the breaker to stay closed, got %s", state)
+	}
+
+	// The oldest success drops out of the window, leaving 2 of 4 failed
+	b.record(true, false)
+	if state := b.State(); state != CircuitOpen {
+		t.Errorf("Expected the breaker to open at a 50%% failure rate, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_LimitsProbes(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(&CircuitBreakerConfig{MinRequests: 1, CoolDown: time.Second, HalfOpenProbes: 2})
+	b.now = func() time.Time { return now }
+
+	b.record(true, false)
+	if allowed, _ := b.allow(); allowed {
+		t.Fatal("Expected requests to be rejected while open")
+	}
+
+	now = now.Add(time.Second)
+	for i := 0; i < 2; i++ {
+		if allowed, probe := b.allow(); !allowed || !probe {
+			t.Fatalf("Expected probe %d to be allowed", i)
+		}
+	}
+	if allowed, _ := b.allow(); allowed {
+		t.Fatal("Expected only two probes in flight")
+	}
+
+	b.record(false, true)
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("Expected the breaker to wait for the second probe, got %s", state)
+	}
+	b.record(false, true)
+	if state := b.State(); state != CircuitClosed {
+		t.Errorf("Expected the breaker to close, got %s", state)
+	}
+}
@@ -2,9 +2,11 @@ package carthooks
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -19,6 +21,88 @@ type ClientConfig struct {
 	Headers     map[string]string
 	Debug       bool
 	OAuth       *OAuthConfig
+
+	// UserAgent is appended to the SDK's User-Agent header
+	// "carthooks-sdk-go/v<Version>", e.g. "my-app/1.2"
+	UserAgent string
+
+	// Logger receives the client's logs; requests and responses are logged
+	// at debug level. Defaults to a debug level stdout logger when Debug is
+	// set, and slog.Default() otherwise.
+	Logger *slog.Logger
+	// DebugWriter receives the debug logs in Debug mode instead of stdout;
+	// it is ignored when Logger is set
+	DebugWriter io.Writer
+	// DebugBodyLimit caps how many bytes of each request and response body
+	// are logged (default 8 KiB); negative logs whole bodies
+	DebugBodyLimit int
+	// RedactKeys adds body, form and query keys whose values are replaced
+	// with [REDACTED] in logs, on top of DefaultRedactKeys
+	RedactKeys []string
+
+	// ExternalIDField is the field that holds client-assigned external IDs
+	ExternalIDField string
+	// IDGenerator generates external IDs; defaults to UUIDv7Generator
+	IDGenerator IDGenerator
+
+	// Cache stores results of read requests
+	Cache Cache
+	// CacheTTL, if positive, makes GetItemByID, GetCollection,
+	// GetCollections and GetApp serve cached results younger than CacheTTL
+	// without a request. Requires Cache.
+	CacheTTL time.Duration
+	// StaleIfError serves the last cached result, flagged as stale, when
+	// the API is unreachable. Requires Cache.
+	StaleIfError bool
+
+	// Outbox, if set, queues CreateItem and UpdateItem calls made while
+	// the API is unreachable, for replay with ReplayOutbox or RunOutbox.
+	// Every write then carries an IdempotencyKeyHeader.
+	Outbox OutboxStore
+
+	// Transport performs the client's HTTP requests, e.g. an instrumented
+	// or proxy-aware transport, or a vcr.Recorder in tests; defaults to
+	// http.DefaultTransport
+	Transport http.RoundTripper
+	// HTTPClient, if set, is used as is for all requests; Timeout,
+	// Transport and the connection options below are then ignored
+	HTTPClient *http.Client
+	// Proxy routes requests through a proxy; it replaces Transport
+	Proxy *ProxyConfig
+	// TLS customizes certificate verification; it replaces Transport
+	TLS *TLSConfig
+	// Pool tunes connection reuse; it replaces Transport
+	Pool *PoolConfig
+	// Hedging sends a second GET when a read is slow and uses whichever
+	// response arrives first
+	Hedging *HedgeConfig
+	// CircuitBreaker makes requests fail fast with ErrCircuitOpen while the
+	// API is failing
+	CircuitBreaker *CircuitBreakerConfig
+	// Failover sends requests to fallback endpoints while BaseURL is down
+	Failover *FailoverConfig
+	// MaxConcurrentRequests, if positive, caps the requests in flight at
+	// once; further requests wait for a slot, bounded by their context. The
+	// wait is reported in RequestMetric.QueueWait.
+	MaxConcurrentRequests int
+
+	// Metrics, if set, is told about every API request
+	Metrics MetricsHook
+	// Tracing propagates the trace carried by a request's context, see
+	// Client.WithContext
+	Tracing TracePropagator
+
+	// Middleware wraps every API request, outermost first; see Client.Use
+	Middleware []RequestMiddleware
+	// ResponseHooks inspect every API response; see Client.OnResponse
+	ResponseHooks []ResponseHook
+	// MaxHookRetries caps the retries response hooks can request per call
+	// (default 3)
+	MaxHookRetries int
+	// MaxRetryAfter caps how long a retry requested by a response hook
+	// waits, including a server's Retry-After honored by
+	// RetryTransientErrors (default 1m)
+	MaxRetryAfter time.Duration
 }
 
 // Client represents the Carthooks API client
@@ -27,10 +111,34 @@ type Client struct {
 	accessToken    string
 	httpClient     *http.Client
 	headers        map[string]string
-	debug          bool
+	logger         *slog.Logger
+	redact         redactor
+	debugBodyLimit int
 	oauthConfig    *OAuthConfig
 	currentTokens  *OAuthTokens
 	tokenExpiresAt *time.Time
+
+	connectionTokens *connectionTokenCache
+
+	externalIDField string
+	idGenerator     IDGenerator
+
+	cache        Cache
+	cacheTTL     time.Duration
+	staleIfError bool
+	outbox       OutboxStore
+
+	middleware     []RequestMiddleware
+	hedger         *hedger
+	breaker        *circuitBreaker
+	failover       *failover
+	limiter        *limiter
+	metrics        MetricsHook
+	tracer         TracePropagator
+	ctx            context.Context
+	responseHooks  []ResponseHook
+	maxHookRetries int
+	maxRetryAfter  time.Duration
 }
 
 // NewClient creates a new Carthooks client with the given configuration
@@ -74,6 +182,7 @@ func NewClient(config *ClientConfig) *Client {
 	headers := map[string]string{
 		"Content-Type": "application/json",
 		"Accept":       "application/json",
+		"User-Agent":   userAgent(config.UserAgent),
 	}
 
 	// Add custom headers
@@ -86,14 +195,40 @@ func NewClient(config *ClientConfig) *Client {
 		headers["Authorization"] = "Bearer " + accessToken
 	}
 
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   timeout,
+			Transport: newTransport(config),
+		}
+	}
+
 	client := &Client{
-		baseURL:     baseURL,
-		accessToken: accessToken,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
-		headers: headers,
-		debug:   debug,
+		baseURL:         baseURL,
+		accessToken:     accessToken,
+		httpClient:      httpClient,
+		headers:         headers,
+		logger:          newClientLogger(config, debug),
+		debugBodyLimit:  config.DebugBodyLimit,
+		redact:          newRedactor(config.RedactKeys),
+		externalIDField: config.ExternalIDField,
+		idGenerator:     config.IDGenerator,
+		cache:           config.Cache,
+		cacheTTL:        config.CacheTTL,
+		staleIfError:    config.StaleIfError,
+		outbox:          config.Outbox,
+		middleware:      append([]RequestMiddleware(nil), config.Middleware...),
+		responseHooks:   append([]ResponseHook(nil), config.ResponseHooks...),
+		maxHookRetries:  config.MaxHookRetries,
+		maxRetryAfter:   config.MaxRetryAfter,
+		hedger:          newHedger(config.Hedging),
+		breaker:         newCircuitBreaker(config.CircuitBreaker),
+		failover:        newFailover(baseURL, config.Failover),
+		limiter:         newLimiter(config.MaxConcurrentRequests),
+		metrics:         config.Metrics,
+		tracer:          config.Tracing,
+
+		connectionTokens: newConnectionTokenCache(),
 	}
 
 	// Set OAuth configuration if provided
@@ -113,12 +248,74 @@ func NewClient(config *ClientConfig) *Client {
 	return client
 }
 
-// SetAccessToken sets the access token for API authentication
+// SetAccessToken sets the access token for API authentication. It also
+// changes the token of copies made with WithContext; use WithAccessToken
+// for a client with its own token.
 func (c *Client) SetAccessToken(token string) {
 	c.accessToken = token
 	c.headers["Authorization"] = "Bearer " + token
 }
 
+// WithContext returns a copy of the client whose requests are made with
+// ctx, so they are canceled with it and carry its request ID and trace. The
+// copy shares the original's configuration, tokens and caches.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+// Clone returns an independent copy of the client. The copy has its own
+// headers, token state, OAuth configuration, middleware and response
+// hooks, so changing them does not affect the original. It shares the
+// original's HTTP transport, cache, circuit breaker and failover state.
+func (c *Client) Clone() *Client {
+	cp := *c
+	cp.headers = make(map[string]string, len(c.headers))
+	for k, v := range c.headers {
+		cp.headers[k] = v
+	}
+	if c.oauthConfig != nil {
+		config := *c.oauthConfig
+		cp.oauthConfig = &config
+	}
+	if c.currentTokens != nil {
+		tokens := *c.currentTokens
+		cp.currentTokens = &tokens
+	}
+	if c.tokenExpiresAt != nil {
+		expiresAt := *c.tokenExpiresAt
+		cp.tokenExpiresAt = &expiresAt
+	}
+	cp.middleware = append([]RequestMiddleware(nil), c.middleware...)
+	cp.responseHooks = append([]ResponseHook(nil), c.responseHooks...)
+	return &cp
+}
+
+// WithAccessToken returns a clone of the client authenticated with token,
+// e.g. a user's token for one request in a web server, leaving the
+// original untouched. The clone does not refresh the token. Its requests
+// bypass the original's cache, which holds results fetched as another user,
+// and its writes are not queued in the original's outbox, which replays
+// them with the original's token.
+func (c *Client) WithAccessToken(token string) *Client {
+	cp := c.Clone()
+	cp.cache = nil
+	cp.outbox = nil
+	cp.currentTokens = nil
+	cp.tokenExpiresAt = nil
+	cp.SetAccessToken(token)
+	return cp
+}
+
+// context returns the context requests are made with
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // GetBaseURL returns the base URL for the Carthooks API
 func (c *Client) GetBaseURL() string {
 	return c.baseURL
@@ -126,6 +323,12 @@ func (c *Client) GetBaseURL() string {
 
 // makeRequest performs an HTTP request and returns the response
 func (c *Client) makeRequest(method, path string, body interface{}, params map[string]string) (*http.Response, error) {
+	return c.makeRequestWithHeaders(method, path, body, params, nil)
+}
+
+// makeRequestWithHeaders performs an HTTP request with headers added to the
+// client's headers and returns the response
+func (c *Client) makeRequestWithHeaders(method, path string, body interface{}, params map[string]string, headers map[string]string) (*http.Response, error) {
 	// Build URL
 	fullURL := c.baseURL + path
 	if len(params) > 0 {
@@ -153,7 +356,7 @@ func (c *Client) makeRequest(method, path string, body interface{}, params map[s
 	}
 
 	// Create request
-	req, err := http.NewRequest(method, fullURL, reqBody)
+	req, err := http.NewRequestWithContext(c.context(), method, fullURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -162,78 +365,140 @@ func (c *Client) makeRequest(method, path string, body interface{}, params map[s
 	for k, v := range c.headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	requestID := setRequestID(req)
+	c.propagateTrace(req)
 
 	// Debug logging
-	if c.debug {
-		fmt.Printf("[DEBUG] %s %s\n", method, fullURL)
+	if c.debugEnabled() {
+		attrs := []any{"method", method, "url", c.redact.url(fullURL), "request_id", requestID}
 		if body != nil {
 			if jsonData, err := json.Marshal(body); err == nil {
-				fmt.Printf("[DEBUG] Request body: %s\n", string(jsonData))
+				attrs = append(attrs, "body", c.logBody(c.redact.json(jsonData)))
 			}
 		}
+		c.logger.Debug("sending request", attrs...)
 	}
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	// Debug response
-	if c.debug {
-		fmt.Printf("[DEBUG] Response status: %s\n", resp.Status)
-	}
+	c.logger.Debug("received response", "method", method, "url", c.redact.url(fullURL), "request_id", requestID, "status", resp.StatusCode)
 
 	return resp, nil
 }
 
-// parseResponse parses the HTTP response into a Result
+// parseResponse parses the HTTP response into a Result and runs the
+// client's response hooks on it
 func (c *Client) parseResponse(resp *http.Response) *Result {
+	result, body := c.decodeResponse(resp)
+	if len(c.responseHooks) == 0 {
+		return result
+	}
+	return c.applyResponseHooks(resp, body, result)
+}
+
+// decodeResponse reads the HTTP response into a Result, also returning the
+// raw body
+func (c *Client) decodeResponse(resp *http.Response) (*Result, []byte) {
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return &Result{
+			Success:     true,
+			NotModified: true,
+			RequestID:   responseRequestID(resp),
+			StatusCode:  resp.StatusCode,
+			Header:      resp.Header,
+		}, nil
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &Result{
-			Success: false,
-			Error:   fmt.Sprintf("failed to read response body: %v", err),
-		}
+			Success:    false,
+			Error:      fmt.Sprintf("failed to read response body: %v", err),
+			RequestID:  responseRequestID(resp),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+		}, nil
 	}
 
-	if c.debug {
-		fmt.Printf("[DEBUG] Response body: %s\n", string(body))
+	if c.debugEnabled() {
+		c.logger.Debug("response body", "status", resp.StatusCode, "body", c.logBody(c.redact.json(body)))
 	}
 
 	// Try to parse as JSON
 	var apiResp struct {
 		Data  interface{} `json:"data"`
 		Error *struct {
-			Message string `json:"message"`
-			Code    string `json:"code"`
+			Message string          `json:"message"`
+			Code    json.RawMessage `json:"code"`
 		} `json:"error"`
+		// Message is set by proxies and gateways answering in place of
+		// the API, which don't use the error envelope
+		Message string                 `json:"message"`
 		TraceID string                 `json:"trace_id"`
 		Meta    map[string]interface{} `json:"meta"`
 	}
 
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		// If JSON parsing fails, treat as error
-		return &Result{
-			Success: false,
-			Error:   string(body),
+		message := string(body)
+		if message == "" {
+			message = statusMessage(resp.StatusCode)
 		}
+		return &Result{
+			Success:    false,
+			Error:      message,
+			RequestID:  responseRequestID(resp),
+			raw:        body,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+		}, body
 	}
 
+	c.reportServerTraceID(resp, apiResp.TraceID)
+
 	result := &Result{
-		TraceID: apiResp.TraceID,
-		Meta:    apiResp.Meta,
+		TraceID:    apiResp.TraceID,
+		RequestID:  responseRequestID(resp),
+		Meta:       apiResp.Meta,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		raw:        body,
 	}
 
-	if apiResp.Error != nil {
+	switch {
+	case apiResp.Error != nil:
 		result.Success = false
 		result.Error = apiResp.Error.Message
-	} else {
+		result.ErrorCode = parseErrorCode(apiResp.Error.Code)
+	case resp.StatusCode >= 400:
+		// An error status without the error envelope is still an error
+		result.Success = false
+		result.Error = apiResp.Message
+		if result.Error == "" {
+			result.Error = statusMessage(resp.StatusCode)
+		}
+	default:
 		result.Success = true
 		result.Data = apiResp.Data
 	}
 
-	return result
+	return result, body
+}
+
+// statusMessage describes an HTTP status for error responses without a
+// message, e.g. "503 Service Unavailable"
+func statusMessage(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return fmt.Sprintf("%d %s", status, text)
+	}
+	return fmt.Sprintf("HTTP %d", status)
 }
@@ -1,9 +1,12 @@
 package carthooks
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -285,3 +288,164 @@ func TestClient_ErrorHandling(t *testing.T) {
 		t.Errorf("GetError() returned '%s', expected 'Item not found'", result.GetError())
 	}
 }
+
+func TestNewClient_UserAgent(t *testing.T) {
+	var agents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.Header.Get("User-Agent"))
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	NewClient(&ClientConfig{BaseURL: server.URL}).GetApps()
+	NewClient(&ClientConfig{BaseURL: server.URL, UserAgent: "my-app/1.2"}).GetApps()
+
+	want := []string{"carthooks-sdk-go/v" + Version, "carthooks-sdk-go/v" + Version + " my-app/1.2"}
+	if !reflect.DeepEqual(agents, want) {
+		t.Errorf("User-Agent = %q, want %q", agents, want)
+	}
+}
+
+func TestClient_ResultStatusAndHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/apps/404":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"app not found","code":"NOT_FOUND"}}`)
+		default:
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"slow down"}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	result := client.GetApp(404)
+	if result.StatusCode != http.StatusNotFound || !result.IsNotFound() || result.IsRateLimited() {
+		t.Errorf("StatusCode = %d, IsNotFound = %t", result.StatusCode, result.IsNotFound())
+	}
+
+	result = client.GetApps()
+	if !result.IsRateLimited() || result.GetHeader("X-RateLimit-Remaining") != "0" {
+		t.Errorf("IsRateLimited = %t, X-RateLimit-Remaining = %q", result.IsRateLimited(), result.GetHeader("X-RateLimit-Remaining"))
+	}
+}
+
+func TestClient_ResultRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":1},"extensions":{"beta":true}}`)
+	}))
+	defer server.Close()
+
+	result := NewClient(&ClientConfig{BaseURL: server.URL}).GetApp(1)
+
+	var body struct {
+		Extensions struct {
+			Beta bool `json:"beta"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(result.Raw(), &body); err != nil || !body.Extensions.Beta {
+		t.Errorf("Raw() = %s, err %v", result.Raw(), err)
+	}
+	if raw := requestFailed(fmt.Errorf("offline")).Raw(); raw != nil {
+		t.Errorf("Raw() = %s for a failed request, want nil", raw)
+	}
+}
+
+func TestClient_WithAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"authorization":%q,"custom":%q}}`, r.Header.Get("Authorization"), r.Header.Get("X-Custom"))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL, AccessToken: "service-token"})
+
+	user := client.WithAccessToken("user-token")
+	var got map[string]string
+	user.GetCurrentUser().Decode(&got)
+	if got["authorization"] != "Bearer user-token" {
+		t.Errorf("WithAccessToken() client sent %q", got["authorization"])
+	}
+	client.GetCurrentUser().Decode(&got)
+	if got["authorization"] != "Bearer service-token" || client.accessToken != "service-token" {
+		t.Errorf("Original client sent %q after WithAccessToken()", got["authorization"])
+	}
+}
+
+func TestClient_WithAccessTokenBypassesCache(t *testing.T) {
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"authorization":%q}}`, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{
+		BaseURL:      server.URL,
+		AccessToken:  "token-a",
+		Cache:        NewMemoryCache(0),
+		CacheTTL:     time.Minute,
+		StaleIfError: true,
+	})
+	other := client.WithAccessToken("token-b")
+
+	var got map[string]string
+	for _, c := range []*Client{client, other, client, other} {
+		c.GetItemByID(1, 2, 3, nil).Decode(&got)
+		if want := "Bearer " + c.accessToken; got["authorization"] != want {
+			t.Errorf("GetItemByID() as %q returned %q", want, got["authorization"])
+		}
+	}
+
+	client.GetCurrentUser()
+	failing = true
+	if result := other.GetCurrentUser(); result.Success || result.IsStale() {
+		t.Errorf("GetCurrentUser() with another token served a cached result %+v", result)
+	}
+	if result := client.GetCurrentUser(); !result.IsStale() {
+		t.Errorf("GetCurrentUser() did not serve its own stale result, got %+v", result)
+	}
+}
+
+func TestClient_WithAccessTokenBypassesOutbox(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	outbox := NewMemoryOutboxStore()
+	client := NewClient(&ClientConfig{BaseURL: server.URL, AccessToken: "service-token", Outbox: outbox})
+
+	result := client.WithAccessToken("user-token").CreateItem(1, 2, map[string]interface{}{"title": "a"})
+	if result.Success || result.IsQueued() {
+		t.Errorf("Expected the user's write to fail instead of being queued, got %s", result)
+	}
+	if entries, _ := outbox.List(context.Background()); len(entries) != 0 {
+		t.Errorf("Expected nothing queued for replay with the service token, got %d entries", len(entries))
+	}
+}
+
+func TestClient_Clone(t *testing.T) {
+	client := NewClient(&ClientConfig{
+		AccessToken: "token",
+		OAuth:       &OAuthConfig{ClientID: "dvc-abc", ClientSecret: "dvs-xyz"},
+	})
+	clone := client.Clone()
+
+	clone.SetAccessToken("other-token")
+	clone.headers["X-Custom"] = "1"
+	clone.GetOAuthConfig().ClientID = "dvc-other"
+	clone.Use(func(next RoundTripFunc) RoundTripFunc { return next })
+
+	if client.headers["Authorization"] != "Bearer token" || client.headers["X-Custom"] != "" {
+		t.Errorf("Clone shares headers with the original: %v", client.headers)
+	}
+	if client.GetOAuthConfig().ClientID != "dvc-abc" || len(client.middleware) != 0 {
+		t.Errorf("Clone shares OAuth config or middleware with the original")
+	}
+	if clone.httpClient != client.httpClient {
+		t.Errorf("Clone does not share the HTTP client")
+	}
+}
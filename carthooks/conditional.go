@@ -0,0 +1,44 @@
+package carthooks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetItemByIDIfChanged retrieves an item unless it still matches etag, the
+// ETag of an earlier result. An unchanged item returns a successful result
+// with NotModified set and no data, without downloading the record again.
+func (c *Client) GetItemByIDIfChanged(appID, collectionID, itemID uint, fields []string, etag string) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/collections/%d/items/%d", appID, collectionID, itemID)
+
+	params := map[string]string{}
+	if len(fields) > 0 {
+		params["fields"] = strings.Join(fields, ",")
+	}
+
+	return c.conditionalGet(path, params, etag)
+}
+
+// GetCollectionIfChanged retrieves a collection unless it still matches
+// etag; see GetItemByIDIfChanged
+func (c *Client) GetCollectionIfChanged(appID, collectionID uint, etag string) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/collections/%d", appID, collectionID)
+
+	return c.conditionalGet(path, nil, etag)
+}
+
+// conditionalGet sends a GET with If-None-Match set to etag, bypassing the
+// cache
+func (c *Client) conditionalGet(path string, params map[string]string, etag string) *Result {
+	var headers map[string]string
+	if etag != "" {
+		headers = map[string]string{"If-None-Match": etag}
+	}
+
+	resp, err := c.makeRequestWithHeaders("GET", path, nil, params, headers)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
@@ -0,0 +1,56 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_GetItemByIDIfChanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":3,"title":"a"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	result := client.GetItemByIDIfChanged(1, 2, 3, nil, "")
+	if !result.Success || result.NotModified || result.Data == nil {
+		t.Fatalf("Unexpected first result %+v", result)
+	}
+	etag := result.ETag()
+	if etag != `"v1"` {
+		t.Fatalf("ETag() = %q", etag)
+	}
+
+	result = client.GetItemByIDIfChanged(1, 2, 3, nil, etag)
+	if !result.Success || !result.NotModified || result.Data != nil {
+		t.Errorf("Expected not modified result, got %+v", result)
+	}
+	if result.StatusCode != http.StatusNotModified || result.ETag() != etag {
+		t.Errorf("Unexpected status %d or ETag %q", result.StatusCode, result.ETag())
+	}
+}
+
+func TestClient_GetCollectionIfChanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/apps/1/collections/2" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("ETag", `"v2"`)
+		fmt.Fprint(w, `{"data":{"id":2}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	result := client.GetCollectionIfChanged(1, 2, `"v1"`)
+	if !result.Success || result.NotModified || result.ETag() != `"v2"` {
+		t.Errorf("Expected changed result, got %+v", result)
+	}
+}
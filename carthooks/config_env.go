@@ -0,0 +1,103 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfigFromEnv
+const (
+	EnvAPIURL       = "CARTHOOKS_API_URL"
+	EnvAccessToken  = "CARTHOOKS_ACCESS_TOKEN"
+	EnvClientID     = "CARTHOOKS_CLIENT_ID"
+	EnvClientSecret = "CARTHOOKS_CLIENT_SECRET"
+	EnvTimeout      = "CARTHOOKS_TIMEOUT"
+	EnvDebug        = "CARTHOOKS_SDK_DEBUG"
+)
+
+// LoadConfigFromEnv builds a ClientConfig from the standard environment
+// variables. Values are trimmed of whitespace and of surrounding quotes, as
+// left by some .env loaders. Credentials are required: either
+// CARTHOOKS_ACCESS_TOKEN, or CARTHOOKS_CLIENT_ID with CARTHOOKS_CLIENT_SECRET
+// for OAuth with AutoRefresh. CARTHOOKS_TIMEOUT is a duration such as "30s"
+// or a number of seconds. Every problem found is reported in the error,
+// naming its variable.
+func LoadConfigFromEnv() (*ClientConfig, error) {
+	config := &ClientConfig{}
+	var errs []error
+
+	if apiURL := envValue(EnvAPIURL); apiURL != "" {
+		u, err := url.Parse(apiURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s: %q is not an http(s) URL", EnvAPIURL, apiURL))
+		}
+		config.BaseURL = strings.TrimSuffix(apiURL, "/")
+	}
+
+	config.AccessToken = envValue(EnvAccessToken)
+	clientID, clientSecret := envValue(EnvClientID), envValue(EnvClientSecret)
+	switch {
+	case clientID != "" && clientSecret != "":
+		config.OAuth = &OAuthConfig{ClientID: clientID, ClientSecret: clientSecret, AutoRefresh: true}
+	case clientID != "":
+		errs = append(errs, fmt.Errorf("%s is set but %s is missing", EnvClientID, EnvClientSecret))
+	case clientSecret != "":
+		errs = append(errs, fmt.Errorf("%s is set but %s is missing", EnvClientSecret, EnvClientID))
+	case config.AccessToken == "":
+		errs = append(errs, fmt.Errorf("missing credentials: set %s, or %s and %s", EnvAccessToken, EnvClientID, EnvClientSecret))
+	}
+
+	if timeout := envValue(EnvTimeout); timeout != "" {
+		d, err := parseTimeout(timeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", EnvTimeout, err))
+		}
+		config.Timeout = d
+	}
+
+	if debug := envValue(EnvDebug); debug != "" {
+		enabled, err := strconv.ParseBool(debug)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %q is not a boolean", EnvDebug, debug))
+		}
+		config.Debug = enabled
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid environment configuration: %w", err)
+	}
+	return config, nil
+}
+
+// envValue returns an environment variable trimmed of whitespace and
+// surrounding quotes
+func envValue(key string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if len(value) >= 2 {
+		if first, last := value[0], value[len(value)-1]; first == last && (first == '"' || first == '\'') {
+			value = strings.TrimSpace(value[1 : len(value)-1])
+		}
+	}
+	return value
+}
+
+// parseTimeout parses a positive duration or number of seconds
+func parseTimeout(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.ParseFloat(value, 64)
+		if convErr != nil {
+			return 0, fmt.Errorf("%q is not a duration or number of seconds", value)
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q is not positive", value)
+	}
+	return d, nil
+}
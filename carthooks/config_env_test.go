@@ -0,0 +1,73 @@
+package carthooks
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvAPIURL, " https://api.example.com/ ")
+	t.Setenv(EnvAccessToken, "")
+	t.Setenv(EnvClientID, `"dvc-abc"`)
+	t.Setenv(EnvClientSecret, "'dvs-xyz'")
+	t.Setenv(EnvTimeout, "45")
+	t.Setenv(EnvDebug, "true")
+
+	config, err := LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv() returned %v", err)
+	}
+	if config.BaseURL != "https://api.example.com" || config.Timeout != 45*time.Second || !config.Debug {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if config.OAuth == nil || config.OAuth.ClientID != "dvc-abc" || config.OAuth.ClientSecret != "dvs-xyz" || !config.OAuth.AutoRefresh {
+		t.Errorf("Unexpected OAuth config %+v", config.OAuth)
+	}
+
+	t.Setenv(EnvTimeout, "1m30s")
+	if config, err := LoadConfigFromEnv(); err != nil || config.Timeout != 90*time.Second {
+		t.Errorf("LoadConfigFromEnv() = %+v, %v", config, err)
+	}
+}
+
+func TestLoadConfigFromEnvAccessToken(t *testing.T) {
+	t.Setenv(EnvAPIURL, "")
+	t.Setenv(EnvAccessToken, "token\n")
+	t.Setenv(EnvClientID, "")
+	t.Setenv(EnvClientSecret, "")
+	t.Setenv(EnvTimeout, "")
+	t.Setenv(EnvDebug, "")
+
+	config, err := LoadConfigFromEnv()
+	if err != nil || config.AccessToken != "token" || config.OAuth != nil {
+		t.Errorf("LoadConfigFromEnv() = %+v, %v", config, err)
+	}
+}
+
+func TestLoadConfigFromEnvErrors(t *testing.T) {
+	t.Setenv(EnvAPIURL, "api.example.com")
+	t.Setenv(EnvAccessToken, "")
+	t.Setenv(EnvClientID, "dvc-abc")
+	t.Setenv(EnvClientSecret, `""`)
+	t.Setenv(EnvTimeout, "soon")
+	t.Setenv(EnvDebug, "maybe")
+
+	_, err := LoadConfigFromEnv()
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, want := range []string{EnvAPIURL, EnvClientSecret + " is missing", EnvTimeout, EnvDebug} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error %q does not mention %q", err, want)
+		}
+	}
+
+	t.Setenv(EnvAPIURL, "")
+	t.Setenv(EnvClientID, "")
+	t.Setenv(EnvTimeout, "")
+	t.Setenv(EnvDebug, "")
+	if _, err := LoadConfigFromEnv(); err == nil || !strings.Contains(err.Error(), "missing credentials") {
+		t.Errorf("LoadConfigFromEnv() = %v, want missing credentials", err)
+	}
+}
@@ -0,0 +1,116 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// connectionTokenMargin is how long before expiry a cached connection
+// token is exchanged again, matching EnsureValidToken
+const connectionTokenMargin = 5 * time.Minute
+
+// GetConnectionToken exchanges the client's OAuth credentials for a token
+// scoped to a connection, acting in the tenant that installed it. The
+// result's data decodes into OAuthTokens. The client's own token is left
+// unchanged; ConnectionToken caches the exchange per connection.
+func (c *Client) GetConnectionToken(connectionID uint) *Result {
+	if c.oauthConfig == nil {
+		return &Result{
+			Success: false,
+			Error:   "OAuth configuration not provided",
+		}
+	}
+
+	return c.GetOAuthToken(&OAuthTokenRequest{
+		GrantType:    "client_credentials",
+		ClientID:     c.oauthConfig.ClientID,
+		ClientSecret: c.oauthConfig.ClientSecret,
+		ConnectionID: connectionID,
+	})
+}
+
+// ConnectionToken returns a token scoped to a connection, exchanging the
+// client's credentials with GetConnectionToken the first time and again
+// when the cached token is within five minutes of expiring. It is safe for
+// concurrent use; concurrent calls for one connection share an exchange.
+func (c *Client) ConnectionToken(connectionID uint) (*OAuthTokens, error) {
+	entry := c.connectionTokens.entry(connectionID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.tokens != nil && (entry.expiresAt.IsZero() || time.Now().Add(connectionTokenMargin).Before(entry.expiresAt)) {
+		return entry.tokens, nil
+	}
+
+	var tokens OAuthTokens
+	if err := c.GetConnectionToken(connectionID).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to exchange token for connection %d: %w", connectionID, err)
+	}
+	if tokens.AccessToken == "" {
+		return nil, errors.New("token response has no access_token")
+	}
+
+	entry.tokens = &tokens
+	entry.expiresAt = time.Time{}
+	if tokens.ExpiresIn > 0 {
+		entry.expiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	}
+	return entry.tokens, nil
+}
+
+// ForConnection returns a copy of the client authenticated with the
+// connection's token from ConnectionToken, for calls made on behalf of the
+// installing tenant. The copy does not refresh the token itself; get a new
+// copy per unit of work, which is cheap while the token is cached.
+func (c *Client) ForConnection(connectionID uint) (*Client, error) {
+	tokens, err := c.ConnectionToken(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := c.WithAccessToken(tokens.AccessToken)
+	cp.currentTokens = tokens
+	return cp, nil
+}
+
+// InvalidateConnectionToken drops the cached token of a connection, e.g.
+// after the API rejected it, so the next ConnectionToken exchanges again
+func (c *Client) InvalidateConnectionToken(connectionID uint) {
+	c.connectionTokens.remove(connectionID)
+}
+
+// connectionTokenCache holds exchanged tokens per connection
+type connectionTokenCache struct {
+	mu      sync.Mutex
+	entries map[uint]*connectionTokenEntry
+}
+
+type connectionTokenEntry struct {
+	mu        sync.Mutex
+	tokens    *OAuthTokens
+	expiresAt time.Time
+}
+
+func newConnectionTokenCache() *connectionTokenCache {
+	return &connectionTokenCache{entries: map[uint]*connectionTokenEntry{}}
+}
+
+// entry returns the connection's entry, creating it if needed
+func (t *connectionTokenCache) entry(connectionID uint) *connectionTokenEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[connectionID]
+	if !ok {
+		entry = &connectionTokenEntry{}
+		t.entries[connectionID] = entry
+	}
+	return entry
+}
+
+func (t *connectionTokenCache) remove(connectionID uint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, connectionID)
+}
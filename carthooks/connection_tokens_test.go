@@ -0,0 +1,72 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_ConnectionToken(t *testing.T) {
+	var exchanges int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			atomic.AddInt32(&exchanges, 1)
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "dev-client" {
+				t.Errorf("Unexpected token request %v", r.Form)
+			}
+			fmt.Fprintf(w, `{"data":{"access_token":"token-%s","token_type":"Bearer","expires_in":3600}}`, r.Form.Get("connection_id"))
+		case "/v1/me":
+			fmt.Fprintf(w, `{"data":{"authorization":%q}}`, r.Header.Get("Authorization"))
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{
+		BaseURL:     server.URL,
+		AccessToken: "own-token",
+		OAuth:       &OAuthConfig{ClientID: "dev-client", ClientSecret: "secret"},
+	})
+
+	for i := 0; i < 3; i++ {
+		tokens, err := client.ConnectionToken(7)
+		if err != nil || tokens.AccessToken != "token-7" {
+			t.Fatalf("ConnectionToken(7) = %+v, %v", tokens, err)
+		}
+	}
+	if tokens, err := client.ConnectionToken(8); err != nil || tokens.AccessToken != "token-8" {
+		t.Fatalf("ConnectionToken(8) = %+v, %v", tokens, err)
+	}
+	if got := atomic.LoadInt32(&exchanges); got != 2 {
+		t.Errorf("Expected one exchange per connection, got %d", got)
+	}
+
+	scoped, err := client.ForConnection(7)
+	if err != nil {
+		t.Fatalf("ForConnection() returned %v", err)
+	}
+	var me map[string]string
+	scoped.GetCurrentUser().Decode(&me)
+	if me["authorization"] != "Bearer token-7" {
+		t.Errorf("Scoped client sent %q", me["authorization"])
+	}
+	client.GetCurrentUser().Decode(&me)
+	if me["authorization"] != "Bearer own-token" || client.GetCurrentTokens() != nil {
+		t.Errorf("Client's own token changed: sent %q", me["authorization"])
+	}
+
+	client.InvalidateConnectionToken(7)
+	client.ConnectionToken(7)
+	if got := atomic.LoadInt32(&exchanges); got != 3 {
+		t.Errorf("Expected an exchange after invalidation, got %d", got)
+	}
+}
+
+func TestClient_ConnectionTokenWithoutOAuth(t *testing.T) {
+	client := NewClient(&ClientConfig{BaseURL: "http://localhost"})
+	if _, err := client.ConnectionToken(7); err == nil {
+		t.Error("Expected an error without OAuth configuration")
+	}
+}
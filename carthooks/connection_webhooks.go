@@ -0,0 +1,69 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ConnectionWebhook is a webhook registered for a connection. It delivers
+// the installing tenant's events to the endpoint, signed with the secret,
+// without a watch per collection.
+type ConnectionWebhook struct {
+	WebhookID    string      `json:"webhook_id"`
+	ConnectionID uint        `json:"connection_id"`
+	EndpointURL  string      `json:"endpoint_url"`
+	Events       []EventCode `json:"events"`
+	// CollectionIDs limits deliveries to these collections; empty means
+	// every collection the connection can access
+	CollectionIDs []uint `json:"collection_ids,omitempty"`
+	Status        string `json:"status"`
+	CreatedAt     int64  `json:"created_at"` // Unix timestamp in seconds
+}
+
+// RegisterConnectionWebhookRequest represents the request body for registering a connection webhook
+type RegisterConnectionWebhookRequest struct {
+	EndpointURL string      `json:"endpoint_url"`
+	Events      []EventCode `json:"events"`
+	// Secret signs deliveries; verify them with NewWebhookHandler
+	Secret        string `json:"secret,omitempty"`
+	CollectionIDs []uint `json:"collection_ids,omitempty"`
+}
+
+// RegisterConnectionWebhook subscribes an endpoint to events in the tenant
+// that installed a connection. The result's data is the ConnectionWebhook.
+func (c *Client) RegisterConnectionWebhook(appID, connectionID uint, request *RegisterConnectionWebhookRequest) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections/%d/webhooks", appID, connectionID)
+
+	resp, err := c.makeRequest("POST", path, request, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// ListConnectionWebhooks lists the webhooks registered for a connection.
+// The result's data decodes into []ConnectionWebhook.
+func (c *Client) ListConnectionWebhooks(appID, connectionID uint) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections/%d/webhooks", appID, connectionID)
+
+	resp, err := c.makeRequest("GET", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// UnregisterConnectionWebhook removes a connection webhook; no further
+// events are delivered to its endpoint
+func (c *Client) UnregisterConnectionWebhook(appID, connectionID uint, webhookID string) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections/%d/webhooks/%s", appID, connectionID, url.PathEscape(webhookID))
+
+	resp, err := c.makeRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
@@ -0,0 +1,52 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ConnectionWebhooks(t *testing.T) {
+	var registered RegisterConnectionWebhookRequest
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/apps/1/connections/5/webhooks":
+			json.NewDecoder(r.Body).Decode(&registered)
+			fmt.Fprint(w, `{"data":{"webhook_id":"wh-1","connection_id":5,"endpoint_url":"https://hooklet.example.com/events","events":["collection.item.created"],"status":"active"}}`)
+		case "GET /v1/apps/1/connections/5/webhooks":
+			fmt.Fprint(w, `{"data":[{"webhook_id":"wh-1","connection_id":5,"events":["collection.item.created"]}]}`)
+		case "DELETE /v1/apps/1/connections/5/webhooks/wh-1":
+			deleted = true
+			fmt.Fprint(w, `{"data":null}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var webhook ConnectionWebhook
+	err := client.RegisterConnectionWebhook(1, 5, &RegisterConnectionWebhookRequest{
+		EndpointURL: "https://hooklet.example.com/events",
+		Events:      []EventCode{EventCodeRecordCreated},
+		Secret:      "s3cret",
+	}).Decode(&webhook)
+	if err != nil || webhook.WebhookID != "wh-1" {
+		t.Fatalf("RegisterConnectionWebhook() = %+v, %v", webhook, err)
+	}
+	if registered.Secret != "s3cret" || len(registered.Events) != 1 || registered.Events[0] != EventCodeRecordCreated {
+		t.Errorf("Unexpected registration %+v", registered)
+	}
+
+	var webhooks []ConnectionWebhook
+	if err := client.ListConnectionWebhooks(1, 5).Decode(&webhooks); err != nil || len(webhooks) != 1 {
+		t.Fatalf("ListConnectionWebhooks() = %+v, %v", webhooks, err)
+	}
+
+	if err := client.UnregisterConnectionWebhook(1, 5, "wh-1").Err(); err != nil || !deleted {
+		t.Errorf("UnregisterConnectionWebhook() = %v, deleted %v", err, deleted)
+	}
+}
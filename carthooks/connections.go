@@ -0,0 +1,130 @@
+package carthooks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ListConnections lists an app's connections. Filters are sent as
+// filters[key] query parameters, e.g. {"hooklet_id": "12", "status": "1"};
+// a nil pagination returns the API's first page. The result's data decodes
+// into []Connection.
+func (c *Client) ListConnections(appID uint, filters map[string]string, pagination *PaginationOptions) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections", appID)
+
+	params := paginationParams(pagination)
+	for k, v := range filters {
+		params["filters["+k+"]"] = v
+	}
+
+	return c.readRequest("GET", path, nil, params)
+}
+
+// GetConnectionLogs lists a connection's log entries, newest first. A zero
+// status returns entries of every status. The result's data decodes into
+// []ConnectionLog.
+func (c *Client) GetConnectionLogs(appID, connectionID uint, status ConnectionLogStatus, pagination *PaginationOptions) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections/%d/logs", appID, connectionID)
+
+	params := paginationParams(pagination)
+	if status != 0 {
+		params["filters[status]"] = strconv.Itoa(int(status))
+	}
+
+	return c.readRequest("GET", path, nil, params)
+}
+
+// GetConnectionUsage aggregates a connection's usage records from from
+// (inclusive) to to (exclusive) into periods of the given granularity. The
+// result's data decodes into []ConnectionUsagePoint, oldest period first.
+func (c *Client) GetConnectionUsage(appID, connectionID uint, from, to time.Time, granularity UsageGranularity) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/connections/%d/usage", appID, connectionID)
+
+	params := map[string]string{
+		"from": strconv.FormatInt(from.Unix(), 10),
+		"to":   strconv.FormatInt(to.Unix(), 10),
+	}
+	if granularity != "" {
+		params["granularity"] = string(granularity)
+	}
+
+	return c.readRequest("GET", path, nil, params)
+}
+
+// SetConnectionStatus activates or deactivates a connection. Only
+// ConnectionStatusActive and ConnectionStatusInactive can be set; pending
+// is the state of connections that were never activated.
+func (c *Client) SetConnectionStatus(appID, connectionID uint, status ConnectionStatus) *Result {
+	var value string
+	switch status {
+	case ConnectionStatusActive:
+		value = "active"
+	case ConnectionStatusInactive:
+		value = "inactive"
+	default:
+		return requestFailed(fmt.Errorf("cannot set connection status to %s", status))
+	}
+
+	return c.UpdateConnection(appID, connectionID, &UpdateConnectionRequest{Status: value})
+}
+
+// Connection returns the result's connection, or its error if it failed
+func (r *Result) Connection() (*Connection, error) {
+	var connection Connection
+	if err := r.Decode(&connection); err != nil {
+		return nil, err
+	}
+	return &connection, nil
+}
+
+// Connections returns the result's connections, or its error if it failed,
+// as in connections, err := client.ListConnections(...).Connections()
+func (r *Result) Connections() ([]Connection, error) {
+	var connections []Connection
+	if err := r.Decode(&connections); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// IsActive reports whether the connection is active
+func (c Connection) IsActive() bool {
+	return ConnectionStatus(c.Status) == ConnectionStatusActive
+}
+
+// IsPending reports whether the connection has not been activated yet
+func (c Connection) IsPending() bool {
+	return ConnectionStatus(c.Status) == ConnectionStatusPending
+}
+
+// String returns the status name, e.g. "active"
+func (s ConnectionStatus) String() string {
+	switch s {
+	case ConnectionStatusPending:
+		return "pending"
+	case ConnectionStatusActive:
+		return "active"
+	case ConnectionStatusInactive:
+		return "inactive"
+	}
+	return fmt.Sprintf("ConnectionStatus(%d)", uint8(s))
+}
+
+// paginationParams converts pagination to query parameters
+func paginationParams(pagination *PaginationOptions) map[string]string {
+	params := map[string]string{}
+	if pagination == nil {
+		return params
+	}
+	if pagination.Page > 0 {
+		params["pagination[page]"] = strconv.Itoa(pagination.Page)
+	}
+	if pagination.PageSize > 0 {
+		params["pagination[pageSize]"] = strconv.Itoa(pagination.PageSize)
+	}
+	if pagination.WithCount {
+		params["pagination[withCount]"] = "true"
+	}
+	return params
+}
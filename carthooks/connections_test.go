@@ -0,0 +1,116 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ListConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/v1/apps/1/connections" || query.Get("filters[status]") != "1" || query.Get("pagination[page]") != "2" || query.Get("pagination[pageSize]") != "10" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"data":[{"id":5,"title":"CRM","status":1},{"id":6,"title":"ERP","status":1}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	result := client.ListConnections(1, map[string]string{"status": "1"}, &PaginationOptions{Page: 2, PageSize: 10})
+	var connections []Connection
+	if err := result.Decode(&connections); err != nil {
+		t.Fatalf("Decode() returned %v", err)
+	}
+	if len(connections) != 2 || connections[1].Title != "ERP" {
+		t.Errorf("Unexpected connections %+v", connections)
+	}
+}
+
+func TestClient_GetConnectionLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/apps/1/connections/5/logs" || r.URL.Query().Get("filters[status]") != "4" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"data":[{"id":9,"connection_id":5,"status":4,"message":"sync failed"}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var logs []ConnectionLog
+	if err := client.GetConnectionLogs(1, 5, ConnectionLogStatusError, nil).Decode(&logs); err != nil {
+		t.Fatalf("Decode() returned %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "sync failed" || ConnectionLogStatus(logs[0].Status) != ConnectionLogStatusError {
+		t.Errorf("Unexpected logs %+v", logs)
+	}
+}
+
+func TestClient_GetConnectionUsage(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != "GET" || r.URL.Path != "/v1/apps/1/connections/5/usage" || query.Get("from") != "1709251200" || query.Get("to") != "1709424000" || query.Get("granularity") != "day" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		fmt.Fprint(w, `{"data":[{"period_start":1709251200,"usage":120},{"period_start":1709337600,"usage":80}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var usage []ConnectionUsagePoint
+	if err := client.GetConnectionUsage(1, 5, from, to, UsageGranularityDay).Decode(&usage); err != nil {
+		t.Fatalf("Decode() returned %v", err)
+	}
+	if len(usage) != 2 || usage[0].Usage != 120 || usage[1].PeriodStart != 1709337600 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+}
+
+func TestClient_SetConnectionStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.Method != "PUT" || r.URL.Path != "/v1/apps/1/connections/5" || body["status"] != "inactive" {
+			t.Errorf("Unexpected request %s %s %v", r.Method, r.URL, body)
+		}
+		fmt.Fprint(w, `{"data":{"id":5,"title":"CRM","status":2}}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	connection, err := client.SetConnectionStatus(1, 5, ConnectionStatusInactive).Connection()
+	if err != nil {
+		t.Fatalf("Connection() returned %v", err)
+	}
+	if connection.ID != 5 || connection.IsActive() || ConnectionStatus(connection.Status) != ConnectionStatusInactive {
+		t.Errorf("Unexpected connection %+v", connection)
+	}
+
+	if result := client.SetConnectionStatus(1, 5, ConnectionStatusPending); result.Err() == nil {
+		t.Error("Expected an error setting a connection to pending")
+	}
+}
+
+func TestResult_Connections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":5,"status":1},{"id":6,"status":0}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	connections, err := client.ListConnections(1, nil, nil).Connections()
+	if err != nil {
+		t.Fatalf("Connections() returned %v", err)
+	}
+	if len(connections) != 2 || !connections[0].IsActive() || !connections[1].IsPending() {
+		t.Errorf("Unexpected connections %+v", connections)
+	}
+	if got := ConnectionStatusInactive.String(); got != "inactive" {
+		t.Errorf("String() = %q, want %q", got, "inactive")
+	}
+}
@@ -0,0 +1,86 @@
+package carthooks
+
+import (
+	_ "embed"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// coverageManifest lists every API endpoint wrapped by this SDK. It must be
+// updated whenever an endpoint is added; cmd/coverage-check diffs it against
+// the platform OpenAPI spec.
+//
+//go:embed coverage.json
+var coverageManifest []byte
+
+// CoverageEndpoint is an API endpoint and the SDK methods that wrap it
+type CoverageEndpoint struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	SDKMethods []string `json:"sdk_methods,omitempty"`
+}
+
+// CoverageReport describes which API endpoints the SDK supports
+type CoverageReport struct {
+	SDK       string             `json:"sdk"`
+	Endpoints []CoverageEndpoint `json:"endpoints"`
+}
+
+// Coverage returns the endpoint coverage report for this SDK, so callers can
+// check whether an endpoint is supported before building on it
+func Coverage() *CoverageReport {
+	var report CoverageReport
+	if err := json.Unmarshal(coverageManifest, &report); err != nil {
+		// The manifest is embedded at build time and checked by tests
+		panic("carthooks: invalid coverage manifest: " + err.Error())
+	}
+	return &report
+}
+
+// Supports reports whether the SDK wraps the given endpoint. Path parameter
+// names are ignored, so "/v1/apps/{id}" matches "/v1/apps/{app_id}".
+func (r *CoverageReport) Supports(method, path string) bool {
+	return r.find(method, path) != nil
+}
+
+// SDKMethods returns the SDK methods wrapping the given endpoint, if any
+func (r *CoverageReport) SDKMethods(method, path string) []string {
+	if endpoint := r.find(method, path); endpoint != nil {
+		return endpoint.SDKMethods
+	}
+	return nil
+}
+
+// Missing returns the endpoints that are not wrapped by the SDK
+func (r *CoverageReport) Missing(endpoints []CoverageEndpoint) []CoverageEndpoint {
+	var missing []CoverageEndpoint
+	for _, endpoint := range endpoints {
+		if !r.Supports(endpoint.Method, endpoint.Path) {
+			missing = append(missing, endpoint)
+		}
+	}
+	return missing
+}
+
+func (r *CoverageReport) find(method, path string) *CoverageEndpoint {
+	method = strings.ToUpper(method)
+	path = normalizeCoveragePath(path)
+	for i := range r.Endpoints {
+		if r.Endpoints[i].Method == method && normalizeCoveragePath(r.Endpoints[i].Path) == path {
+			return &r.Endpoints[i]
+		}
+	}
+	return nil
+}
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizeCoveragePath strips path parameter names and trailing slashes
+func normalizeCoveragePath(path string) string {
+	path = pathParamPattern.ReplaceAllString(path, "{}")
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
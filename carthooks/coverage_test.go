@@ -0,0 +1,51 @@
+package carthooks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverageManifest(t *testing.T) {
+	report := Coverage()
+	if len(report.Endpoints) == 0 {
+		t.Fatal("Coverage manifest has no endpoints")
+	}
+
+	clientType := reflect.TypeOf(&Client{})
+	seen := map[string]bool{}
+	for _, endpoint := range report.Endpoints {
+		key := endpoint.Method + " " + normalizeCoveragePath(endpoint.Path)
+		if seen[key] {
+			t.Errorf("Duplicate manifest entry %s", key)
+		}
+		seen[key] = true
+
+		if len(endpoint.SDKMethods) == 0 {
+			t.Errorf("Manifest entry %s lists no SDK methods", key)
+		}
+		for _, name := range endpoint.SDKMethods {
+			if _, ok := clientType.MethodByName(name); !ok {
+				t.Errorf("Manifest entry %s references unknown method %s", key, name)
+			}
+		}
+	}
+}
+
+func TestCoverageReport_Supports(t *testing.T) {
+	report := Coverage()
+
+	if !report.Supports("get", "/v1/apps/{id}/collections/{cid}/items/") {
+		t.Error("Expected GetItems endpoint to be supported regardless of parameter names")
+	}
+	if report.Supports("PATCH", "/v1/apps/{id}") {
+		t.Error("Expected PATCH /v1/apps/{id} to be unsupported")
+	}
+
+	missing := report.Missing([]CoverageEndpoint{
+		{Method: "GET", Path: "/v1/apps"},
+		{Method: "GET", Path: "/v1/unknown"},
+	})
+	if len(missing) != 1 || missing[0].Path != "/v1/unknown" {
+		t.Errorf("Unexpected missing endpoints %+v", missing)
+	}
+}
@@ -0,0 +1,144 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// DeadLetterSink receives poison messages: messages that failed processing
+// MaxAttempts times
+type DeadLetterSink interface {
+	DeadLetter(ctx context.Context, message *Message, cause error) error
+}
+
+// SQSDeadLetterQueue is a DeadLetterSink that sends poison messages to an
+// SQS queue, with the failure recorded in the CarthooksError message
+// attribute
+type SQSDeadLetterQueue struct {
+	client   *sqs.Client
+	queueURL string
+}
+
+// DeadLetterTo returns a sink that sends poison messages to queueURL using
+// the source's SQS client
+func (s *SQSSource) DeadLetterTo(queueURL string) *SQSDeadLetterQueue {
+	return &SQSDeadLetterQueue{client: s.client, queueURL: queueURL}
+}
+
+// DeadLetter sends message to the dead-letter queue
+func (q *SQSDeadLetterQueue) DeadLetter(ctx context.Context, message *Message, cause error) error {
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: aws.String(string(message.Body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"CarthooksError": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(cause.Error()),
+			},
+			"CarthooksMessageId": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(message.ID),
+			},
+		},
+	}
+	if IsFIFOQueue(q.queueURL) {
+		groupID := message.GroupID
+		if groupID == "" {
+			groupID = "dead-letter"
+		}
+		input.MessageGroupId = aws.String(groupID)
+		input.MessageDeduplicationId = aws.String(dedupeID(message))
+	}
+
+	if _, err := q.client.SendMessage(ctx, input); err != nil {
+		return fmt.Errorf("failed to send message to dead-letter queue: %w", err)
+	}
+	return nil
+}
+
+// fail handles a message that failed processing. Below MaxAttempts it is
+// nacked for redelivery; after that it is passed to OnPoisonMessage and the
+// DeadLetter sink and acknowledged, so it stops cycling through the queue.
+// It reports whether the message was dead-lettered.
+func (w *Watcher) fail(ctx context.Context, message *Message, cause error, acks *acker) bool {
+	w.metrics.MessageFailed()
+	attempts := w.recordFailure(message)
+	if w.config.MaxAttempts <= 0 || attempts < w.config.MaxAttempts {
+		w.nack(ctx, message)
+		return false
+	}
+
+	if w.config.DeadLetter != nil {
+		if err := w.config.DeadLetter.DeadLetter(ctx, message, cause); err != nil {
+			w.logger.Error("failed to dead-letter message", "message_id", message.ID, "error", err)
+			w.nack(ctx, message)
+			return false
+		}
+	}
+	if w.config.OnPoisonMessage != nil {
+		w.config.OnPoisonMessage(message, cause)
+	}
+
+	w.logger.Warn("message dead-lettered", "message_id", message.ID, "attempts", attempts)
+	w.clearFailures(message)
+	acks.add(ctx, message)
+	return true
+}
+
+// failureTTL is how long a locally tracked failure count is kept after the
+// message last failed. Messages that go on to succeed on another consumer,
+// or expire from the queue, are never acked here, so their counts are
+// dropped once the source would have redelivered them long since.
+const failureTTL = time.Hour
+
+// failureCount is how many times a message has failed locally, and when
+type failureCount struct {
+	count  int
+	failed time.Time
+}
+
+// recordFailure returns how many times message has now failed, using the
+// source's receive count when it reports one
+func (w *Watcher) recordFailure(message *Message) int {
+	if message.ReceiveCount > 0 {
+		return message.ReceiveCount
+	}
+
+	w.failuresMu.Lock()
+	defer w.failuresMu.Unlock()
+
+	now := time.Now()
+	for id, f := range w.failures {
+		if now.Sub(f.failed) > failureTTL {
+			delete(w.failures, id)
+		}
+	}
+
+	if w.failures == nil {
+		w.failures = map[string]*failureCount{}
+	}
+	f, ok := w.failures[dedupeID(message)]
+	if !ok {
+		f = &failureCount{}
+		w.failures[dedupeID(message)] = f
+	}
+	f.count++
+	f.failed = now
+	return f.count
+}
+
+// clearFailures forgets the failure count of a message tracked locally
+func (w *Watcher) clearFailures(message *Message) {
+	if message.ReceiveCount > 0 {
+		return
+	}
+
+	w.failuresMu.Lock()
+	defer w.failuresMu.Unlock()
+	delete(w.failures, dedupeID(message))
+}
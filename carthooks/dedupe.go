@@ -0,0 +1,74 @@
+package carthooks
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultDedupeSize is how many message IDs the default in-memory store
+// remembers
+const defaultDedupeSize = 1000
+
+// DedupeStore records processed messages so the Watcher can skip
+// redeliveries of at-least-once sources. Use a shared store (e.g.
+// redisdedupe) when several watcher instances consume the same queue.
+type DedupeStore interface {
+	// Seen reports whether messageID was already processed
+	Seen(ctx context.Context, messageID string) (bool, error)
+	// Mark records messageID as processed
+	Mark(ctx context.Context, messageID string) error
+}
+
+// MemoryDedupeStore is a DedupeStore that remembers a fixed number of the
+// most recently processed message IDs. It is safe for concurrent use.
+type MemoryDedupeStore struct {
+	mu    sync.Mutex
+	size  int
+	ids   map[string]struct{}
+	order []string
+}
+
+// NewMemoryDedupeStore creates an in-memory store remembering up to size
+// message IDs (default 1000)
+func NewMemoryDedupeStore(size int) *MemoryDedupeStore {
+	if size <= 0 {
+		size = defaultDedupeSize
+	}
+	return &MemoryDedupeStore{
+		size: size,
+		ids:  make(map[string]struct{}, size),
+	}
+}
+
+// Seen reports whether messageID was recently processed
+func (s *MemoryDedupeStore) Seen(ctx context.Context, messageID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[messageID]
+	return ok, nil
+}
+
+// Mark records messageID as processed, evicting the oldest ID when full
+func (s *MemoryDedupeStore) Mark(ctx context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[messageID]; ok {
+		return nil
+	}
+	if len(s.order) >= s.size {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.ids[messageID] = struct{}{}
+	s.order = append(s.order, messageID)
+	return nil
+}
+
+// dedupeID returns the ID a message is deduplicated by: its
+// DeduplicationID if the source provides one, otherwise its ID
+func dedupeID(message *Message) string {
+	if message.DeduplicationID != "" {
+		return message.DeduplicationID
+	}
+	return message.ID
+}
@@ -0,0 +1,44 @@
+package carthooks
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryDedupeStore_Evicts(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupeStore(2)
+
+	store.Mark(ctx, "a")
+	store.Mark(ctx, "b")
+	store.Mark(ctx, "c")
+
+	if seen, _ := store.Seen(ctx, "a"); seen {
+		t.Error("Expected oldest ID to be evicted")
+	}
+	for _, id := range []string{"b", "c"} {
+		if seen, _ := store.Seen(ctx, id); !seen {
+			t.Errorf("Expected %s to be remembered", id)
+		}
+	}
+}
+
+func TestWatcher_DedupesByMessageID(t *testing.T) {
+	source := &recordingSource{}
+	calls := 0
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source: source,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			calls++
+			return nil
+		},
+	})
+
+	message := &Message{ID: "sqs-1", Body: []byte(`{"payload":{"id":1}}`)}
+	watcher.processBatch(context.Background(), []*Message{message})
+	watcher.processBatch(context.Background(), []*Message{message})
+
+	if calls != 1 || len(source.acked) != 2 {
+		t.Errorf("Expected one handler call and two acks, got %d calls, acked=%v", calls, source.acked)
+	}
+}
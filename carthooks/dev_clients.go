@@ -0,0 +1,80 @@
+package carthooks
+
+import (
+	"fmt"
+	"time"
+)
+
+// DevClient is a dev client credential: a "dvc-" client ID and its "dvs-"
+// secret, used as OAuthConfig.ClientID and ClientSecret
+type DevClient struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	ClientID string `json:"client_id"`
+	// ClientSecret is only returned by CreateDevClient and
+	// RotateDevClientSecret; store it then, it cannot be read back
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	CreatedAt    int64    `json:"created_at"` // Unix timestamp in seconds
+	RotatedAt    int64    `json:"rotated_at"` // Unix timestamp in seconds
+	// PreviousSecretExpiresAt is when the secret replaced by the last
+	// rotation stops working
+	PreviousSecretExpiresAt int64 `json:"previous_secret_expires_at,omitempty"`
+}
+
+// CreateDevClientRequest represents the request body for creating a dev client
+type CreateDevClientRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// CreateDevClient creates a dev client credential. The result's data is
+// the DevClient, including its secret.
+func (c *Client) CreateDevClient(request *CreateDevClientRequest) *Result {
+	resp, err := c.makeRequest("POST", "/v1/dev-clients", request, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// ListDevClients lists the tenant's dev clients, without their secrets.
+// The result's data decodes into []DevClient.
+func (c *Client) ListDevClients() *Result {
+	resp, err := c.makeRequest("GET", "/v1/dev-clients", nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// RotateDevClientSecret issues a new secret for a dev client. The previous
+// secret keeps working for gracePeriod, so running services can switch over
+// without downtime; zero revokes it immediately. The result's data is the
+// DevClient, including its new secret.
+func (c *Client) RotateDevClientSecret(devClientID uint, gracePeriod time.Duration) *Result {
+	path := fmt.Sprintf("/v1/dev-clients/%d/rotate", devClientID)
+
+	body := map[string]interface{}{"grace_period": int64(gracePeriod / time.Second)}
+	resp, err := c.makeRequest("POST", path, body, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// RevokeDevClient deletes a dev client. Its secrets stop working and the
+// tokens issued to it are revoked.
+func (c *Client) RevokeDevClient(devClientID uint) *Result {
+	path := fmt.Sprintf("/v1/dev-clients/%d", devClientID)
+
+	resp, err := c.makeRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
@@ -0,0 +1,52 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_DevClients(t *testing.T) {
+	var rotation map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/dev-clients":
+			fmt.Fprint(w, `{"data":{"id":3,"name":"ci","client_id":"dvc-abc","client_secret":"dvs-one"}}`)
+		case "GET /v1/dev-clients":
+			fmt.Fprint(w, `{"data":[{"id":3,"name":"ci","client_id":"dvc-abc"}]}`)
+		case "POST /v1/dev-clients/3/rotate":
+			json.NewDecoder(r.Body).Decode(&rotation)
+			fmt.Fprint(w, `{"data":{"id":3,"name":"ci","client_id":"dvc-abc","client_secret":"dvs-two","previous_secret_expires_at":1700003600}}`)
+		case "DELETE /v1/dev-clients/3":
+			fmt.Fprint(w, `{"data":null}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var dev DevClient
+	if err := client.CreateDevClient(&CreateDevClientRequest{Name: "ci"}).Decode(&dev); err != nil || dev.ClientSecret != "dvs-one" {
+		t.Fatalf("CreateDevClient() = %+v, %v", dev, err)
+	}
+
+	var devs []DevClient
+	if err := client.ListDevClients().Decode(&devs); err != nil || len(devs) != 1 || devs[0].ClientSecret != "" {
+		t.Fatalf("ListDevClients() = %+v, %v", devs, err)
+	}
+
+	if err := client.RotateDevClientSecret(dev.ID, time.Hour).Decode(&dev); err != nil || dev.ClientSecret != "dvs-two" {
+		t.Fatalf("RotateDevClientSecret() = %+v, %v", dev, err)
+	}
+	if rotation["grace_period"] != float64(3600) {
+		t.Errorf("Unexpected rotation body %v", rotation)
+	}
+
+	if err := client.RevokeDevClient(dev.ID).Err(); err != nil {
+		t.Errorf("RevokeDevClient() returned %v", err)
+	}
+}
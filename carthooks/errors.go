@@ -0,0 +1,129 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Errors matched (via errors.Is) by the error Result.Err returns, according
+// to the HTTP status of the API's response
+var (
+	// ErrNotFound is matched by 404 Not Found responses
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is matched by 401 Unauthorized responses, e.g. for an
+	// expired access token
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is matched by 403 Forbidden responses
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is matched by 409 Conflict responses
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited is matched by 429 Too Many Requests responses
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation is matched by 400 Bad Request and 422 Unprocessable
+	// Entity responses
+	ErrValidation = errors.New("validation failed")
+	// ErrQuotaExceeded is matched by QUOTA_EXCEEDED errors, and returned by
+	// QuotaStatus.Check
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// ErrorCode is a machine-readable error code returned by the API in
+// error.code
+type ErrorCode string
+
+// Known API error codes. The API may return codes not listed here.
+const (
+	ErrorCodeNotFound        ErrorCode = "NOT_FOUND"
+	ErrorCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
+	ErrorCodeTokenExpired    ErrorCode = "TOKEN_EXPIRED"
+	ErrorCodeForbidden       ErrorCode = "FORBIDDEN"
+	ErrorCodeConflict        ErrorCode = "CONFLICT"
+	ErrorCodeRateLimited     ErrorCode = "RATE_LIMITED"
+	ErrorCodeValidation      ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeInvalidRequest  ErrorCode = "INVALID_REQUEST"
+	ErrorCodeLocked          ErrorCode = "LOCKED"
+	ErrorCodeItemLocked      ErrorCode = "ITEM_LOCKED"
+	ErrorCodeInternal        ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeUnavailable     ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrorCodeQuotaExceeded   ErrorCode = "QUOTA_EXCEEDED"
+	ErrorCodeDuplicateRecord ErrorCode = "DUPLICATE_RECORD"
+)
+
+// sentinel returns the sentinel error for a known code, or nil
+func (c ErrorCode) sentinel() error {
+	switch c {
+	case ErrorCodeNotFound:
+		return ErrNotFound
+	case ErrorCodeUnauthorized, ErrorCodeTokenExpired:
+		return ErrUnauthorized
+	case ErrorCodeForbidden:
+		return ErrForbidden
+	case ErrorCodeConflict, ErrorCodeDuplicateRecord:
+		return ErrConflict
+	case ErrorCodeRateLimited:
+		return ErrRateLimited
+	case ErrorCodeValidation, ErrorCodeInvalidRequest:
+		return ErrValidation
+	case ErrorCodeQuotaExceeded:
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// parseErrorCode returns the error code from its JSON value, which the API
+// sends as a string but some endpoints send as a number
+func parseErrorCode(raw json.RawMessage) ErrorCode {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var code string
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return ErrorCode(code)
+	}
+	return ErrorCode(raw)
+}
+
+// APIError is the error Result.Err returns for an error response from the
+// API. Use errors.As to read its details, or errors.Is with one of the
+// sentinel errors, e.g. errors.Is(err, carthooks.ErrNotFound).
+type APIError struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	TraceID    string
+	RequestID  string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the sentinel error for the response status
+// or error code
+func (e *APIError) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+	return target == statusError(e.StatusCode) || target == e.Code.sentinel()
+}
+
+// statusError returns the sentinel error for an HTTP status, or nil
+func statusError(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResult_ErrSentinels(t *testing.T) {
+	statuses := map[int]error{
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusConflict:            ErrConflict,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusBadRequest:          ErrValidation,
+		http.StatusUnprocessableEntity: ErrValidation,
+	}
+
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"error":{"message":"request failed","code":"E1"},"trace_id":"trace-1"}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	for status = range statuses {
+		err := client.GetApps().Err()
+		if !errors.Is(err, statuses[status]) {
+			t.Errorf("status %d: errors.Is(%v, %v) = false", status, err, statuses[status])
+		}
+		if errors.Is(err, ErrConflict) && status != http.StatusConflict {
+			t.Errorf("status %d: unexpectedly matched ErrConflict", status)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status || apiErr.Code != "E1" || apiErr.TraceID != "trace-1" || apiErr.Message != "request failed" {
+			t.Errorf("status %d: errors.As = %+v", status, apiErr)
+		}
+	}
+
+	status = http.StatusInternalServerError
+	if err := client.GetApps().Err(); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("status 500: Err() = %v", err)
+	}
+}
+
+func TestResult_ErrorCode(t *testing.T) {
+	bodies := []string{
+		`{"error":{"message":"no such item","code":"NOT_FOUND"}}`,
+		`{"error":{"message":"bad field","code":40001}}`,
+	}
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	body = bodies[0]
+	result := client.GetApps()
+	if result.ErrorCode != ErrorCodeNotFound || !errors.Is(result.Err(), ErrNotFound) {
+		t.Errorf("ErrorCode = %q, Err() = %v", result.ErrorCode, result.Err())
+	}
+
+	body = bodies[1]
+	result = client.GetApps()
+	if result.ErrorCode != "40001" || result.Error != "bad field" {
+		t.Errorf("ErrorCode = %q, Error = %q", result.ErrorCode, result.Error)
+	}
+}
+
+func TestResult_ErrWithoutErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		status    int
+		body      string
+		sentinel  error
+		message   string
+		retryable bool
+	}{
+		{http.StatusNotFound, `{}`, ErrNotFound, "404 Not Found", false},
+		{http.StatusTooManyRequests, `{"message":"slow down"}`, ErrRateLimited, "slow down", true},
+		{http.StatusServiceUnavailable, ``, nil, "503 Service Unavailable", true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			result := NewClient(&ClientConfig{BaseURL: server.URL}).GetApp(1)
+			err := result.Err()
+			var apiErr *APIError
+			if result.Success || !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("Expected an *APIError for status %d, got %+v (%v)", tt.status, result, err)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("Expected %v to match %v", err, tt.sentinel)
+			}
+			if result.Error != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, result.Error)
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("IsRetryable() = %t, want %t", IsRetryable(err), tt.retryable)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// RecordCreatedPayload is the payload of collection.item.created events
+type RecordCreatedPayload struct {
+	RecordFormat
+}
+
+// RecordUpdatedPayload is the payload of collection.item.updated events
+type RecordUpdatedPayload struct {
+	RecordFormat
+	// Previous holds the values of changed fields before the update, where
+	// the API provides them
+	Previous map[string]interface{} `json:"previous,omitempty"`
+}
+
+// ChangedFields returns the names of fields with a previous value, sorted
+func (p *RecordUpdatedPayload) ChangedFields() []string {
+	fields := make([]string, 0, len(p.Previous))
+	for field := range p.Previous {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// RecordDeletedPayload is the payload of collection.item.deleted events
+type RecordDeletedPayload struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	DeletedAt int64  `json:"deleted_at"`
+	Deleter   uint   `json:"deleter"`
+}
+
+// FieldChangedPayload is the payload of collection.item.field_changed
+// events, sent once per changed field
+type FieldChangedPayload struct {
+	RecordFormat
+	Field    string      `json:"field"`
+	Value    interface{} `json:"value"`
+	Previous interface{} `json:"previous"`
+}
+
+// CommentAddedPayload is the payload of collection.item.comment_added events
+type CommentAddedPayload struct {
+	ID        uint   `json:"id"`
+	ItemID    uint   `json:"item_id"`
+	Content   string `json:"content"`
+	Author    uint   `json:"author"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// WorkflowPayload is the payload of workflow.started, workflow.completed and
+// workflow.failed events. ID identifies the workflow run.
+type WorkflowPayload struct {
+	ID         uint   `json:"id"`
+	WorkflowID uint   `json:"workflow_id"`
+	ItemID     uint   `json:"item_id"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	StartedAt  int64  `json:"started_at"`
+	FinishedAt int64  `json:"finished_at,omitempty"`
+}
+
+// TypedPayload decodes the payload into the struct for the event's code,
+// e.g. a *RecordDeletedPayload for collection.item.deleted. Unknown codes
+// return an error.
+func (e *EventMessage) TypedPayload() (interface{}, error) {
+	var payload interface{}
+	switch e.Meta.Event {
+	case EventCodeRecordCreated:
+		payload = &RecordCreatedPayload{}
+	case EventCodeRecordUpdated:
+		payload = &RecordUpdatedPayload{}
+	case EventCodeRecordDeleted:
+		payload = &RecordDeletedPayload{}
+	case EventCodeFieldChanged:
+		payload = &FieldChangedPayload{}
+	case EventCodeCommentAdded:
+		payload = &CommentAddedPayload{}
+	case EventCodeWorkflowStarted, EventCodeWorkflowCompleted, EventCodeWorkflowFailed:
+		payload = &WorkflowPayload{}
+	default:
+		return nil, fmt.Errorf("no payload type for event %q", e.Meta.Event)
+	}
+
+	if err := e.DecodePayload(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// DecodePayload decodes the event payload into v, e.g. a
+// *RecordUpdatedPayload or a struct of your own
+func (e *EventMessage) DecodePayload(v interface{}) error {
+	data, err := json.Marshal(e.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return nil
+}
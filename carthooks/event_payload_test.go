@@ -0,0 +1,105 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEventMessage_DecodePayload(t *testing.T) {
+	body := `{
+		"version": "1",
+		"meta": {"collection_id": 456, "event": "collection.item.updated"},
+		"payload": {
+			"id": 9,
+			"title": "Widget",
+			"updated_at": 1700000000,
+			"fields": {"f_1001": "shipped", "f_1002": 3},
+			"previous": {"f_1002": 2, "f_1001": "pending"}
+		}
+	}`
+
+	var event EventMessage
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatalf("Failed to parse event: %v", err)
+	}
+
+	var payload RecordUpdatedPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		t.Fatalf("DecodePayload() failed: %v", err)
+	}
+
+	if payload.ID != 9 || payload.Title != "Widget" || payload.UpdatedAt != 1700000000 {
+		t.Errorf("Unexpected record %+v", payload.RecordFormat)
+	}
+	if payload.Fields["f_1001"] != "shipped" {
+		t.Errorf("Unexpected fields %v", payload.Fields)
+	}
+	if got := payload.ChangedFields(); !reflect.DeepEqual(got, []string{"f_1001", "f_1002"}) {
+		t.Errorf("Unexpected changed fields %v", got)
+	}
+}
+
+func TestEventMessage_DecodePayloadTypeMismatch(t *testing.T) {
+	event := EventMessage{Payload: map[string]interface{}{"id": "not-a-number"}}
+
+	var payload RecordCreatedPayload
+	if err := event.DecodePayload(&payload); err == nil {
+		t.Error("Expected an error decoding a string ID")
+	}
+}
+
+func TestEventMessage_TypedPayload(t *testing.T) {
+	tests := []struct {
+		body  string
+		check func(payload interface{}) bool
+	}{
+		{
+			`{"meta":{"event":"collection.item.deleted"},"payload":{"id":9,"deleted_at":1700000000}}`,
+			func(p interface{}) bool {
+				d, ok := p.(*RecordDeletedPayload)
+				return ok && d.ID == 9 && d.DeletedAt == 1700000000
+			},
+		},
+		{
+			`{"meta":{"event":"collection.item.field_changed"},"payload":{"id":9,"field":"f_1001","value":"shipped","previous":"pending"}}`,
+			func(p interface{}) bool {
+				f, ok := p.(*FieldChangedPayload)
+				return ok && f.ID == 9 && f.Field == "f_1001" && f.Previous == "pending"
+			},
+		},
+		{
+			`{"meta":{"event":"collection.item.comment_added"},"payload":{"id":3,"item_id":9,"content":"hi"}}`,
+			func(p interface{}) bool {
+				c, ok := p.(*CommentAddedPayload)
+				return ok && c.ItemID == 9 && c.Content == "hi"
+			},
+		},
+		{
+			`{"meta":{"event":"workflow.failed"},"payload":{"id":5,"workflow_id":2,"status":"failed","error":"timeout"}}`,
+			func(p interface{}) bool {
+				w, ok := p.(*WorkflowPayload)
+				return ok && w.WorkflowID == 2 && w.Error == "timeout"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		var event EventMessage
+		if err := json.Unmarshal([]byte(tt.body), &event); err != nil {
+			t.Fatalf("Failed to parse event: %v", err)
+		}
+		payload, err := event.TypedPayload()
+		if err != nil || !tt.check(payload) {
+			t.Errorf("Unexpected payload for %s: %+v, %v", event.Meta.Event, payload, err)
+		}
+	}
+
+	unknown := EventMessage{Meta: EventMessageMeta{Event: "collection.item.archived"}}
+	if _, err := unknown.TypedPayload(); err == nil {
+		t.Error("Expected an error for an unknown event code")
+	}
+	if !unknown.Is("collection.item.archived") || unknown.Is(EventCodeRecordCreated) {
+		t.Error("Unexpected Is() result")
+	}
+}
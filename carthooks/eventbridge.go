@@ -0,0 +1,73 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EventBridgeSource is the "source" field of events Carthooks puts on an
+// EventBridge bus; use it in rule event patterns
+const EventBridgeSource = "carthooks"
+
+// EventBridgeEvent is the EventBridge envelope around a Carthooks EventMessage,
+// as delivered to rule targets such as Lambda functions
+type EventBridgeEvent struct {
+	Version    string       `json:"version"`
+	ID         string       `json:"id"`
+	DetailType string       `json:"detail-type"`
+	Source     string       `json:"source"`
+	Account    string       `json:"account"`
+	Time       string       `json:"time"`
+	Region     string       `json:"region"`
+	Resources  []string     `json:"resources"`
+	Detail     EventMessage `json:"detail"`
+}
+
+// NewEventBridgeWatchOptions returns watch options that deliver events for a
+// collection to an EventBridge bus instead of an SQS queue
+func NewEventBridgeWatchOptions(name string, appID, collectionID uint, eventBusARN string) (*WatchDataOptions, error) {
+	region, err := regionFromARN(eventBusARN)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchDataOptions{
+		EndpointURL:  eventBusARN,
+		EndpointType: EndpointTypeEventBridge,
+		EventBusARN:  eventBusARN,
+		AWSRegion:    region,
+		Name:         name,
+		AppID:        appID,
+		CollectionID: collectionID,
+	}, nil
+}
+
+// ParseEventBridgeEvent decodes an EventBridge event and returns the
+// Carthooks EventMessage it carries. Events from other sources, or without
+// an event code in their detail, are rejected.
+func ParseEventBridgeEvent(data []byte) (*EventMessage, error) {
+	var event EventBridgeEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse EventBridge event: %w", err)
+	}
+
+	if event.Source != EventBridgeSource {
+		return nil, fmt.Errorf("unexpected EventBridge source %q", event.Source)
+	}
+	if event.Detail.Meta.Event == "" {
+		return nil, fmt.Errorf("EventBridge event %s carries no Carthooks event in its detail", event.ID)
+	}
+
+	return &event.Detail, nil
+}
+
+// regionFromARN extracts the region from an ARN such as
+// arn:aws:events:us-east-1:123456789012:event-bus/name
+func regionFromARN(arn string) (string, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "events" || parts[3] == "" {
+		return "", fmt.Errorf("invalid EventBridge bus ARN: %s", arn)
+	}
+	return parts[3], nil
+}
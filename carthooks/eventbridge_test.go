@@ -0,0 +1,67 @@
+package carthooks
+
+import "testing"
+
+func TestNewEventBridgeWatchOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		region  string
+		wantErr bool
+	}{
+		{"valid", "arn:aws:events:eu-west-1:123456789012:event-bus/carthooks", "eu-west-1", false},
+		{"not an ARN", "https://events.eu-west-1.amazonaws.com", "", true},
+		{"other service", "arn:aws:sqs:eu-west-1:123456789012:queue", "", true},
+		{"missing region", "arn:aws:events::123456789012:event-bus/carthooks", "", true},
+		{"too short", "arn:aws:events:eu-west-1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := NewEventBridgeWatchOptions("orders", 1, 2, tt.arn)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tt.arn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewEventBridgeWatchOptions() failed: %v", err)
+			}
+			if options.AWSRegion != tt.region || options.EventBusARN != tt.arn || options.EndpointURL != tt.arn ||
+				options.EndpointType != EndpointTypeEventBridge || options.AppID != 1 || options.CollectionID != 2 || options.Name != "orders" {
+				t.Errorf("Unexpected options %+v", options)
+			}
+		})
+	}
+}
+
+func TestParseEventBridgeEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid envelope", `{"version":"0","id":"e-1","detail-type":"collection.item.updated","source":"carthooks","region":"eu-west-1",
+			"detail":{"version":"1","meta":{"tenant_id":3,"collection_id":456,"event":"collection.item.updated"},"payload":{"id":9}}}`, false},
+		{"missing detail", `{"version":"0","id":"e-2","source":"carthooks"}`, true},
+		{"other source", `{"id":"e-3","source":"aws.s3","detail":{"meta":{"event":"collection.item.updated"}}}`, true},
+		{"malformed JSON", `{"source":`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEventBridgeEvent([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", event)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEventBridgeEvent() failed: %v", err)
+			}
+			if event.Meta.Event != EventCodeRecordUpdated || event.Meta.CollectionID != 456 || event.Record()["id"] != float64(9) {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package eventtest builds wire-accurate Carthooks event envelopes for
+// handler unit tests, so tests do not depend on captured production
+// payloads.
+//
+//	event := eventtest.NewUpdatedEvent(3, 456,
+//		map[string]interface{}{"id": 9, "fields": map[string]interface{}{"f_1001": "shipped"}},
+//		map[string]interface{}{"f_1001": "pending"},
+//	)
+//	err := handler(ctx, event.Event())
+//
+//	// or exercise a WebhookHandler end to end
+//	webhook.ServeHTTP(recorder, event.Signed(secret).Request("/events"))
+package eventtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// Builder assembles an event envelope. Its methods return the builder so
+// calls can be chained.
+type Builder struct {
+	event  carthooks.EventMessage
+	secret string
+}
+
+var sequence struct {
+	sync.Mutex
+	next uint
+}
+
+func nextID() uint {
+	sequence.Lock()
+	defer sequence.Unlock()
+	sequence.next++
+	return sequence.next
+}
+
+// New builds an event with the given code and payload. Meta defaults to an
+// API trigger with the current time and a unique nonce.
+func New(code carthooks.EventCode, tenantID, collectionID uint, payload map[string]interface{}) *Builder {
+	return &Builder{event: carthooks.EventMessage{
+		Version: "1",
+		Meta: carthooks.EventMessageMeta{
+			TenantID:     tenantID,
+			CollectionID: collectionID,
+			Event:        code,
+			TriggerType:  "api",
+			Timestamp:    time.Now().Unix(),
+			Nonce:        fmt.Sprintf("eventtest-%d", nextID()),
+		},
+		Payload: payload,
+	}}
+}
+
+// NewCreatedEvent builds a collection.item.created event for record.
+// Missing id, created_at and updated_at fields are filled in.
+func NewCreatedEvent(tenantID, collectionID uint, record map[string]interface{}) *Builder {
+	payload := recordPayload(record)
+	if _, ok := payload["created_at"]; !ok {
+		payload["created_at"] = payload["updated_at"]
+	}
+	return New(carthooks.EventCodeRecordCreated, tenantID, collectionID, payload)
+}
+
+// NewUpdatedEvent builds a collection.item.updated event for record, with
+// the values of changed fields before the update in previous. Missing id,
+// created_at and updated_at fields are filled in.
+func NewUpdatedEvent(tenantID, collectionID uint, record, previous map[string]interface{}) *Builder {
+	payload := recordPayload(record)
+	if _, ok := payload["created_at"]; !ok {
+		payload["created_at"] = time.Now().Add(-time.Hour).Unix()
+	}
+	if previous != nil {
+		payload["previous"] = previous
+	}
+	return New(carthooks.EventCodeRecordUpdated, tenantID, collectionID, payload)
+}
+
+// NewDeletedEvent builds a collection.item.deleted event for a record
+func NewDeletedEvent(tenantID, collectionID, recordID uint) *Builder {
+	return New(carthooks.EventCodeRecordDeleted, tenantID, collectionID, map[string]interface{}{
+		"id":         recordID,
+		"deleted_at": time.Now().Unix(),
+	})
+}
+
+func recordPayload(record map[string]interface{}) map[string]interface{} {
+	payload := make(map[string]interface{}, len(record)+3)
+	for k, v := range record {
+		payload[k] = v
+	}
+	if _, ok := payload["id"]; !ok {
+		payload["id"] = nextID()
+	}
+	if _, ok := payload["updated_at"]; !ok {
+		payload["updated_at"] = time.Now().Unix()
+	}
+	return payload
+}
+
+// WithTrigger sets what triggered the event, e.g. "api" or "automation"
+func (b *Builder) WithTrigger(triggerType, triggerName string) *Builder {
+	b.event.Meta.TriggerType = triggerType
+	b.event.Meta.TriggerName = triggerName
+	return b
+}
+
+// WithTimestamp sets the delivery timestamp checked by ReplayGuard
+func (b *Builder) WithTimestamp(t time.Time) *Builder {
+	b.event.Meta.Timestamp = t.Unix()
+	return b
+}
+
+// WithNonce sets the delivery nonce checked by ReplayGuard
+func (b *Builder) WithNonce(nonce string) *Builder {
+	b.event.Meta.Nonce = nonce
+	return b
+}
+
+// Signed signs the deliveries built by Request and Message with secret
+func (b *Builder) Signed(secret string) *Builder {
+	b.secret = secret
+	return b
+}
+
+// Event returns the event as a handler receives it, decoded from its JSON
+// so the payload has the same types as a real delivery
+func (b *Builder) Event() *carthooks.EventMessage {
+	var event carthooks.EventMessage
+	if err := json.Unmarshal(b.JSON(), &event); err != nil {
+		panic(fmt.Sprintf("eventtest: %v", err))
+	}
+	return &event
+}
+
+// JSON returns the envelope as it is sent on the wire
+func (b *Builder) JSON() []byte {
+	body, err := json.Marshal(b.event)
+	if err != nil {
+		panic(fmt.Sprintf("eventtest: %v", err))
+	}
+	return body
+}
+
+// Request returns a webhook delivery of the event to target, for testing a
+// carthooks.WebhookHandler
+func (b *Builder) Request(target string) *http.Request {
+	body := b.JSON()
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if b.secret != "" {
+		req.Header.Set(carthooks.WebhookSignatureHeader, carthooks.SignWebhookPayload(b.secret, body))
+	}
+	return req
+}
+
+// Message returns a queue delivery of the event, for testing code that
+// consumes carthooks.Message values
+func (b *Builder) Message() *carthooks.Message {
+	body := b.JSON()
+	message := &carthooks.Message{
+		ID:   fmt.Sprintf("eventtest-message-%d", nextID()),
+		Body: body,
+	}
+	if b.secret != "" {
+		message.Attributes = map[string]string{
+			carthooks.WebhookSignatureHeader: carthooks.SignWebhookPayload(b.secret, body),
+		}
+	}
+	return message
+}
@@ -0,0 +1,60 @@
+package eventtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+func TestNewUpdatedEvent(t *testing.T) {
+	event := NewUpdatedEvent(3, 456,
+		map[string]interface{}{"id": 9, "fields": map[string]interface{}{"f_1001": "shipped"}},
+		map[string]interface{}{"f_1001": "pending"},
+	).WithTrigger("automation", "nightly").Event()
+
+	if !event.Is(carthooks.EventCodeRecordUpdated) || event.Meta.TenantID != 3 || event.Meta.CollectionID != 456 {
+		t.Errorf("Unexpected meta %+v", event.Meta)
+	}
+	if event.Meta.TriggerType != "automation" || event.Meta.TriggerName != "nightly" {
+		t.Errorf("Unexpected trigger %+v", event.Meta)
+	}
+
+	var payload carthooks.RecordUpdatedPayload
+	if err := event.DecodePayload(&payload); err != nil {
+		t.Fatalf("DecodePayload() failed: %v", err)
+	}
+	if payload.ID != 9 || payload.Fields["f_1001"] != "shipped" || payload.UpdatedAt == 0 || payload.CreatedAt >= payload.UpdatedAt {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if changed := payload.ChangedFields(); len(changed) != 1 || changed[0] != "f_1001" {
+		t.Errorf("Unexpected changed fields %v", changed)
+	}
+}
+
+func TestBuilder_SignedDeliveries(t *testing.T) {
+	var received *carthooks.EventMessage
+	webhook := carthooks.NewWebhookHandler("secret", carthooks.EventHandlerFunc(func(event *carthooks.EventMessage) error {
+		received = event
+		return nil
+	}))
+	webhook.Replay = carthooks.NewReplayGuard(5 * time.Minute)
+
+	event := NewCreatedEvent(3, 456, map[string]interface{}{"title": "Widget"}).Signed("secret")
+	rec := httptest.NewRecorder()
+	webhook.ServeHTTP(rec, event.Request("/events"))
+	if rec.Code != http.StatusNoContent || received == nil || received.Record()["title"] != "Widget" {
+		t.Fatalf("Expected delivery to be handled, got %d %+v", rec.Code, received)
+	}
+
+	message := NewDeletedEvent(3, 456, 9).Signed("secret").Message()
+	headers := http.Header{}
+	for name, value := range message.Attributes {
+		headers.Set(name, value)
+	}
+	if _, err := carthooks.VerifyEventSignature(headers, message.Body, "secret"); err != nil {
+		t.Errorf("Expected signed message to verify, got %v", err)
+	}
+}
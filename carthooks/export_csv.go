@@ -0,0 +1,218 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record property columns of CSV exports, alongside field keys
+const (
+	ColumnID        = "id"
+	ColumnTitle     = "title"
+	ColumnCreatedAt = "created_at"
+	ColumnUpdatedAt = "updated_at"
+	ColumnCreator   = "creator"
+)
+
+var recordColumns = []string{ColumnID, ColumnTitle, ColumnCreatedAt, ColumnUpdatedAt, ColumnCreator}
+
+// CSVOptions configures ExportCSV
+type CSVOptions struct {
+	// Columns lists the columns to export, in order: field keys and the
+	// Column constants. Defaults to the record columns followed by the
+	// schema's fields with UseSchemaLabels, or else the first record's
+	// fields in key order.
+	Columns []string
+	// Headers maps columns to header names. Columns without one use their
+	// schema label with UseSchemaLabels, and otherwise their key.
+	Headers map[string]string
+	// UseSchemaLabels fetches the collection with GetCollection for field
+	// labels and order
+	UseSchemaLabels bool
+	// Separator joins the values of multi-value fields (default "; ")
+	Separator string
+	// TimeFormat, if set, formats created_at and updated_at as times in
+	// that layout instead of Unix seconds
+	TimeFormat string
+	// Flatten, if set, converts field values to cells instead of the
+	// default flattening
+	Flatten func(column string, value interface{}) string
+	// Comma is the field delimiter (default ',')
+	Comma rune
+}
+
+// ExportCSV streams the records matching query to w as CSV with a header
+// row, fetching pages as they are written. Field values are flattened to
+// cells: lists are joined with Separator, and objects such as attachments
+// and lookups are written as their title, name, label, value, url or id,
+// whichever they have first, falling back to JSON.
+func (c *Client) ExportCSV(ctx context.Context, appID, collectionID uint, query *QueryOptions, w io.Writer, opts *CSVOptions) error {
+	options := CSVOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Separator == "" {
+		options.Separator = "; "
+	}
+
+	labels := map[string]string{}
+	columns := options.Columns
+	if options.UseSchemaLabels {
+		var collection Collection
+		if err := c.WithContext(ctx).GetCollection(appID, collectionID).Decode(&collection); err != nil {
+			return fmt.Errorf("failed to fetch collection schema: %w", err)
+		}
+		for _, field := range collection.Fields {
+			labels[field.Key] = field.Label
+		}
+		if columns == nil {
+			columns = append([]string(nil), recordColumns...)
+			for _, field := range collection.Fields {
+				columns = append(columns, field.Key)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if options.Comma != 0 {
+		writer.Comma = options.Comma
+	}
+
+	writeHeader := func() error {
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			switch {
+			case options.Headers[column] != "":
+				header[i] = options.Headers[column]
+			case labels[column] != "":
+				header[i] = labels[column]
+			default:
+				header[i] = column
+			}
+		}
+		return writer.Write(header)
+	}
+
+	headerWritten := false
+	if columns != nil {
+		if err := writeHeader(); err != nil {
+			return err
+		}
+		headerWritten = true
+	}
+
+	err := c.EachRecord(ctx, appID, collectionID, query, func(record RecordFormat) error {
+		if !headerWritten {
+			columns = append(append([]string(nil), recordColumns...), fieldKeys(record.Fields)...)
+			if err := writeHeader(); err != nil {
+				return err
+			}
+			headerWritten = true
+		}
+
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = options.cell(record, column)
+		}
+		return writer.Write(row)
+	})
+	if err == nil && !headerWritten {
+		columns = recordColumns
+		err = writeHeader()
+	}
+
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// fieldKeys returns the keys of fields, sorted
+func fieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cell returns the CSV cell for a record column
+func (o *CSVOptions) cell(record RecordFormat, column string) string {
+	switch column {
+	case ColumnID:
+		return strconv.FormatUint(uint64(record.ID), 10)
+	case ColumnTitle:
+		return record.Title
+	case ColumnCreatedAt:
+		return o.formatTime(record.CreatedAt)
+	case ColumnUpdatedAt:
+		return o.formatTime(record.UpdatedAt)
+	case ColumnCreator:
+		return strconv.FormatUint(uint64(record.Creator), 10)
+	}
+
+	value := record.Fields[column]
+	if o.Flatten != nil {
+		return o.Flatten(column, value)
+	}
+	return flattenValue(value, o.Separator)
+}
+
+func (o *CSVOptions) formatTime(seconds int64) string {
+	if o.TimeFormat == "" {
+		return strconv.FormatInt(seconds, 10)
+	}
+	if seconds == 0 {
+		return ""
+	}
+	return time.Unix(seconds, 0).UTC().Format(o.TimeFormat)
+}
+
+// flattenKeys are the keys whose value represents an object in a cell,
+// in order of preference
+var flattenKeys = []string{"title", "name", "label", "value", "url", "id"}
+
+// flattenValue converts a field value to a single cell
+func flattenValue(value interface{}, separator string) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if part := flattenValue(item, separator); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, separator)
+	case map[string]interface{}:
+		for _, key := range flattenKeys {
+			if inner, ok := v[key]; ok && inner != nil {
+				return flattenValue(inner, separator)
+			}
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
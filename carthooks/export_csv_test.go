@@ -0,0 +1,62 @@
+package carthooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_ExportCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/apps/1/collections/2" {
+			fmt.Fprint(w, `{"data":{"id":2,"fields":[{"key":"f_1","label":"Status"},{"key":"f_2","label":"Files"},{"key":"f_3","label":"Customer"}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[
+			{"id":1,"title":"Order, one","updated_at":1700000000,"fields":{"f_1":"open","f_2":[{"name":"a.pdf","url":"https://x/a.pdf"},{"name":"b.pdf"}],"f_3":{"id":9,"title":"Acme"}}},
+			{"id":2,"title":"Order two","fields":{"f_1":null,"f_2":[],"f_3":{"id":10}}}
+		]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var buf bytes.Buffer
+	err := client.ExportCSV(context.Background(), 1, 2, nil, &buf, &CSVOptions{
+		Columns:         []string{ColumnID, ColumnTitle, ColumnUpdatedAt, "f_1", "f_2", "f_3"},
+		Headers:         map[string]string{ColumnID: "Record ID"},
+		UseSchemaLabels: true,
+		TimeFormat:      "2006-01-02",
+	})
+	if err != nil {
+		t.Fatalf("ExportCSV() returned %v", err)
+	}
+
+	expected := `Record ID,title,updated_at,Status,Files,Customer
+1,"Order, one",2023-11-14,open,a.pdf; b.pdf,Acme
+2,Order two,,,,10
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestClient_ExportCSVDefaultColumns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":1,"title":"a","fields":{"f_2":2.5,"f_1":true}}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var buf bytes.Buffer
+	if err := client.ExportCSV(context.Background(), 1, 2, nil, &buf, nil); err != nil {
+		t.Fatalf("ExportCSV() returned %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[0] != "id,title,created_at,updated_at,creator,f_1,f_2" || lines[1] != "1,a,0,0,0,true,2.5" {
+		t.Errorf("Unexpected CSV:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,119 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// Export job formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// Export job status values reported in ExportJob.Status
+const (
+	ExportJobStatusPending   = "pending"
+	ExportJobStatusRunning   = "running"
+	ExportJobStatusCompleted = "completed"
+	ExportJobStatusFailed    = "failed"
+)
+
+// ExportJobOptions describes a server-side export
+type ExportJobOptions struct {
+	// Format is ExportFormatCSV or ExportFormatNDJSON
+	Format  string                 `json:"format"`
+	Filters map[string]interface{} `json:"filters,omitempty"`
+	Sort    []string               `json:"sort,omitempty"`
+	Fields  []string               `json:"fields,omitempty"`
+}
+
+// ExportJob describes an export job
+type ExportJob struct {
+	JobID        string `json:"job_id"`
+	AppID        uint   `json:"app_id"`
+	CollectionID uint   `json:"collection_id"`
+	Format       string `json:"format"`
+	Status       string `json:"status"`
+	Records      int    `json:"records"`
+	Error        string `json:"error,omitempty"`
+	CreatedAt    int64  `json:"created_at"`  // Unix timestamp in seconds
+	FinishedAt   int64  `json:"finished_at"` // Unix timestamp in seconds
+}
+
+// Done reports whether the job has completed or failed
+func (j *ExportJob) Done() bool {
+	return j.Status == ExportJobStatusCompleted || j.Status == ExportJobStatusFailed
+}
+
+// SubmitExportJob starts a server-side export of a collection. The result's
+// data is the ExportJob; wait for it with WaitForJob.
+func (c *Client) SubmitExportJob(appID, collectionID uint, options *ExportJobOptions) *Result {
+	path := fmt.Sprintf("/v1/apps/%d/collections/%d/exports", appID, collectionID)
+
+	resp, err := c.makeRequest("POST", path, options, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// GetExportJobStatus retrieves an export job
+func (c *Client) GetExportJobStatus(jobID string) *Result {
+	path := fmt.Sprintf("/v1/exports/%s", url.PathEscape(jobID))
+
+	resp, err := c.makeRequest("GET", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// DownloadExportResult streams the file of a completed export job to w
+func (c *Client) DownloadExportResult(ctx context.Context, jobID string, w io.Writer) error {
+	path := fmt.Sprintf("/v1/exports/%s/download", url.PathEscape(jobID))
+
+	resp, err := c.WithContext(ctx).makeRequest("GET", path, nil, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseResponse(resp).Err()
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download export %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// WaitForJob polls an export job every pollInterval until it completes,
+// fails or ctx is done. A failed job is returned with an error carrying
+// the job's error message.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, pollInterval time.Duration) (*ExportJob, error) {
+	client := c.WithContext(ctx)
+	for {
+		var job ExportJob
+		if err := client.GetExportJobStatus(jobID).Decode(&job); err != nil {
+			return nil, err
+		}
+		if job.Status == ExportJobStatusFailed {
+			return &job, fmt.Errorf("export job %s failed: %s", jobID, job.Error)
+		}
+		if job.Done() {
+			return &job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &job, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
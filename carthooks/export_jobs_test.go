@@ -0,0 +1,67 @@
+package carthooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ExportJob(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/apps/1/collections/2/exports":
+			fmt.Fprint(w, `{"data":{"job_id":"job-1","status":"pending"}}`)
+		case "/v1/exports/job-1":
+			polls++
+			status := "running"
+			if polls == 3 {
+				status = "completed"
+			}
+			fmt.Fprintf(w, `{"data":{"job_id":"job-1","status":%q,"records":2}}`, status)
+		case "/v1/exports/job-1/download":
+			fmt.Fprint(w, "id,title\n1,a\n2,b\n")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"job not found","code":"NOT_FOUND"}}`)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var job ExportJob
+	if err := client.SubmitExportJob(1, 2, &ExportJobOptions{Format: ExportFormatCSV}).Decode(&job); err != nil || job.JobID != "job-1" {
+		t.Fatalf("SubmitExportJob() = %+v, %v", job, err)
+	}
+
+	done, err := client.WaitForJob(context.Background(), job.JobID, time.Millisecond)
+	if err != nil || done.Status != ExportJobStatusCompleted || polls != 3 {
+		t.Fatalf("WaitForJob() = %+v, %v after %d polls", done, err, polls)
+	}
+
+	var buf bytes.Buffer
+	if err := client.DownloadExportResult(context.Background(), job.JobID, &buf); err != nil || buf.String() != "id,title\n1,a\n2,b\n" {
+		t.Errorf("DownloadExportResult() = %q, %v", buf.String(), err)
+	}
+	if err := client.DownloadExportResult(context.Background(), "missing", &buf); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClient_WaitForJobFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"job_id":"job-1","status":"failed","error":"too many records"}}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	job, err := client.WaitForJob(context.Background(), "job-1", time.Millisecond)
+	if err == nil || job == nil || job.Error != "too many records" {
+		t.Errorf("WaitForJob() = %+v, %v", job, err)
+	}
+}
@@ -0,0 +1,25 @@
+package carthooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// ExportNDJSON streams the records matching query to w as newline-delimited
+// JSON, one record per line. Pages are fetched as they are written, so only
+// one page is held in memory at a time.
+func (c *Client) ExportNDJSON(ctx context.Context, appID, collectionID uint, query *QueryOptions, w io.Writer) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	encoder.SetEscapeHTML(false)
+
+	err := c.EachRecord(ctx, appID, collectionID, query, func(record RecordFormat) error {
+		return encoder.Encode(record)
+	})
+	if flushErr := buffered.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package carthooks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestClient_ExportNDJSON(t *testing.T) {
+	var pages []int
+	server := newPagedServer(t, 5, &pages)
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var buf bytes.Buffer
+	query := &QueryOptions{Pagination: &PaginationOptions{PageSize: 2}}
+	if err := client.ExportNDJSON(context.Background(), 1, 2, query, &buf); err != nil {
+		t.Fatalf("ExportNDJSON() returned %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var ids []uint
+	for scanner.Scan() {
+		var record RecordFormat
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Line %q is not a record: %v", scanner.Text(), err)
+		}
+		ids = append(ids, record.ID)
+	}
+	if len(ids) != 5 || ids[0] != 1 || ids[4] != 5 || len(pages) != 3 {
+		t.Errorf("Unexpected records %v from pages %v", ids, pages)
+	}
+}
@@ -0,0 +1,149 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// exportServer serves QueryItems over records, honoring an updated_at
+// $gte filter and pagination; records are kept sorted by updated_at and id
+type exportServer struct {
+	mu      sync.Mutex
+	records []RecordFormat
+}
+
+func (s *exportServer) set(id uint, updatedAt int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.records {
+		if s.records[i].ID == id {
+			s.records = append(s.records[:i], s.records[i+1:]...)
+			break
+		}
+	}
+	s.records = append(s.records, RecordFormat{ID: id, UpdatedAt: updatedAt})
+	sort.Slice(s.records, func(i, j int) bool {
+		a, b := s.records[i], s.records[j]
+		return a.UpdatedAt < b.UpdatedAt || (a.UpdatedAt == b.UpdatedAt && a.ID < b.ID)
+	})
+}
+
+func (s *exportServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var query QueryOptions
+	json.NewDecoder(r.Body).Decode(&query)
+
+	var since int64
+	if filter, ok := query.Filters["updated_at"].(map[string]interface{}); ok {
+		since = int64(filter["$gte"].(float64))
+	}
+
+	s.mu.Lock()
+	var matching []RecordFormat
+	for _, record := range s.records {
+		if record.UpdatedAt >= since {
+			matching = append(matching, record)
+		}
+	}
+	s.mu.Unlock()
+
+	page, size := query.Pagination.Page, query.Pagination.PageSize
+	start, end := (page-1)*size, page*size
+	if start > len(matching) {
+		start = len(matching)
+	}
+	if end > len(matching) {
+		end = len(matching)
+	}
+	data, _ := json.Marshal(matching[start:end])
+	fmt.Fprintf(w, `{"data":%s}`, data)
+}
+
+func TestClient_ExportItemsSince(t *testing.T) {
+	server := &exportServer{}
+	// 150 records share a second, so the export must page within it
+	for id := uint(1); id <= 250; id++ {
+		server.set(id, 1000+int64(id/150))
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := NewClient(&ClientConfig{BaseURL: ts.URL})
+
+	exported := map[uint]int{}
+	updatedOnce := false
+	checkpoint, err := client.ExportItemsSince(1, 2, time.Time{}, func(records []RecordFormat) error {
+		for _, record := range records {
+			exported[record.ID]++
+		}
+		// An update during the export moves a read record to the end
+		if !updatedOnce {
+			updatedOnce = true
+			server.set(1, 1005)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportItemsSince() returned %v", err)
+	}
+	if len(exported) != 250 {
+		t.Errorf("Expected all 250 records, got %d", len(exported))
+	}
+	for id, count := range exported {
+		if count > 1 && id != 1 {
+			t.Errorf("Record %d exported %d times", id, count)
+		}
+	}
+	if checkpoint.Unix() != 1005 {
+		t.Errorf("Expected checkpoint 1005, got %d", checkpoint.Unix())
+	}
+
+	server.set(7, 1010)
+	var next []uint
+	checkpoint, err = client.ExportItemsSince(1, 2, checkpoint, func(records []RecordFormat) error {
+		for _, record := range records {
+			next = append(next, record.ID)
+		}
+		return nil
+	})
+	if err != nil || fmt.Sprint(next) != "[1 7]" || checkpoint.Unix() != 1010 {
+		t.Errorf("Incremental export = %v, %v, checkpoint %d", next, err, checkpoint.Unix())
+	}
+}
+
+func TestClient_ExportItemsWithCheckpoint(t *testing.T) {
+	server := &exportServer{}
+	for id := uint(1); id <= 3; id++ {
+		server.set(id, 1000+int64(id))
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := NewClient(&ClientConfig{BaseURL: ts.URL})
+
+	store := NewMemoryCheckpointStore()
+	ctx := context.Background()
+	sink := func(records []RecordFormat) error { return nil }
+	if err := client.ExportItemsWithCheckpoint(ctx, store, "warehouse", 1, 2, sink); err != nil {
+		t.Fatalf("ExportItemsWithCheckpoint() returned %v", err)
+	}
+	if checkpoint, _ := store.Load(ctx, "warehouse"); checkpoint.Unix() != 1003 {
+		t.Errorf("Expected saved checkpoint 1003, got %d", checkpoint.Unix())
+	}
+
+	server.set(4, 1004)
+	var ids []uint
+	client.ExportItemsWithCheckpoint(ctx, store, "warehouse", 1, 2, func(records []RecordFormat) error {
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		return nil
+	})
+	if fmt.Sprint(ids) != "[3 4]" {
+		t.Errorf("Expected resumed export of records 3 and 4, got %v", ids)
+	}
+}
@@ -0,0 +1,122 @@
+package carthooks
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// IDGenerator generates client-assigned external IDs for new items
+type IDGenerator interface {
+	NewID() (string, error)
+}
+
+// IDGeneratorFunc adapts an ordinary function to the IDGenerator interface
+type IDGeneratorFunc func() (string, error)
+
+// NewID calls f()
+func (f IDGeneratorFunc) NewID() (string, error) {
+	return f()
+}
+
+// UUIDv7Generator generates time-ordered UUIDv7 identifiers (RFC 9562).
+// Being time-ordered, they keep inserts into an indexed field append-friendly.
+type UUIDv7Generator struct{}
+
+// NewID returns a new UUIDv7 string
+func (UUIDv7Generator) NewID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	// 48-bit big-endian Unix timestamp in milliseconds
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(time.Now().UnixMilli()))
+	copy(b[0:6], ts[2:8])
+
+	b[6] = (b[6] & 0x0f) | 0x70 // version 7
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// NewExternalID generates a new external ID using the configured generator
+func (c *Client) NewExternalID() (string, error) {
+	generator := c.idGenerator
+	if generator == nil {
+		generator = UUIDv7Generator{}
+	}
+	return generator.NewID()
+}
+
+// CreateItemWithExternalID creates a new item, writing a client-generated
+// external ID into the configured ExternalIDField. A value already present
+// in data for that field is kept as is.
+func (c *Client) CreateItemWithExternalID(appID, collectionID uint, data map[string]interface{}) *Result {
+	if c.externalIDField == "" {
+		return &Result{
+			Success: false,
+			Error:   "external ID field not configured",
+		}
+	}
+
+	withID := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		withID[k] = v
+	}
+
+	if v, ok := withID[c.externalIDField]; !ok || v == nil || v == "" {
+		externalID, err := c.NewExternalID()
+		if err != nil {
+			return &Result{
+				Success: false,
+				Error:   fmt.Sprintf("failed to generate external ID: %v", err),
+			}
+		}
+		withID[c.externalIDField] = externalID
+	}
+
+	return c.CreateItem(appID, collectionID, withID)
+}
+
+// GetItemByExternalID retrieves a single item by its client-assigned external ID.
+// The lookup is an exact match on a single field with no sorting, so it can be
+// served from an index on ExternalIDField.
+func (c *Client) GetItemByExternalID(appID, collectionID uint, externalID string) *Result {
+	if c.externalIDField == "" {
+		return &Result{
+			Success: false,
+			Error:   "external ID field not configured",
+		}
+	}
+
+	options := &QueryOptions{
+		Pagination: &PaginationOptions{
+			Page:     1,
+			PageSize: 1,
+		},
+		Filters: map[string]interface{}{
+			c.externalIDField: map[string]interface{}{"$eq": externalID},
+		},
+	}
+
+	result := c.QueryItems(appID, collectionID, options)
+	if !result.Success {
+		return result
+	}
+
+	items, ok := result.Data.([]interface{})
+	if !ok || len(items) == 0 {
+		return &Result{
+			Success: false,
+			Error:   fmt.Sprintf("item with external ID %s not found", externalID),
+			TraceID: result.TraceID,
+			Meta:    result.Meta,
+		}
+	}
+
+	result.Data = items[0]
+	return result
+}
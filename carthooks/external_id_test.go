@@ -0,0 +1,107 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestUUIDv7Generator(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	prev := ""
+	for i := 0; i < 100; i++ {
+		id, err := UUIDv7Generator{}.NewID()
+		if err != nil {
+			t.Fatalf("NewID() failed: %v", err)
+		}
+		if !pattern.MatchString(id) {
+			t.Fatalf("NewID() = %s, not a UUIDv7", id)
+		}
+		if id == prev {
+			t.Fatalf("NewID() returned duplicate ID %s", id)
+		}
+		prev = id
+	}
+}
+
+func TestClient_CreateItemWithExternalID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requestBody map[string]map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		if requestBody["data"]["f_ext"] != "ext-1" {
+			t.Errorf("Expected f_ext 'ext-1', got %v", requestBody["data"]["f_ext"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": requestBody["data"]})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:         server.URL,
+		ExternalIDField: "f_ext",
+		IDGenerator: IDGeneratorFunc(func() (string, error) {
+			return "ext-1", nil
+		}),
+	})
+
+	data := map[string]interface{}{"title": "Test Item"}
+	result := client.CreateItemWithExternalID(123, 456, data)
+	if !result.Success {
+		t.Fatalf("CreateItemWithExternalID() failed: %s", result.Error)
+	}
+
+	if _, ok := data["f_ext"]; ok {
+		t.Error("CreateItemWithExternalID() should not modify the caller's data")
+	}
+}
+
+func TestClient_GetItemByExternalID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/v1/apps/123/collections/456/items/query"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+
+		var options QueryOptions
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+
+		filter, _ := options.Filters["f_ext"].(map[string]interface{})
+		w.Header().Set("Content-Type", "application/json")
+		if filter["$eq"] == "ext-1" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]interface{}{{"id": 7, "title": "Found"}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": []interface{}{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:         server.URL,
+		ExternalIDField: "f_ext",
+	})
+
+	result := client.GetItemByExternalID(123, 456, "ext-1")
+	record, err := result.GetRecord()
+	if err != nil {
+		t.Fatalf("GetRecord() failed: %v", err)
+	}
+	if record.ID != 7 {
+		t.Errorf("Expected ID 7, got %d", record.ID)
+	}
+
+	result = client.GetItemByExternalID(123, 456, "missing")
+	if result.Success {
+		t.Error("Expected failure for unknown external ID")
+	}
+}
@@ -0,0 +1,225 @@
+package carthooks
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailoverThreshold = 3
+	defaultFailoverRecovery  = 30 * time.Second
+)
+
+// FailoverConfig lets the client fail over from its BaseURL to fallback
+// endpoints, e.g. other regions, while it is down. Endpoints are preferred
+// in order: BaseURL, then FallbackURLs. An endpoint is marked down after
+// FailureThreshold failed requests in a row and skipped until
+// RecoveryInterval has passed; then it is tried again, and requests return
+// to it as soon as it answers.
+//
+// Network errors and 502, 503 and 504 responses count as failures. A
+// failed request is retried on the next endpoint if it is safe to repeat:
+// GET, HEAD, PUT, DELETE and OPTIONS requests, and writes carrying an
+// IdempotencyKeyHeader. Other writes return the failure, and only later
+// requests go to the next endpoint.
+type FailoverConfig struct {
+	// FallbackURLs are the endpoints used while BaseURL is down, in order
+	// of preference
+	FallbackURLs []string
+	// FailureThreshold is how many failed requests in a row mark an
+	// endpoint down (default 3)
+	FailureThreshold int
+	// RecoveryInterval is how long an endpoint marked down is skipped
+	// (default 30s)
+	RecoveryInterval time.Duration
+	// OnFailover, if set, is called in its own goroutine when requests move
+	// to another endpoint
+	OnFailover func(from, to string)
+}
+
+// failover routes requests to the first healthy endpoint
+type failover struct {
+	config    FailoverConfig
+	endpoints []*failoverEndpoint
+
+	mu     sync.Mutex
+	active int
+	now    func() time.Time
+}
+
+type failoverEndpoint struct {
+	baseURL   string
+	url       *url.URL
+	failures  int
+	downUntil time.Time
+}
+
+func newFailover(baseURL string, config *FailoverConfig) *failover {
+	if config == nil || len(config.FallbackURLs) == 0 {
+		return nil
+	}
+	f := &failover{config: *config, now: time.Now}
+	if f.config.FailureThreshold <= 0 {
+		f.config.FailureThreshold = defaultFailoverThreshold
+	}
+	if f.config.RecoveryInterval <= 0 {
+		f.config.RecoveryInterval = defaultFailoverRecovery
+	}
+	for _, endpoint := range append([]string{baseURL}, config.FallbackURLs...) {
+		endpoint = strings.TrimSuffix(endpoint, "/")
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			continue
+		}
+		f.endpoints = append(f.endpoints, &failoverEndpoint{baseURL: endpoint, url: u})
+	}
+	return f
+}
+
+// candidates returns the endpoints to try, in order: those not marked
+// down, or every endpoint if all are down
+func (f *failover) candidates() []*failoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	var up []*failoverEndpoint
+	for _, endpoint := range f.endpoints {
+		if !now.Before(endpoint.downUntil) {
+			up = append(up, endpoint)
+		}
+	}
+	if len(up) == 0 {
+		return f.endpoints
+	}
+	return up
+}
+
+// record registers a request outcome on an endpoint
+func (f *failover) record(endpoint *failoverEndpoint, failed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !failed {
+		endpoint.failures = 0
+		endpoint.downUntil = time.Time{}
+		f.activate(endpoint)
+		return
+	}
+
+	endpoint.failures++
+	if endpoint.failures >= f.config.FailureThreshold {
+		endpoint.downUntil = f.now().Add(f.config.RecoveryInterval)
+	}
+}
+
+// activate notes that requests are now served by endpoint
+func (f *failover) activate(endpoint *failoverEndpoint) {
+	for i, e := range f.endpoints {
+		if e == endpoint && i != f.active {
+			from := f.endpoints[f.active].baseURL
+			f.active = i
+			if f.config.OnFailover != nil {
+				go f.config.OnFailover(from, endpoint.baseURL)
+			}
+		}
+	}
+}
+
+// activeURL returns the base URL currently serving requests
+func (f *failover) activeURL() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.endpoints[f.active].baseURL
+}
+
+// wrap returns a RoundTripFunc that sends requests for the primary base URL
+// to the first healthy endpoint, moving on to the next one on failure
+func (f *failover) wrap(send RoundTripFunc) RoundTripFunc {
+	primary := f.endpoints[0].baseURL
+	return func(req *http.Request) (*http.Response, error) {
+		target := req.URL.String()
+		if !strings.HasPrefix(target, primary) {
+			return send(req)
+		}
+		rest := strings.TrimPrefix(target, primary)
+
+		candidates := f.candidates()
+		var resp *http.Response
+		var err error
+		for i, endpoint := range candidates {
+			attempt, buildErr := retarget(req, endpoint.baseURL+rest, i > 0)
+			if buildErr != nil {
+				if resp == nil && err == nil {
+					return nil, buildErr
+				}
+				return resp, err
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			resp, err = send(attempt)
+			if err != nil && req.Context().Err() != nil {
+				// Cancelled by the caller; says nothing about the endpoint
+				return resp, err
+			}
+			failed := err != nil || failoverStatus(resp.StatusCode)
+			f.record(endpoint, failed)
+			if !failed || !repeatable(req) {
+				return resp, err
+			}
+		}
+		return resp, err
+	}
+}
+
+// retarget returns req sent to target, with a fresh body if it is a retry
+func retarget(req *http.Request, target string, retry bool) (*http.Request, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	attempt := req.Clone(req.Context())
+	attempt.URL = u
+	attempt.Host = u.Host
+	if retry && req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, http.ErrBodyNotAllowed
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attempt.Body = body
+	}
+	return attempt, nil
+}
+
+// failoverStatus reports whether a response status means the endpoint is
+// down rather than that it rejected the request
+func failoverStatus(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// repeatable reports whether req can be sent again without risk of
+// applying it twice
+func repeatable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// ActiveEndpoint returns the base URL serving the client's requests: a
+// fallback while failover has moved away from BaseURL, and BaseURL
+// otherwise
+func (c *Client) ActiveEndpoint() string {
+	if c.failover == nil {
+		return c.baseURL
+	}
+	return c.failover.activeURL()
+}
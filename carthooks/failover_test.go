@@ -0,0 +1,111 @@
+package carthooks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Failover(t *testing.T) {
+	var primaryDown atomic.Bool
+	var primaryHits, fallbackHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"endpoint":"primary"}}`)
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackHits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, `{"data":{"endpoint":"fallback","body":%q}}`, body)
+	}))
+	defer fallback.Close()
+
+	switched := make(chan string, 4)
+	client := NewClient(&ClientConfig{
+		BaseURL: primary.URL,
+		Failover: &FailoverConfig{
+			FallbackURLs:     []string{fallback.URL},
+			FailureThreshold: 2,
+			RecoveryInterval: time.Minute,
+			OnFailover:       func(from, to string) { switched <- to },
+		},
+	})
+	now := time.Now()
+	client.failover.now = func() time.Time { return now }
+
+	endpoint := func(result *Result) string {
+		var data map[string]string
+		if err := result.Decode(&data); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		return data["endpoint"]
+	}
+
+	if got := endpoint(client.GetApp(1)); got != "primary" {
+		t.Fatalf("Served by %s, want primary", got)
+	}
+
+	// Reads are retried on the fallback while the primary fails
+	primaryDown.Store(true)
+	for i := 0; i < 2; i++ {
+		if got := endpoint(client.GetApp(1)); got != "fallback" {
+			t.Fatalf("Served by %s, want fallback", got)
+		}
+	}
+	if <-switched != fallback.URL || client.ActiveEndpoint() != fallback.URL {
+		t.Errorf("ActiveEndpoint() = %s, want fallback", client.ActiveEndpoint())
+	}
+
+	// The primary is marked down and skipped; bodies reach the fallback
+	hits := primaryHits.Load()
+	result := client.UpdateConnection(1, 2, &UpdateConnectionRequest{Title: "CRM"})
+	var data map[string]string
+	result.Decode(&data)
+	if data["endpoint"] != "fallback" || data["body"] != `{"title":"CRM"}` || primaryHits.Load() != hits {
+		t.Errorf("Expected the write on the fallback only, got %v and %d primary hits", data, primaryHits.Load()-hits)
+	}
+
+	// After the recovery interval the primary is tried again
+	primaryDown.Store(false)
+	now = now.Add(time.Minute)
+	if got := endpoint(client.GetApp(1)); got != "primary" {
+		t.Fatalf("Served by %s after recovery, want primary", got)
+	}
+	if <-switched != primary.URL || client.ActiveEndpoint() != primary.URL {
+		t.Errorf("ActiveEndpoint() = %s, want primary", client.ActiveEndpoint())
+	}
+}
+
+func TestClient_FailoverDoesNotRepeatWrites(t *testing.T) {
+	var fallbackHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackHits.Add(1)
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer fallback.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:  primary.URL,
+		Failover: &FailoverConfig{FallbackURLs: []string{fallback.URL}},
+	})
+
+	if result := client.CreateItem(1, 2, map[string]interface{}{"title": "x"}); result.StatusCode != http.StatusBadGateway {
+		t.Errorf("CreateItem() status = %d, want 502", result.StatusCode)
+	}
+	if fallbackHits.Load() != 0 {
+		t.Errorf("POST without idempotency key was repeated on the fallback")
+	}
+}
@@ -0,0 +1,55 @@
+package carthooks
+
+import (
+	"encoding/json"
+)
+
+// messageGroup is a run of messages that must be processed in order
+type messageGroup struct {
+	id       string
+	messages []*Message
+}
+
+// groupMessages splits a batch by key, keeping the received order within
+// each group and the order in which groups first appear. Messages with an
+// empty key each form their own group.
+func groupMessages(messages []*Message, key func(*Message) string) []*messageGroup {
+	var groups []*messageGroup
+	index := map[string]*messageGroup{}
+	for _, message := range messages {
+		id := key(message)
+		group, ok := index[id]
+		if !ok || id == "" {
+			group = &messageGroup{id: id}
+			groups = append(groups, group)
+			if id != "" {
+				index[id] = group
+			}
+		}
+		group.messages = append(group.messages, message)
+	}
+	return groups
+}
+
+// messageGroupID groups messages by their source GroupID
+func messageGroupID(message *Message) string {
+	return message.GroupID
+}
+
+// messageRecordID groups messages by GroupID, falling back to the ID of the
+// record they carry so changes to one record stay in order
+func messageRecordID(message *Message) string {
+	if message.GroupID != "" {
+		return message.GroupID
+	}
+
+	var body struct {
+		Payload struct {
+			ID json.RawMessage `json:"id"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(message.Body, &body); err != nil || len(body.Payload.ID) == 0 {
+		return ""
+	}
+	return "record:" + string(body.Payload.ID)
+}
@@ -0,0 +1,116 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// recordingSource is a MessageSource that records acks and nacks
+type recordingSource struct {
+	mu     sync.Mutex
+	acked  []string
+	nacked []string
+}
+
+func (s *recordingSource) Receive(ctx context.Context) ([]*Message, error) { return nil, nil }
+func (s *recordingSource) Close() error                                    { return nil }
+
+func (s *recordingSource) Ack(ctx context.Context, message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acked = append(s.acked, message.ID)
+	return nil
+}
+
+func (s *recordingSource) Nack(ctx context.Context, message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nacked = append(s.nacked, message.ID)
+	return nil
+}
+
+func fifoMessage(id, group string, itemID int) *Message {
+	return &Message{
+		ID:              id,
+		GroupID:         group,
+		DeduplicationID: id,
+		Body:            []byte(fmt.Sprintf(`{"version":"1","payload":{"id":%d}}`, itemID)),
+	}
+}
+
+func TestGroupMessages(t *testing.T) {
+	groups := groupMessages([]*Message{
+		fifoMessage("1", "a", 1),
+		fifoMessage("2", "b", 2),
+		fifoMessage("3", "a", 3),
+		fifoMessage("4", "", 4),
+		fifoMessage("5", "", 5),
+	}, messageGroupID)
+	if len(groups) != 4 {
+		t.Fatalf("Expected 4 groups, got %d", len(groups))
+	}
+	if groups[0].id != "a" || len(groups[0].messages) != 2 || groups[0].messages[1].ID != "3" {
+		t.Errorf("Unexpected first group %+v", groups[0])
+	}
+}
+
+func TestWatcher_FIFOFailureHoldsBackGroup(t *testing.T) {
+	source := &recordingSource{}
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:         source,
+		ParallelGroups: true,
+		Handler:        func(ctx context.Context, event *EventMessage) error { return nil },
+	})
+
+	broken := &Message{ID: "2", GroupID: "a", Body: []byte(`{"payload":{}}`)}
+	watcher.processBatch(context.Background(), []*Message{
+		fifoMessage("1", "a", 1),
+		broken,
+		fifoMessage("3", "a", 3),
+		fifoMessage("4", "b", 4),
+	})
+
+	acked := map[string]bool{}
+	for _, id := range source.acked {
+		acked[id] = true
+	}
+	if !acked["1"] || !acked["4"] || len(source.acked) != 2 {
+		t.Errorf("Expected messages 1 and 4 acked, got %v", source.acked)
+	}
+	if len(source.nacked) != 2 || source.nacked[0] != "2" || source.nacked[1] != "3" {
+		t.Errorf("Expected messages 2 and 3 nacked in order, got %v", source.nacked)
+	}
+}
+
+func TestWatcher_SkipsDuplicates(t *testing.T) {
+	source := &recordingSource{}
+	calls := 0
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:  source,
+		Handler: func(ctx context.Context, event *EventMessage) error { calls++; return nil },
+	})
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "a", 1)})
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "a", 1)})
+
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+	if len(source.acked) != 2 {
+		t.Errorf("Expected both deliveries acked, got %v", source.acked)
+	}
+}
+
+func TestMessageRecordID(t *testing.T) {
+	if id := messageRecordID(fifoMessage("1", "", 42)); id != "record:42" {
+		t.Errorf("Expected record:42, got %q", id)
+	}
+	if id := messageRecordID(fifoMessage("1", "a", 42)); id != "a" {
+		t.Errorf("Expected GroupID to take precedence, got %q", id)
+	}
+	if id := messageRecordID(&Message{Body: []byte("not json")}); id != "" {
+		t.Errorf("Expected no key for a malformed body, got %q", id)
+	}
+}
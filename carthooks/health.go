@@ -0,0 +1,108 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxConsecutivePollErrors is how many failed receives in a row make a
+// watcher unhealthy
+const maxConsecutivePollErrors = 5
+
+// WatcherHealth is a snapshot of a Watcher's liveness
+type WatcherHealth struct {
+	Running           bool      `json:"running"`
+	LastPoll          time.Time `json:"last_poll"`
+	LastMessage       time.Time `json:"last_message"`
+	ConsecutiveErrors int       `json:"consecutive_errors"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// Check returns why the watcher is unhealthy, or nil. A watcher is healthy
+// while it runs, has received (possibly nothing) within maxPollAge, hasn't
+// failed its last receives repeatedly and its subscription hasn't lapsed.
+func (h WatcherHealth) Check(maxPollAge time.Duration) error {
+	if !h.Running {
+		return fmt.Errorf("watcher is not running")
+	}
+	if h.ConsecutiveErrors >= maxConsecutivePollErrors {
+		return fmt.Errorf("%d consecutive receive errors", h.ConsecutiveErrors)
+	}
+	if maxPollAge > 0 && time.Since(h.LastPoll) > maxPollAge {
+		return fmt.Errorf("no receive completed since %s", h.LastPoll.Format(time.RFC3339))
+	}
+	if !h.ExpiresAt.IsZero() && time.Now().After(h.ExpiresAt) {
+		return fmt.Errorf("subscription expired at %s", h.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// watcherHealth tracks poll outcomes for Health
+type watcherHealth struct {
+	mu                sync.Mutex
+	lastPoll          time.Time
+	lastMessage       time.Time
+	consecutiveErrors int
+}
+
+func (h *watcherHealth) polled(count int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastPoll = time.Now()
+	if count > 0 {
+		h.lastMessage = h.lastPoll
+	}
+	h.consecutiveErrors = 0
+}
+
+func (h *watcherHealth) pollFailed() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveErrors++
+}
+
+// Health returns a snapshot of the watcher's liveness
+func (w *Watcher) Health() WatcherHealth {
+	w.mu.Lock()
+	health := WatcherHealth{
+		Running:   w.running,
+		ExpiresAt: w.expiresAt,
+	}
+	w.mu.Unlock()
+
+	w.health.mu.Lock()
+	health.LastPoll = w.health.lastPoll
+	health.LastMessage = w.health.lastMessage
+	health.ConsecutiveErrors = w.health.consecutiveErrors
+	w.health.mu.Unlock()
+
+	return health
+}
+
+// HealthHandler returns an http.Handler for liveness probes such as
+// /healthz. It responds 200 with the Health snapshot as JSON, or 503 when
+// Check(maxPollAge) fails.
+func (w *Watcher) HealthHandler(maxPollAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		health := w.Health()
+		response := struct {
+			WatcherHealth
+			Status string `json:"status"`
+			Reason string `json:"reason,omitempty"`
+		}{WatcherHealth: health, Status: "ok"}
+
+		status := http.StatusOK
+		if err := health.Check(maxPollAge); err != nil {
+			status = http.StatusServiceUnavailable
+			response.Status = "unhealthy"
+			response.Reason = err.Error()
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		json.NewEncoder(rw).Encode(response)
+	})
+}
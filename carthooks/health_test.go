@@ -0,0 +1,66 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWatcherHealth_Check(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		health  WatcherHealth
+		healthy bool
+	}{
+		{"healthy", WatcherHealth{Running: true, LastPoll: now}, true},
+		{"stopped", WatcherHealth{Running: false, LastPoll: now}, false},
+		{"stale poll", WatcherHealth{Running: true, LastPoll: now.Add(-time.Hour)}, false},
+		{"poll errors", WatcherHealth{Running: true, LastPoll: now, ConsecutiveErrors: 5}, false},
+		{"expired", WatcherHealth{Running: true, LastPoll: now, ExpiresAt: now.Add(-time.Minute)}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.health.Check(time.Minute)
+			if (err == nil) != tc.healthy {
+				t.Errorf("Expected healthy=%v, got %v", tc.healthy, err)
+			}
+		})
+	}
+}
+
+func TestWatcher_HealthHandler(t *testing.T) {
+	watcher, _ := NewWatcher(&WatcherConfig{Source: &recordingSource{}})
+	handler := watcher.HealthHandler(time.Minute)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before Run, got %d", rec.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for watcher.Health().LastPoll.IsZero() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 while running, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var body map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body["status"] != "ok" || body["running"] != true {
+		t.Errorf("Unexpected body %v", body)
+	}
+}
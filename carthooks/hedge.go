@@ -0,0 +1,173 @@
+package carthooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeWindow     = 100
+	minHedgeSamples        = 10
+	defaultHedgeFixedDelay = 100 * time.Millisecond
+)
+
+// HedgeConfig enables request hedging for GET requests: if a response has
+// not arrived after the hedge delay, a second identical request is sent and
+// whichever responds first is used. This trims tail latency on read paths
+// at the cost of some duplicate requests.
+type HedgeConfig struct {
+	// Delay before sending the hedge request (default 100ms). With
+	// Percentile set it is used until enough latencies have been observed.
+	Delay time.Duration
+	// Percentile, e.g. 0.95, derives the delay from recently observed
+	// response latencies instead
+	Percentile float64
+	// Window is how many recent latencies the percentile is computed over
+	// (default 100)
+	Window int
+}
+
+// hedger sends GET requests with hedging and tracks response latencies
+type hedger struct {
+	config HedgeConfig
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+func newHedger(config *HedgeConfig) *hedger {
+	if config == nil {
+		return nil
+	}
+	h := &hedger{config: *config}
+	if h.config.Window <= 0 {
+		h.config.Window = defaultHedgeWindow
+	}
+	if h.config.Delay <= 0 {
+		h.config.Delay = defaultHedgeFixedDelay
+	}
+	return h
+}
+
+// delay returns how long to wait before sending the hedge request
+func (h *hedger) delay() time.Duration {
+	if h.config.Percentile <= 0 {
+		return h.config.Delay
+	}
+
+	h.mu.Lock()
+	samples := append([]time.Duration(nil), h.latencies...)
+	h.mu.Unlock()
+	if len(samples) < minHedgeSamples {
+		return h.config.Delay
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	index := int(h.config.Percentile * float64(len(samples)-1))
+	if index >= len(samples) {
+		index = len(samples) - 1
+	}
+	return samples[index]
+}
+
+// observe records the latency of a response
+func (h *hedger) observe(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.latencies) < h.config.Window {
+		h.latencies = append(h.latencies, latency)
+		return
+	}
+	h.latencies[h.next] = latency
+	h.next = (h.next + 1) % h.config.Window
+}
+
+// hedgeAttempt is the outcome of one of the hedged requests
+type hedgeAttempt struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// wrap returns a RoundTripFunc that hedges GET requests sent with send
+func (h *hedger) wrap(send RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			return send(req)
+		}
+
+		start := time.Now()
+		results := make(chan hedgeAttempt, 2)
+		var cancels []context.CancelFunc
+		launch := func() {
+			ctx, cancel := context.WithCancel(req.Context())
+			index := len(cancels)
+			cancels = append(cancels, cancel)
+			go func() {
+				resp, err := send(req.Clone(ctx))
+				results <- hedgeAttempt{index: index, resp: resp, err: err}
+			}()
+		}
+
+		launch()
+		inFlight := 1
+		timer := time.NewTimer(h.delay())
+		defer timer.Stop()
+
+		var lastErr error
+		for {
+			select {
+			case <-timer.C:
+				launch()
+				inFlight++
+				continue
+			case attempt := <-results:
+				inFlight--
+				if attempt.err != nil {
+					cancels[attempt.index]()
+					lastErr = attempt.err
+					if inFlight == 0 {
+						return nil, lastErr
+					}
+					continue
+				}
+
+				h.observe(time.Since(start))
+				// Cancel and discard the slower request, if any
+				timer.Stop()
+				for i, cancel := range cancels {
+					if i != attempt.index {
+						cancel()
+					}
+				}
+				if inFlight > 0 {
+					go func() {
+						if loser := <-results; loser.resp != nil {
+							loser.resp.Body.Close()
+						}
+					}()
+				}
+				attempt.resp.Body = &cancelOnClose{ReadCloser: attempt.resp.Body, cancel: cancels[attempt.index]}
+				return attempt.resp, nil
+			}
+		}
+	}
+}
+
+// cancelOnClose releases a request's context once its response body is
+// closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
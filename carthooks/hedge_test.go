@@ -0,0 +1,60 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_HedgesSlowReads(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if n == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}
+		fmt.Fprintf(w, `{"data":{"attempt":%d}}`, n)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Hedging: &HedgeConfig{Delay: 20 * time.Millisecond}})
+
+	start := time.Now()
+	result := client.GetApp(1)
+	if !result.Success || result.Data.(map[string]interface{})["attempt"] != float64(2) {
+		t.Fatalf("Expected the hedge request's response, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected hedging to cut latency, took %s", elapsed)
+	}
+
+	atomic.StoreInt32(&requests, 10)
+	client.CreateItem(1, 2, map[string]interface{}{})
+	if got := atomic.LoadInt32(&requests); got != 11 {
+		t.Errorf("Expected writes not to be hedged, got %d requests", got-10)
+	}
+}
+
+func TestHedger_PercentileDelay(t *testing.T) {
+	h := newHedger(&HedgeConfig{Delay: time.Second, Percentile: 0.9, Window: 20})
+	for i := 1; i <= 5; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+	if d := h.delay(); d != time.Second {
+		t.Errorf("Expected the fixed delay until enough samples, got %s", d)
+	}
+
+	for i := 6; i <= 30; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+	// The window holds the last 20 samples, 11ms..30ms
+	if d := h.delay(); d != 28*time.Millisecond {
+		t.Errorf("Expected p90 of 28ms, got %s", d)
+	}
+}
@@ -0,0 +1,347 @@
+package carthooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ImportFormat is the encoding of an import's input
+type ImportFormat int
+
+const (
+	// ImportCSV reads CSV with a header row naming the columns
+	ImportCSV ImportFormat = iota
+	// ImportNDJSON reads one JSON object per line
+	ImportNDJSON
+)
+
+const defaultImportBatchSize = 100
+
+// ImportConfig configures an Importer
+type ImportConfig struct {
+	AppID        uint
+	CollectionID uint
+	Format       ImportFormat
+	// Mapping maps input columns (CSV headers or NDJSON keys) to field
+	// keys. Columns without a mapping are skipped; a nil Mapping imports
+	// every column under its own name.
+	Mapping map[string]string
+	// UpsertKey is the field that identifies existing items: rows whose
+	// value matches an item update it, the others create items. Empty
+	// creates an item for every row.
+	UpsertKey string
+	// ValidateSchema checks that every mapped field and UpsertKey exists in
+	// the collection's schema before importing anything
+	ValidateSchema bool
+	// BatchSize is how many rows are looked up and written together
+	// (default 100)
+	BatchSize int
+	// Transform, if set, adjusts each row's data before it is written.
+	// An error fails the row.
+	Transform func(data map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Importer loads CSV or NDJSON into a collection. Empty CSV cells are left
+// out of the written data, so updates keep the item's existing values.
+type Importer struct {
+	client *Client
+	config ImportConfig
+}
+
+// NewImporter creates an importer writing with client
+func NewImporter(client *Client, config ImportConfig) *Importer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultImportBatchSize
+	}
+	return &Importer{client: client, config: config}
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Rows    int
+	Created int
+	Updated int
+	// Errors lists the rows that were not imported, in input order
+	Errors []*ImportRowError
+}
+
+// Err joins the row errors with errors.Join, or returns nil if every row
+// was imported
+func (r *ImportReport) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
+
+// ImportRowError is the error of one input row
+type ImportRowError struct {
+	// Row is the 1-based CSV row, not counting the header, or NDJSON line
+	Row int
+	Err error
+}
+
+// Error implements the error interface
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// Unwrap returns the row's error
+func (e *ImportRowError) Unwrap() error {
+	return e.Err
+}
+
+// importRow is a parsed input row
+type importRow struct {
+	number int
+	data   map[string]interface{}
+}
+
+// Import reads every row from r and writes it to the collection. Row
+// failures are collected in the report; the returned error is for problems
+// that stop the import, such as unreadable input or a failed schema check,
+// and is returned with the report of the rows processed so far.
+func (im *Importer) Import(ctx context.Context, r io.Reader) (*ImportReport, error) {
+	report := &ImportReport{}
+	client := im.client.WithContext(ctx)
+
+	if im.config.ValidateSchema {
+		if err := im.validateSchema(client); err != nil {
+			return report, err
+		}
+	}
+
+	var batch []importRow
+	keys := map[string]bool{}
+	flush := func() error {
+		if len(batch) > 0 {
+			im.writeBatch(client, batch, report)
+		}
+		batch = batch[:0]
+		keys = map[string]bool{}
+		return ctx.Err()
+	}
+
+	err := im.readRows(r, func(row importRow, err error) error {
+		report.Rows++
+		if err == nil && im.config.Transform != nil {
+			row.data, err = im.config.Transform(row.data)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, &ImportRowError{Row: row.number, Err: err})
+			return nil
+		}
+
+		// A key seen earlier in the batch may not exist yet, so write the
+		// batch first to update the item it creates
+		if key := im.upsertValue(row.data); key != "" {
+			if keys[key] {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			keys[key] = true
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= im.config.BatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Row < report.Errors[j].Row })
+	return report, err
+}
+
+// validateSchema checks the mapped fields against the collection's schema
+func (im *Importer) validateSchema(client *Client) error {
+	var collection Collection
+	if err := client.GetCollection(im.config.AppID, im.config.CollectionID).Decode(&collection); err != nil {
+		return fmt.Errorf("failed to fetch collection schema: %w", err)
+	}
+	fields := map[string]bool{}
+	for _, field := range collection.Fields {
+		fields[field.Key] = true
+	}
+
+	var unknown []string
+	for _, key := range im.config.Mapping {
+		if !fields[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if im.config.UpsertKey != "" && !fields[im.config.UpsertKey] {
+		unknown = append(unknown, im.config.UpsertKey)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("fields not in collection schema: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// readRows parses r in the configured format, calling fn with each row or
+// the row's parse error. An error from fn stops reading.
+func (im *Importer) readRows(r io.Reader, fn func(row importRow, err error) error) error {
+	switch im.config.Format {
+	case ImportCSV:
+		return im.readCSV(r, fn)
+	case ImportNDJSON:
+		return im.readNDJSON(r, fn)
+	}
+	return fmt.Errorf("unknown import format %d", im.config.Format)
+}
+
+func (im *Importer) readCSV(r io.Reader, fn func(row importRow, err error) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		row := importRow{number: number}
+		if err == nil {
+			values := map[string]interface{}{}
+			for i, value := range record {
+				if i < len(header) && value != "" {
+					values[header[i]] = value
+				}
+			}
+			row.data = im.mapColumns(values)
+		}
+		if err := fn(row, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (im *Importer) readNDJSON(r io.Reader, fn func(row importRow, err error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		row := importRow{number: number}
+		var values map[string]interface{}
+		err := json.Unmarshal([]byte(line), &values)
+		if err == nil {
+			row.data = im.mapColumns(values)
+		}
+		if err := fn(row, err); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+	return nil
+}
+
+// mapColumns renames input columns to field keys per Mapping
+func (im *Importer) mapColumns(values map[string]interface{}) map[string]interface{} {
+	if im.config.Mapping == nil {
+		return values
+	}
+	data := make(map[string]interface{}, len(im.config.Mapping))
+	for column, key := range im.config.Mapping {
+		if value, ok := values[column]; ok {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// upsertValue returns a row's upsert key value as a string, or "" if it
+// has none
+func (im *Importer) upsertValue(data map[string]interface{}) string {
+	if im.config.UpsertKey == "" {
+		return ""
+	}
+	return flattenValue(data[im.config.UpsertKey], ",")
+}
+
+// writeBatch looks up the batch's upsert keys, then updates the rows that
+// match an item and creates the others
+func (im *Importer) writeBatch(client *Client, batch []importRow, report *ImportReport) {
+	existing := map[string]uint{}
+	if im.config.UpsertKey != "" {
+		var values []interface{}
+		for _, row := range batch {
+			if value := im.upsertValue(row.data); value != "" {
+				values = append(values, row.data[im.config.UpsertKey])
+			}
+		}
+		if len(values) > 0 {
+			records, err := client.QueryItems(im.config.AppID, im.config.CollectionID, &QueryOptions{
+				Pagination: &PaginationOptions{Page: 1, PageSize: len(values)},
+				Filters: map[string]interface{}{
+					im.config.UpsertKey: map[string]interface{}{"$in": values},
+				},
+			}).Records()
+			if err != nil {
+				for _, row := range batch {
+					report.Errors = append(report.Errors, &ImportRowError{Row: row.number, Err: fmt.Errorf("failed to look up %s: %w", im.config.UpsertKey, err)})
+				}
+				return
+			}
+			for _, record := range records {
+				existing[flattenValue(record.Fields[im.config.UpsertKey], ",")] = record.ID
+			}
+		}
+	}
+
+	var creates []map[string]interface{}
+	var createRows []int
+	var updates []ItemUpdate
+	var updateRows []int
+	for _, row := range batch {
+		if itemID, ok := existing[im.upsertValue(row.data)]; ok && im.config.UpsertKey != "" {
+			updates = append(updates, ItemUpdate{ItemID: itemID, Data: row.data})
+			updateRows = append(updateRows, row.number)
+		} else {
+			creates = append(creates, row.data)
+			createRows = append(createRows, row.number)
+		}
+	}
+
+	for _, item := range client.BulkCreateItems(im.config.AppID, im.config.CollectionID, creates).Items {
+		if item.Err != nil {
+			report.Errors = append(report.Errors, &ImportRowError{Row: createRows[item.Index], Err: item.Err})
+		} else {
+			report.Created++
+		}
+	}
+	for _, item := range client.BulkUpdateItems(im.config.AppID, im.config.CollectionID, updates).Items {
+		if item.Err != nil {
+			report.Errors = append(report.Errors, &ImportRowError{Row: updateRows[item.Index], Err: item.Err})
+		} else {
+			report.Updated++
+		}
+	}
+}
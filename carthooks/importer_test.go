@@ -0,0 +1,147 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// importServer stores items keyed by their "sku" field
+type importServer struct {
+	mu     sync.Mutex
+	nextID uint
+	items  map[uint]map[string]interface{}
+}
+
+func (s *importServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body struct {
+		Data    map[string]interface{} `json:"data"`
+		Filters map[string]interface{} `json:"filters"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	switch {
+	case r.URL.Path == "/v1/apps/1/collections/2":
+		fmt.Fprint(w, `{"data":{"id":2,"fields":[{"key":"sku"},{"key":"name"}]}}`)
+	case strings.HasSuffix(r.URL.Path, "/items/query"):
+		values := body.Filters["sku"].(map[string]interface{})["$in"].([]interface{})
+		var records []RecordFormat
+		for id, fields := range s.items {
+			for _, value := range values {
+				if fields["sku"] == value {
+					records = append(records, RecordFormat{ID: id, Fields: fields})
+				}
+			}
+		}
+		data, _ := json.Marshal(records)
+		fmt.Fprintf(w, `{"data":%s}`, data)
+	case r.Method == "POST":
+		if body.Data["name"] == "bad" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"error":{"message":"invalid name","code":"VALIDATION_ERROR"}}`)
+			return
+		}
+		s.nextID++
+		s.items[s.nextID] = body.Data
+		fmt.Fprintf(w, `{"data":{"id":%d}}`, s.nextID)
+	case r.Method == "PUT":
+		var id uint
+		fmt.Sscanf(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], "%d", &id)
+		for k, v := range body.Data {
+			s.items[id][k] = v
+		}
+		fmt.Fprintf(w, `{"data":{"id":%d}}`, id)
+	}
+}
+
+func TestImporter_CSVUpsert(t *testing.T) {
+	server := &importServer{nextID: 1, items: map[uint]map[string]interface{}{
+		1: {"sku": "A", "name": "old"},
+	}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	importer := NewImporter(NewClient(&ClientConfig{BaseURL: ts.URL}), ImportConfig{
+		AppID:          1,
+		CollectionID:   2,
+		Format:         ImportCSV,
+		Mapping:        map[string]string{"SKU": "sku", "Name": "name"},
+		UpsertKey:      "sku",
+		ValidateSchema: true,
+	})
+
+	input := "SKU,Name,Ignored\nA,new,x\nB,first\nC,bad\nB,second\n\"D,broken\n"
+	report, err := importer.Import(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Import() returned %v", err)
+	}
+
+	if report.Rows != 5 || report.Created != 1 || report.Updated != 2 || len(report.Errors) != 2 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	if report.Errors[0].Row != 3 || !errors.Is(report.Errors[0], ErrValidation) || report.Errors[1].Row != 5 {
+		t.Errorf("Unexpected row errors %v", report.Err())
+	}
+	if server.items[1]["name"] != "new" || len(server.items) != 2 || server.items[2]["name"] != "second" {
+		t.Errorf("Unexpected items %v", server.items)
+	}
+	if _, ok := server.items[1]["Ignored"]; ok {
+		t.Error("Expected unmapped column to be skipped")
+	}
+}
+
+func TestImporter_NDJSON(t *testing.T) {
+	server := &importServer{items: map[uint]map[string]interface{}{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	importer := NewImporter(NewClient(&ClientConfig{BaseURL: ts.URL}), ImportConfig{
+		AppID:        1,
+		CollectionID: 2,
+		Format:       ImportNDJSON,
+		BatchSize:    1,
+	})
+
+	input := `{"sku":"A","name":"a"}
+
+not json
+{"sku":"B","name":"b"}
+`
+	report, err := importer.Import(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Import() returned %v", err)
+	}
+	if report.Rows != 3 || report.Created != 2 || len(report.Errors) != 1 || report.Errors[0].Row != 3 {
+		t.Errorf("Unexpected report %+v, %v", report, report.Err())
+	}
+}
+
+func TestImporter_ValidateSchema(t *testing.T) {
+	server := &importServer{items: map[uint]map[string]interface{}{}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	importer := NewImporter(NewClient(&ClientConfig{BaseURL: ts.URL}), ImportConfig{
+		AppID:          1,
+		CollectionID:   2,
+		Mapping:        map[string]string{"Name": "name", "Price": "price"},
+		ValidateSchema: true,
+	})
+
+	_, err := importer.Import(context.Background(), strings.NewReader("Name,Price\na,1\n"))
+	if err == nil || !strings.Contains(err.Error(), "price") {
+		t.Errorf("Expected schema error naming price, got %v", err)
+	}
+	if len(server.items) != 0 {
+		t.Error("Expected nothing to be imported")
+	}
+}
@@ -1,62 +1,138 @@
 package carthooks
 
+import (
+	"context"
+	"time"
+)
+
 // ClientInterface defines the interface for Carthooks SDK client
-// This interface allows for easy mocking in tests
+// This interface allows for easy mocking in tests. Code that only needs part
+// of the API can depend on one of the smaller interfaces it is composed of.
 type ClientInterface interface {
 	// Basic client methods
 	SetAccessToken(token string)
 	GetBaseURL() string
-	
-	// OAuth methods
+
+	OAuthAPI
+	ItemsAPI
+	ConnectionsAPI
+	WatchAPI
+	FilesAPI
+	UsersAPI
+	AppsAPI
+}
+
+// OAuthAPI covers OAuth token management and the current user
+type OAuthAPI interface {
 	GetOAuthToken(request *OAuthTokenRequest) *Result
 	RefreshOAuthToken(refreshToken ...string) *Result
 	InitializeOAuth(userAccessToken ...string) *Result
 	ExchangeAuthorizationCode(code, redirectURI string) *Result
 	GetOAuthAuthorizeCode(request *OAuthAuthorizeCodeRequest) *Result
+	GetConnectionToken(connectionID uint) *Result
+	CreateDevClient(request *CreateDevClientRequest) *Result
+	ListDevClients() *Result
+	RotateDevClientSecret(devClientID uint, gracePeriod time.Duration) *Result
+	RevokeDevClient(devClientID uint) *Result
 	GetCurrentUser() *Result
 	GetUserTenants() *Result
 	EnsureValidToken() error
 	GetCurrentTokens() *OAuthTokens
 	SetOAuthConfig(config *OAuthConfig)
 	GetOAuthConfig() *OAuthConfig
-	
-	// Collection/Item methods
+}
+
+// ItemsAPI covers collection items, their subform items, locks, external
+// IDs and bulk operations
+type ItemsAPI interface {
 	GetItems(appID, collectionID uint, limit, start int, options map[string]string) *Result
 	GetItemByID(appID, collectionID, itemID uint, fields []string) *Result
+	GetItemByIDIfChanged(appID, collectionID, itemID uint, fields []string, etag string) *Result
 	QueryItems(appID, collectionID uint, options *QueryOptions) *Result
 	CreateItem(appID, collectionID uint, data map[string]interface{}) *Result
 	UpdateItem(appID, collectionID, itemID uint, data map[string]interface{}) *Result
 	DeleteItem(appID, collectionID, itemID uint) *Result
 	LockItem(appID, collectionID, itemID uint, options *LockOptions) *Result
 	UnlockItem(appID, collectionID, itemID uint, lockID string) *Result
-	
-	// SubItem methods
+	ForceUnlockItem(appID, collectionID, itemID uint, reason string) *Result
+	GetItemLock(appID, collectionID, itemID uint) *Result
+	TryLockItem(appID, collectionID, itemID uint, options *LockOptions, retry *LockRetryOptions) (*Result, error)
+	CreateItemWithExternalID(appID, collectionID uint, data map[string]interface{}) *Result
+	GetItemByExternalID(appID, collectionID uint, externalID string) *Result
+	NewExternalID() (string, error)
+	BulkCreateItems(appID, collectionID uint, items []map[string]interface{}) *BulkResult
+	BulkUpdateItems(appID, collectionID uint, updates []ItemUpdate) *BulkResult
+	BulkDeleteItems(appID, collectionID uint, itemIDs []uint) *BulkResult
+	SubmitExportJob(appID, collectionID uint, options *ExportJobOptions) *Result
+	GetExportJobStatus(jobID string) *Result
+
 	CreateSubItem(appID, collectionID, itemID, fieldID uint, data map[string]interface{}) *Result
 	UpdateSubItem(appID, collectionID, itemID, fieldID, subItemID uint, data map[string]interface{}) *Result
 	DeleteSubItem(appID, collectionID, itemID, fieldID, subItemID uint) *Result
-	
-	// Connection methods
+
+	GetSubmissionToken(appID, collectionID uint, options *SubmissionTokenOptions) *Result
+	UpdateSubmissionToken(appID, collectionID, itemID uint, options *UpdateTokenOptions) *Result
+}
+
+// ConnectionsAPI covers hooklet connections, their logs and usage
+type ConnectionsAPI interface {
 	CreateConnection(appID uint, request *CreateConnectionRequest) *Result
 	UpdateConnection(appID, connectionID uint, request *UpdateConnectionRequest) *Result
 	GetConnection(appID, connectionID uint) *Result
+	ListConnections(appID uint, filters map[string]string, pagination *PaginationOptions) *Result
+	SetConnectionStatus(appID, connectionID uint, status ConnectionStatus) *Result
 	DeleteConnection(appID, connectionID uint) *Result
 	CreateConnectionLog(appID, connectionID uint, request *CreateConnectionLogRequest) *Result
+	GetConnectionLogs(appID, connectionID uint, status ConnectionLogStatus, pagination *PaginationOptions) *Result
 	CreateConnectionUsage(appID, connectionID uint, request *CreateConnectionUsageRequest) *Result
-	
-	// Advanced methods
-	GetSubmissionToken(appID, collectionID uint, options *SubmissionTokenOptions) *Result
-	UpdateSubmissionToken(appID, collectionID, itemID uint, options *UpdateTokenOptions) *Result
+	GetConnectionUsage(appID, connectionID uint, from, to time.Time, granularity UsageGranularity) *Result
+	RegisterConnectionWebhook(appID, connectionID uint, request *RegisterConnectionWebhookRequest) *Result
+	ListConnectionWebhooks(appID, connectionID uint) *Result
+	UnregisterConnectionWebhook(appID, connectionID uint, webhookID string) *Result
+	CreateVendorTask(request *CreateVendorTaskRequest) *Result
+	GetVendorTask(taskID string) *Result
+	CompleteVendorTask(taskID string, request *CompleteVendorTaskRequest) *Result
+}
+
+// WatchAPI covers watches and event streaming
+type WatchAPI interface {
+	StartWatchData(options *WatchDataOptions) *Result
+	StopWatchData(options *WatchDataOptions) *Result
+	ListWatches(appID, collectionID uint) *Result
+	GetWatch(watchID string) *Result
+	UpdateWatchData(watchID string, options *WatchDataOptions) *Result
+	PauseWatch(watchID string) *Result
+	ResumeWatch(watchID string) *Result
+	DeleteWatch(watchID string) *Result
+	SubscribeSSE(ctx context.Context, appID, collectionID uint, filters map[string]interface{}, handler func(event *EventMessage) error) error
+}
+
+// FilesAPI covers file uploads
+type FilesAPI interface {
 	GetUploadToken() *Result
+}
+
+// UsersAPI covers looking up users
+type UsersAPI interface {
 	GetUser(userID uint) *Result
 	GetUserByToken(token string) *Result
-	StartWatchData(options *WatchDataOptions) *Result
-	StopWatchData(options *WatchDataOptions) *Result
+	ListTenantUsers(pagination *PaginationOptions) *Result
+	InviteUser(request *InviteUserRequest) *Result
+	RemoveUser(userID uint) *Result
+	UpdateUserRole(userID uint, role TenantRole) *Result
+	GetAuditLogs(filter *AuditLogFilter, pagination *PaginationOptions) *Result
+	GetTenantUsage(from, to time.Time) *Result
+	GetQuotaStatus() *Result
+}
+
+// AppsAPI covers apps and their collections
+type AppsAPI interface {
 	GetCollections(appID uint) *Result
 	GetCollection(appID, collectionID uint) *Result
+	GetCollectionIfChanged(appID, collectionID uint, etag string) *Result
 	GetApps() *Result
 	GetApp(appID uint) *Result
 }
 
 // Ensure Client implements ClientInterface
 var _ ClientInterface = (*Client)(nil)
-
@@ -0,0 +1,284 @@
+// Package kafkasource provides a Kafka carthooks.MessageSource so a Watcher
+// can consume EventMessage records from a topic with consumer-group semantics.
+package kafkasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/segmentio/kafka-go"
+)
+
+// Config holds configuration for a Kafka source
+type Config struct {
+	Brokers []string
+	Topic   string
+	// GroupID is the consumer group; partitions are balanced across all
+	// watchers sharing it and offsets are committed on Ack
+	GroupID  string
+	MinBytes int           // defaults to 1
+	MaxBytes int           // defaults to 10MB
+	MaxWait  time.Duration // defaults to 10s
+	// BatchSize is the maximum number of records per Receive (default 10)
+	BatchSize int
+	// BatchWait is how long Receive waits for more records once it has
+	// the first of a batch (default 100ms)
+	BatchWait time.Duration
+	// RedeliveryDelay is how long a nacked record is held before it is
+	// delivered again (default 1s). It doubles each time the same record is
+	// nacked, up to MaxRedeliveryDelay (default 1m).
+	RedeliveryDelay    time.Duration
+	MaxRedeliveryDelay time.Duration
+}
+
+// reader is the part of *kafka.Reader the source uses
+type reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Source is a carthooks.MessageSource backed by a Kafka consumer group.
+// Kafka offsets are cumulative, so a partition's offset is only committed up
+// to the oldest record not yet acked; nacked records are redelivered by the
+// source itself and hold back their partition's commits until acked.
+type Source struct {
+	config Config
+	reader reader
+
+	mu         sync.Mutex
+	partitions map[int][]*pendingRecord // fetched records not yet committed
+	redeliver  []*redelivery            // nacked records, oldest first
+}
+
+// pendingRecord is a fetched record awaiting its ack
+type pendingRecord struct {
+	record kafka.Message
+	acked  bool
+	nacks  int
+}
+
+// redelivery is a nacked record held until its redelivery time
+type redelivery struct {
+	record    kafka.Message
+	notBefore time.Time
+}
+
+// New creates a Kafka source
+func New(config Config) (*Source, error) {
+	if len(config.Brokers) == 0 || config.Topic == "" || config.GroupID == "" {
+		return nil, fmt.Errorf("kafka source requires brokers, topic and group ID")
+	}
+
+	minBytes := config.MinBytes
+	if minBytes <= 0 {
+		minBytes = 1
+	}
+	maxBytes := config.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = 10e6
+	}
+	maxWait := config.MaxWait
+	if maxWait <= 0 {
+		maxWait = 10 * time.Second
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  config.Brokers,
+		Topic:    config.Topic,
+		GroupID:  config.GroupID,
+		MinBytes: minBytes,
+		MaxBytes: maxBytes,
+		MaxWait:  maxWait,
+	})
+
+	return newSource(reader, config), nil
+}
+
+func newSource(reader reader, config Config) *Source {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = 100 * time.Millisecond
+	}
+	if config.RedeliveryDelay <= 0 {
+		config.RedeliveryDelay = time.Second
+	}
+	if config.MaxRedeliveryDelay <= 0 {
+		config.MaxRedeliveryDelay = time.Minute
+	}
+	return &Source{config: config, reader: reader, partitions: map[int][]*pendingRecord{}}
+}
+
+// Receive returns the nacked records due for redelivery or, if there are
+// none, fetches up to BatchSize records from the topic. While it waits for
+// the topic it wakes up to return nacked records as they fall due.
+func (s *Source) Receive(ctx context.Context) ([]*carthooks.Message, error) {
+	for {
+		messages, wait := s.dueRedeliveries()
+		if len(messages) > 0 {
+			return messages, nil
+		}
+
+		fetchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if wait > 0 {
+			fetchCtx, cancel = context.WithTimeout(ctx, wait)
+		}
+		record, err := s.reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if wait > 0 && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				continue // a nacked record is due
+			}
+			return nil, err
+		}
+		return s.fetchBatch(ctx, record), nil
+	}
+}
+
+// dueRedeliveries removes up to BatchSize nacked records whose delay has
+// passed, and returns how long until the next of the rest is due
+func (s *Source) dueRedeliveries() ([]*carthooks.Message, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var messages []*carthooks.Message
+	var wait time.Duration
+	held := s.redeliver[:0]
+	for _, r := range s.redeliver {
+		if !r.notBefore.After(now) && len(messages) < s.config.BatchSize {
+			messages = append(messages, message(r.record))
+			continue
+		}
+		held = append(held, r)
+		if until := r.notBefore.Sub(now); wait == 0 || until < wait {
+			wait = until
+		}
+	}
+	s.redeliver = held
+	if len(held) > 0 && wait <= 0 {
+		wait = time.Millisecond
+	}
+	return messages, wait
+}
+
+// fetchBatch tracks first and any further records fetched within BatchWait,
+// up to BatchSize
+func (s *Source) fetchBatch(ctx context.Context, first kafka.Message) []*carthooks.Message {
+	records := []kafka.Message{first}
+	batchCtx, cancel := context.WithTimeout(ctx, s.config.BatchWait)
+	defer cancel()
+	for len(records) < s.config.BatchSize {
+		// Records already fetched must be returned to be acked, so a failed
+		// fetch only ends the batch; the error recurs on the next Receive
+		record, err := s.reader.FetchMessage(batchCtx)
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := make([]*carthooks.Message, len(records))
+	for i, record := range records {
+		s.partitions[record.Partition] = append(s.partitions[record.Partition], &pendingRecord{record: record})
+		messages[i] = message(record)
+	}
+	return messages
+}
+
+// message wraps a record for the Watcher
+func message(record kafka.Message) *carthooks.Message {
+	attributes := make(map[string]string, len(record.Headers)+2)
+	for _, header := range record.Headers {
+		attributes[header.Key] = string(header.Value)
+	}
+	attributes["partition"] = strconv.Itoa(record.Partition)
+	attributes["offset"] = strconv.FormatInt(record.Offset, 10)
+
+	return &carthooks.Message{
+		ID:         fmt.Sprintf("%s/%d/%d", record.Topic, record.Partition, record.Offset),
+		Body:       record.Value,
+		Attributes: attributes,
+		Raw:        record,
+	}
+}
+
+// Ack marks the record as processed and commits its partition's offset past
+// every record acked without a gap
+func (s *Source) Ack(ctx context.Context, message *carthooks.Message) error {
+	record, ok := message.Raw.(kafka.Message)
+	if !ok {
+		return fmt.Errorf("not a Kafka message")
+	}
+
+	// Commits are made under the lock so they reach Kafka in order
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.partitions[record.Partition]
+	for _, p := range pending {
+		if p.record.Offset == record.Offset {
+			p.acked = true
+		}
+	}
+
+	var commit *kafka.Message
+	for len(pending) > 0 && pending[0].acked {
+		commit = &pending[0].record
+		pending = pending[1:]
+	}
+	if commit == nil {
+		return nil
+	}
+	if err := s.reader.CommitMessages(ctx, *commit); err != nil {
+		return err
+	}
+	s.partitions[record.Partition] = pending
+	return nil
+}
+
+// Nack queues the record for redelivery by Receive once its redelivery
+// delay has passed. Its partition's offset is not committed past it until it
+// is acked, so it is also redelivered by Kafka if the group rebalances or
+// restarts first.
+func (s *Source) Nack(ctx context.Context, message *carthooks.Message) error {
+	record, ok := message.Raw.(kafka.Message)
+	if !ok {
+		return fmt.Errorf("not a Kafka message")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nacks := 1
+	for _, p := range s.partitions[record.Partition] {
+		if p.record.Offset == record.Offset {
+			p.nacks++
+			nacks = p.nacks
+		}
+	}
+	delay := s.config.RedeliveryDelay
+	for i := 1; i < nacks && delay < s.config.MaxRedeliveryDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxRedeliveryDelay {
+		delay = s.config.MaxRedeliveryDelay
+	}
+
+	s.redeliver = append(s.redeliver, &redelivery{record: record, notBefore: time.Now().Add(delay)})
+	return nil
+}
+
+// Close closes the underlying reader and leaves the consumer group
+func (s *Source) Close() error {
+	return s.reader.Close()
+}
@@ -0,0 +1,178 @@
+package kafkasource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeReader serves records in order, blocks once they run out, and
+// records commits
+type fakeReader struct {
+	mu      sync.Mutex
+	records []kafka.Message
+	commits []int64
+	closed  bool
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	if len(r.records) == 0 {
+		r.mu.Unlock()
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	defer r.mu.Unlock()
+	record := r.records[0]
+	r.records = r.records[1:]
+	return record, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.commits = append(r.commits, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newFakeSource(config Config, offsets ...int64) (*Source, *fakeReader) {
+	reader := &fakeReader{}
+	for _, offset := range offsets {
+		reader.records = append(reader.records, kafka.Message{
+			Topic:     "events",
+			Partition: 0,
+			Offset:    offset,
+			Value:     []byte(`{"version":"1"}`),
+			Headers:   []kafka.Header{{Key: "trace", Value: []byte("abc")}},
+		})
+	}
+	return newSource(reader, config), reader
+}
+
+func TestSource_ReceiveAndAck(t *testing.T) {
+	source, reader := newFakeSource(Config{}, 5)
+	ctx := context.Background()
+
+	messages, err := source.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	message := messages[0]
+	if message.ID != "events/0/5" || message.Attributes["trace"] != "abc" || message.Attributes["offset"] != "5" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+
+	if err := source.Ack(ctx, message); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if len(reader.commits) != 1 || reader.commits[0] != 5 {
+		t.Errorf("Expected offset 5 committed, got %v", reader.commits)
+	}
+}
+
+func TestSource_ReceiveBatches(t *testing.T) {
+	source, _ := newFakeSource(Config{BatchSize: 2, BatchWait: 10 * time.Millisecond}, 1, 2, 3)
+	ctx := context.Background()
+
+	first, err := source.Receive(ctx)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("Expected a full batch of 2, got %d (%v)", len(first), err)
+	}
+	second, err := source.Receive(ctx)
+	if err != nil || len(second) != 1 || second[0].ID != "events/0/3" {
+		t.Fatalf("Expected the last record once BatchWait passed, got %v (%v)", second, err)
+	}
+}
+
+func TestSource_NackHoldsCommitsAndRedelivers(t *testing.T) {
+	delay := 20 * time.Millisecond
+	source, reader := newFakeSource(Config{RedeliveryDelay: delay, BatchWait: time.Millisecond}, 1, 2, 3)
+	ctx := context.Background()
+
+	received, err := source.Receive(ctx)
+	if err != nil || len(received) != 3 {
+		t.Fatalf("Expected 3 records, got %d (%v)", len(received), err)
+	}
+
+	source.Ack(ctx, received[0])
+	source.Nack(ctx, received[1])
+	nacked := time.Now()
+	source.Ack(ctx, received[2])
+	if len(reader.commits) != 1 || reader.commits[0] != 1 {
+		t.Fatalf("Expected only offset 1 committed past the nacked record, got %v", reader.commits)
+	}
+
+	messages, err := source.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if messages[0].ID != "events/0/2" {
+		t.Fatalf("Expected the nacked record redelivered, got %s", messages[0].ID)
+	}
+	if elapsed := time.Since(nacked); elapsed < delay {
+		t.Errorf("Expected the redelivery held for %s, got it after %s", delay, elapsed)
+	}
+
+	source.Ack(ctx, messages[0])
+	if len(reader.commits) != 2 || reader.commits[1] != 3 {
+		t.Errorf("Expected offset 3 committed once the nacked record was acked, got %v", reader.commits)
+	}
+}
+
+func TestSource_CommitsPerPartition(t *testing.T) {
+	reader := &fakeReader{records: []kafka.Message{
+		{Topic: "events", Partition: 0, Offset: 1},
+		{Topic: "events", Partition: 1, Offset: 7},
+	}}
+	source := newSource(reader, Config{BatchWait: time.Millisecond})
+	ctx := context.Background()
+
+	messages, _ := source.Receive(ctx)
+	source.Nack(ctx, messages[0])
+	source.Ack(ctx, messages[1])
+
+	if len(reader.commits) != 1 || reader.commits[0] != 7 {
+		t.Errorf("Expected a nack to hold back only its own partition, got %v", reader.commits)
+	}
+}
+
+func TestSource_RedeliveryDelayBacksOff(t *testing.T) {
+	source, _ := newFakeSource(Config{RedeliveryDelay: time.Second, MaxRedeliveryDelay: 3 * time.Second}, 1)
+	ctx := context.Background()
+
+	messages, _ := source.Receive(ctx)
+	var delays []time.Duration
+	for i := 0; i < 3; i++ {
+		source.Nack(ctx, messages[0])
+		delays = append(delays, time.Until(source.redeliver[i].notBefore).Round(time.Second))
+	}
+	if delays[0] != time.Second || delays[1] != 2*time.Second || delays[2] != 3*time.Second {
+		t.Errorf("Expected delays of 1s, 2s and a 3s cap, got %v", delays)
+	}
+
+	// Receive keeps waiting for the topic while nothing is due
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := source.Receive(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Receive to wait out its context, got %v", err)
+	}
+}
+
+func TestSource_Close(t *testing.T) {
+	source, reader := newFakeSource(Config{})
+	source.Close()
+	if !reader.closed {
+		t.Error("Expected Close to close the reader")
+	}
+}
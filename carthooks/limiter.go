@@ -0,0 +1,71 @@
+package carthooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// limiter caps the number of requests in flight. A request holds its slot
+// until its response body is closed, since the connection stays busy
+// while the body is read.
+type limiter struct {
+	slots chan struct{}
+}
+
+func newLimiter(max int) *limiter {
+	if max <= 0 {
+		return nil
+	}
+	return &limiter{slots: make(chan struct{}, max)}
+}
+
+// queueWaitKey carries a *int64 that the limiter adds a request's queue
+// wait to, in nanoseconds, for RequestMetric.QueueWait
+type queueWaitKey struct{}
+
+// wrap returns a RoundTripFunc that waits for a free slot, or for the
+// request's context to end, before sending with send
+func (l *limiter) wrap(send RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		select {
+		case l.slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if wait, ok := req.Context().Value(queueWaitKey{}).(*int64); ok {
+			atomic.AddInt64(wait, int64(time.Since(start)))
+		}
+
+		resp, err := send(req)
+		if err != nil || resp == nil || resp.Body == nil {
+			<-l.slots
+			return resp, err
+		}
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() { <-l.slots }}
+		return resp, nil
+	}
+}
+
+// releaseOnClose calls release once when the body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// withQueueWait returns ctx carrying a counter for the limiter's queue wait
+func withQueueWait(ctx context.Context) (context.Context, *int64) {
+	wait := new(int64)
+	return context.WithValue(ctx, queueWaitKey{}, wait), wait
+}
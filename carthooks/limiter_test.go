@@ -0,0 +1,97 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitRecorder records the queue wait of each request
+type waitRecorder struct {
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (r *waitRecorder) OnRequestStart(method, path string) {}
+
+func (r *waitRecorder) OnRequestEnd(metric RequestMetric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waits = append(r.waits, metric.QueueWait)
+}
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	recorder := &waitRecorder{}
+	client := NewClient(&ClientConfig{BaseURL: server.URL, MaxConcurrentRequests: 2, Metrics: recorder})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(id uint) {
+			defer wg.Done()
+			if err := client.GetApp(id).Err(); err != nil {
+				t.Errorf("GetApp() returned %v", err)
+			}
+		}(uint(i + 1))
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Peak of %d requests in flight, want at most 2", peak)
+	}
+	queued := 0
+	for _, wait := range recorder.waits {
+		if wait >= 10*time.Millisecond {
+			queued++
+		}
+	}
+	if len(recorder.waits) != 6 || queued < 2 {
+		t.Errorf("Expected queued requests to report their wait, got %v", recorder.waits)
+	}
+}
+
+func TestClient_MaxConcurrentRequestsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient(&ClientConfig{BaseURL: server.URL, MaxConcurrentRequests: 1})
+
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		client.GetApp(1)
+	}()
+	<-started
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.WithContext(ctx).GetApp(2).Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetApp() while the slot is taken = %v, want context.DeadlineExceeded", err)
+	}
+}
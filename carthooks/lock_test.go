@@ -0,0 +1,155 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_TryLockItem(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts < 3 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{
+				"error": {"message": "item is locked", "code": "ITEM_LOCKED"},
+				"meta": {"lock": {"lockId": "other-process", "expiresAt": 4102444800}}
+			}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"lockId": "my-process"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	options := &LockOptions{LockID: "my-process"}
+
+	_, err := client.TryLockItem(123, 456, 789, options, nil)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected ErrLocked, got %v", err)
+	}
+
+	var lockErr *LockedError
+	if !errors.As(err, &lockErr) {
+		t.Fatalf("Expected *LockedError, got %T", err)
+	}
+	if lockErr.LockID != "other-process" {
+		t.Errorf("Expected holder 'other-process', got '%s'", lockErr.LockID)
+	}
+	if !lockErr.ExpiresAt.Equal(time.Unix(4102444800, 0)) {
+		t.Errorf("Unexpected expiry %v", lockErr.ExpiresAt)
+	}
+
+	result, err := client.TryLockItem(123, 456, 789, options, &LockRetryOptions{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected lock to succeed after retry, got %v", err)
+	}
+	if !result.Success || attempts != 3 {
+		t.Errorf("Expected success on third attempt, got success=%t attempts=%d", result.Success, attempts)
+	}
+}
+
+func TestClient_TryLockItemOtherError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"message": "Item not found", "code": "NOT_FOUND"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	_, err := client.TryLockItem(123, 456, 789, nil, nil)
+	if err == nil || errors.Is(err, ErrLocked) {
+		t.Errorf("Expected non-lock error, got %v", err)
+	}
+}
+
+func TestClient_TryLockItemStopsWithContext(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error": {"message": "item is locked", "code": "ITEM_LOCKED"}}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := NewClient(&ClientConfig{BaseURL: server.URL}).WithContext(ctx)
+
+	start := time.Now()
+	_, err := client.TryLockItem(123, 456, 789, nil, &LockRetryOptions{
+		MaxAttempts:    10,
+		InitialBackoff: time.Hour,
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the wait to end with the context, took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || attempts != 1 {
+		t.Errorf("Expected a deadline error after 1 attempt, got %v after %d", err, attempts)
+	}
+}
+
+func TestClient_GetItemLock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/apps/123/collections/456/items/789/lock" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"locked": true, "lockId": "worker-7", "lockSubject": "Processing", "expiresAt": 4102444800}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var status LockStatus
+	if err := client.GetItemLock(123, 456, 789).GetData(&status); err != nil {
+		t.Fatalf("GetData() failed: %v", err)
+	}
+	if !status.Locked || status.LockID != "worker-7" || status.Subject != "Processing" {
+		t.Errorf("Unexpected lock status %+v", status)
+	}
+	if !status.ExpiresTime().Equal(time.Unix(4102444800, 0)) {
+		t.Errorf("Unexpected expiry %v", status.ExpiresTime())
+	}
+}
+
+func TestClient_ForceUnlockItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/apps/123/collections/456/items/789/force-unlock" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["reason"] != "worker crashed" {
+			t.Errorf("Expected the reason in the body, got %v (%v)", body, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"locked": false}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	if result := client.ForceUnlockItem(123, 456, 789, "worker crashed"); !result.Success {
+		t.Errorf("ForceUnlockItem() failed: %s", result.Error)
+	}
+}
+
+func TestClient_ForceUnlockItemRequiresReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	if result := client.ForceUnlockItem(123, 456, 789, ""); result.Success || result.Error == "" {
+		t.Errorf("Expected an error without a reason, got %+v", result)
+	}
+}
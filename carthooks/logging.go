@@ -0,0 +1,56 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// defaultDebugBodyLimit is how many bytes of each body are logged by default
+const defaultDebugBodyLimit = 8 << 10 // 8 KiB
+
+// newClientLogger returns the logger for a client: config.Logger if set,
+// a debug level text logger on DebugWriter (default stdout) in debug mode,
+// or slog.Default()
+func newClientLogger(config *ClientConfig, debug bool) *slog.Logger {
+	if config.Logger != nil {
+		return config.Logger
+	}
+	if debug {
+		var w io.Writer = os.Stdout
+		if config.DebugWriter != nil {
+			w = config.DebugWriter
+		}
+		return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+	return slog.Default()
+}
+
+// logBody returns body for logging, truncated to the client's debug body
+// limit
+func (c *Client) logBody(body string) string {
+	limit := c.debugBodyLimit
+	if limit == 0 {
+		limit = defaultDebugBodyLimit
+	}
+	if limit < 0 || len(body) <= limit {
+		return body
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", body[:limit], len(body)-limit)
+}
+
+// orDefaultLogger returns logger, or slog.Default() if it is nil
+func orDefaultLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// debugEnabled reports whether the client logs at debug level, so callers
+// can skip building expensive debug attributes
+func (c *Client) debugEnabled() bool {
+	return c.logger.Enabled(context.Background(), slog.LevelDebug)
+}
@@ -0,0 +1,71 @@
+package carthooks
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Logger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":1}}`)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Logger:  slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	client.CreateItem(1, 2, map[string]interface{}{"title": "hello"})
+
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="sending request" method=POST`,
+		`body="{\"data\":{\"title\":\"hello\"}}"`,
+		`msg="received response" method=POST`,
+		"status=200",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs missing %s:\n%s", want, logs)
+		}
+	}
+
+	buf.Reset()
+	client = NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		Logger:  slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+	client.CreateItem(1, 2, map[string]interface{}{"title": "hello"})
+	if buf.Len() != 0 {
+		t.Errorf("expected no logs above debug level, got:\n%s", buf.String())
+	}
+}
+
+func TestClient_DebugWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"notes":%q}}`, strings.Repeat("x", 100))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(&ClientConfig{
+		BaseURL:        server.URL,
+		Debug:          true,
+		DebugWriter:    &buf,
+		DebugBodyLimit: 20,
+	})
+	client.GetApps()
+
+	logs := buf.String()
+	if !strings.Contains(logs, `msg="response body"`) {
+		t.Fatalf("expected response body in debug writer, got:\n%s", logs)
+	}
+	if strings.Contains(logs, strings.Repeat("x", 21)) || !strings.Contains(logs, "... (") {
+		t.Errorf("expected response body truncated to 20 bytes, got:\n%s", logs)
+	}
+}
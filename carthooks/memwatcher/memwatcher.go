@@ -0,0 +1,209 @@
+// Package memwatcher provides an in-memory carthooks.MessageSource for tests.
+// Tests push EventMessages into the source and assert on handler behavior
+// without AWS credentials or a running broker.
+//
+//	source := memwatcher.New()
+//	watcher, _ := carthooks.NewWatcher(&carthooks.WatcherConfig{Source: source, Handler: handler})
+//	go watcher.Run(context.Background())
+//	defer watcher.Stop()
+//
+//	source.Push(memwatcher.Created(456, map[string]interface{}{"id": 1}))
+//	source.Drain(time.Second)
+package memwatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// Source is an in-memory carthooks.MessageSource. It is safe for concurrent use.
+type Source struct {
+	queue chan *carthooks.Message
+
+	mu      sync.Mutex
+	nextID  int
+	pending int
+	acked   []*carthooks.Message
+	nacked  []*carthooks.Message
+	settled chan struct{}
+}
+
+// New creates an empty in-memory source
+func New() *Source {
+	return &Source{
+		queue:   make(chan *carthooks.Message, 1024),
+		settled: make(chan struct{}, 1),
+	}
+}
+
+// Push enqueues an event for delivery to the watcher
+func (s *Source) Push(event *carthooks.EventMessage) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	s.PushRaw(body)
+	return nil
+}
+
+// PushRaw enqueues a raw message body, e.g. to test malformed messages
+func (s *Source) PushRaw(body []byte) {
+	s.mu.Lock()
+	s.nextID++
+	s.pending++
+	message := &carthooks.Message{
+		ID:         strconv.Itoa(s.nextID),
+		Body:       body,
+		Attributes: map[string]string{},
+	}
+	s.mu.Unlock()
+
+	s.queue <- message
+}
+
+// Receive returns the queued messages, waiting briefly if there are none
+func (s *Source) Receive(ctx context.Context) ([]*carthooks.Message, error) {
+	var messages []*carthooks.Message
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(100 * time.Millisecond):
+		return nil, nil
+	case message := <-s.queue:
+		messages = append(messages, message)
+	}
+
+	for {
+		select {
+		case message := <-s.queue:
+			messages = append(messages, message)
+		default:
+			return messages, nil
+		}
+	}
+}
+
+// Ack records the message as acknowledged
+func (s *Source) Ack(ctx context.Context, message *carthooks.Message) error {
+	s.mu.Lock()
+	s.acked = append(s.acked, message)
+	s.settle()
+	s.mu.Unlock()
+	return nil
+}
+
+// Nack records the message as rejected. It is not redelivered.
+func (s *Source) Nack(ctx context.Context, message *carthooks.Message) error {
+	s.mu.Lock()
+	s.nacked = append(s.nacked, message)
+	s.settle()
+	s.mu.Unlock()
+	return nil
+}
+
+// Close is a no-op
+func (s *Source) Close() error {
+	return nil
+}
+
+// Acked returns the messages acknowledged so far
+func (s *Source) Acked() []*carthooks.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*carthooks.Message(nil), s.acked...)
+}
+
+// Nacked returns the messages rejected so far
+func (s *Source) Nacked() []*carthooks.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*carthooks.Message(nil), s.nacked...)
+}
+
+// Drain waits until every pushed message has been acked or nacked
+func (s *Source) Drain(timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		pending := s.pending
+		s.mu.Unlock()
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-s.settled:
+		case <-deadline:
+			return fmt.Errorf("%d messages still pending after %s", pending, timeout)
+		}
+	}
+}
+
+// settle marks one message as handled; callers must hold s.mu
+func (s *Source) settle() {
+	s.pending--
+	select {
+	case s.settled <- struct{}{}:
+	default:
+	}
+}
+
+var recordIDs struct {
+	sync.Mutex
+	next uint
+}
+
+// Created fabricates a realistic collection.item.created envelope for record.
+// Missing id, created_at and updated_at fields are filled in.
+func Created(collectionID uint, record map[string]interface{}) *carthooks.EventMessage {
+	return envelope(carthooks.EventCodeRecordCreated, collectionID, record)
+}
+
+// Updated fabricates a realistic collection.item.updated envelope for record.
+// Missing id, created_at and updated_at fields are filled in.
+func Updated(collectionID uint, record map[string]interface{}) *carthooks.EventMessage {
+	return envelope(carthooks.EventCodeRecordUpdated, collectionID, record)
+}
+
+func envelope(event carthooks.EventCode, collectionID uint, record map[string]interface{}) *carthooks.EventMessage {
+	payload := make(map[string]interface{}, len(record)+3)
+	for k, v := range record {
+		payload[k] = v
+	}
+
+	if _, ok := payload["id"]; !ok {
+		recordIDs.Lock()
+		recordIDs.next++
+		payload["id"] = recordIDs.next
+		recordIDs.Unlock()
+	}
+
+	now := time.Now().Unix()
+	if _, ok := payload["updated_at"]; !ok {
+		payload["updated_at"] = now
+	}
+	if _, ok := payload["created_at"]; !ok {
+		if event == carthooks.EventCodeRecordCreated {
+			payload["created_at"] = payload["updated_at"]
+		} else {
+			payload["created_at"] = now - 3600
+		}
+	}
+
+	return &carthooks.EventMessage{
+		Version: "1",
+		Meta: carthooks.EventMessageMeta{
+			TenantID:     1,
+			CollectionID: collectionID,
+			Event:        event,
+			TriggerType:  "memwatcher",
+		},
+		Payload: payload,
+	}
+}
@@ -0,0 +1,66 @@
+package memwatcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+func TestSource_WithWatcher(t *testing.T) {
+	source := New()
+
+	var mu sync.Mutex
+	var titles []string
+	watcher, err := carthooks.NewWatcher(&carthooks.WatcherConfig{
+		Source: source,
+		Handler: func(ctx context.Context, event *carthooks.EventMessage) error {
+			mu.Lock()
+			titles = append(titles, event.Record()["title"].(string))
+			mu.Unlock()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+
+	go watcher.Run(context.Background())
+	defer watcher.Stop()
+
+	source.Push(Created(456, map[string]interface{}{"title": "first"}))
+	source.Push(Updated(456, map[string]interface{}{"title": "second"}))
+	source.PushRaw([]byte(`not json`))
+
+	if err := source.Drain(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(source.Acked()) != 2 || len(source.Nacked()) != 1 {
+		t.Errorf("Expected 2 acked and 1 nacked, got %d and %d", len(source.Acked()), len(source.Nacked()))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(titles) != 2 || titles[0] != "first" || titles[1] != "second" {
+		t.Errorf("Unexpected handler calls %v", titles)
+	}
+}
+
+func TestEnvelopes(t *testing.T) {
+	created := Created(456, map[string]interface{}{"title": "x"})
+	payload := created.Payload.(map[string]interface{})
+	if created.Meta.Event != carthooks.EventCodeRecordCreated || payload["id"] == nil {
+		t.Errorf("Unexpected created envelope %+v", created)
+	}
+	if payload["created_at"] != payload["updated_at"] {
+		t.Error("Created envelope should have equal created_at and updated_at")
+	}
+
+	updated := Updated(456, map[string]interface{}{"id": 9})
+	if updated.Meta.Event != carthooks.EventCodeRecordUpdated || updated.Payload.(map[string]interface{})["id"] != 9 {
+		t.Errorf("Unexpected updated envelope %+v", updated)
+	}
+}
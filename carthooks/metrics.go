@@ -0,0 +1,144 @@
+package carthooks
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatcherMetrics receives Watcher instrumentation. Implementations must be
+// safe for concurrent use; see the promwatcher package for a Prometheus
+// adapter.
+type WatcherMetrics interface {
+	// MessagesReceived is called for each non-empty receive with the batch size
+	MessagesReceived(count int)
+	// EmptyPoll is called for each receive that returned no messages
+	EmptyPoll()
+	// PollError is called when receiving from the source fails
+	PollError()
+	// MessageProcessed is called for each successfully handled message
+	MessageProcessed()
+	// MessageFailed is called for each message that failed processing
+	MessageFailed()
+	// HandlerDuration is called with the duration of each Handler or
+	// BatchHandler invocation
+	HandlerDuration(duration time.Duration)
+}
+
+// noopMetrics discards all measurements
+type noopMetrics struct{}
+
+func (noopMetrics) MessagesReceived(count int)             {}
+func (noopMetrics) EmptyPoll()                             {}
+func (noopMetrics) PollError()                             {}
+func (noopMetrics) MessageProcessed()                      {}
+func (noopMetrics) MessageFailed()                         {}
+func (noopMetrics) HandlerDuration(duration time.Duration) {}
+
+// MetricsHook receives client request instrumentation. Implementations must
+// be safe for concurrent use; see the promclient package for a Prometheus
+// adapter.
+type MetricsHook interface {
+	// OnRequestStart is called before each API request is sent
+	OnRequestStart(method, path string)
+	// OnRequestEnd is called once the request completes or fails
+	OnRequestEnd(metric RequestMetric)
+}
+
+// RequestMetric describes a completed API request
+type RequestMetric struct {
+	Method string
+	// Path is the endpoint's path template, e.g.
+	// "/v1/apps/{app_id}/collections/{collection_id}/items/{item_id}", so
+	// requests can be grouped per endpoint
+	Path string
+	// StatusCode is the HTTP status, or 0 if no response was received
+	StatusCode int
+	// Duration is the whole request, including QueueWait
+	Duration time.Duration
+	// QueueWait is how long the request waited for a slot under
+	// ClientConfig.MaxConcurrentRequests
+	QueueWait time.Duration
+	// Err is set if no response was received
+	Err error
+}
+
+// instrument returns a RoundTripFunc that reports requests sent with send
+// to hook
+func instrument(hook MetricsHook, send RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		path := pathTemplate(req.URL.Path)
+		hook.OnRequestStart(req.Method, path)
+		ctx, wait := withQueueWait(req.Context())
+		start := time.Now()
+		resp, err := send(req.WithContext(ctx))
+		metric := RequestMetric{
+			Method:    req.Method,
+			Path:      path,
+			Duration:  time.Since(start),
+			QueueWait: time.Duration(atomic.LoadInt64(wait)),
+			Err:       err,
+		}
+		if resp != nil {
+			metric.StatusCode = resp.StatusCode
+		}
+		hook.OnRequestEnd(metric)
+		return resp, err
+	}
+}
+
+var (
+	pathTemplatesOnce sync.Once
+	pathTemplates     [][]string
+)
+
+// pathTemplate maps a request path to the matching endpoint path from the
+// coverage manifest, keeping any base URL path prefix. Paths that match no
+// endpoint have their numeric segments replaced with {id}, keeping metric
+// label cardinality bounded.
+func pathTemplate(path string) string {
+	pathTemplatesOnce.Do(func() {
+		for _, endpoint := range Coverage().Endpoints {
+			pathTemplates = append(pathTemplates, strings.Split(strings.TrimPrefix(endpoint.Path, "/"), "/"))
+		}
+	})
+
+	segments := strings.Split(path, "/")
+	var best []string
+	bestLiterals := -1
+	for _, template := range pathTemplates {
+		offset := len(segments) - len(template)
+		if offset < 1 {
+			continue
+		}
+		literals := 0
+		matched := true
+		for i, segment := range template {
+			if strings.HasPrefix(segment, "{") {
+				continue
+			}
+			if segment != segments[offset+i] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		// Prefer literal segments, so .../items/query beats .../items/{item_id}
+		if matched && literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
+	}
+	if best != nil {
+		prefix := segments[:len(segments)-len(best)]
+		return strings.Join(append(append([]string(nil), prefix...), best...), "/")
+	}
+
+	for i, segment := range segments {
+		if segment != "" && strings.Trim(segment, "0123456789") == "" {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
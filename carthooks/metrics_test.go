@@ -0,0 +1,17 @@
+package carthooks
+
+import "testing"
+
+func TestPathTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/v1/apps/1/collections/2/items/3":     "/v1/apps/{app_id}/collections/{collection_id}/items/{item_id}",
+		"/v1/apps/1/collections/2/items/query": "/v1/apps/{app_id}/collections/{collection_id}/items/query",
+		"/api/v1/apps/7":                       "/api/v1/apps/{app_id}",
+		"/v1/unknown/42/things":                "/v1/unknown/{id}/things",
+	}
+	for path, want := range tests {
+		if got := pathTemplate(path); got != want {
+			t.Errorf("pathTemplate(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
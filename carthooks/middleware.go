@@ -0,0 +1,22 @@
+package carthooks
+
+import (
+	"context"
+)
+
+// Handler processes an event delivered to a Watcher. Returning an error
+// leaves the message unacknowledged so the source redelivers it.
+type Handler func(ctx context.Context, event *EventMessage) error
+
+// Middleware wraps a Handler to add behavior such as logging, metrics or
+// retries around it
+type Middleware func(next Handler) Handler
+
+// Chain wraps handler with middlewares. The first middleware is the
+// outermost, so it sees each event first and each result last.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,57 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, event *EventMessage) error {
+				calls = append(calls, name+">")
+				err := next(ctx, event)
+				calls = append(calls, "<"+name)
+				return err
+			}
+		}
+	}
+
+	handler := Chain(func(ctx context.Context, event *EventMessage) error {
+		calls = append(calls, "handler")
+		return nil
+	}, tag("outer"), tag("inner"))
+
+	if err := handler(context.Background(), nil); err != nil {
+		t.Fatalf("handler returned %v", err)
+	}
+	if got := fmt.Sprint(calls); got != "[outer> inner> handler <inner <outer]" {
+		t.Errorf("Unexpected call order %s", got)
+	}
+}
+
+func TestWatcher_AppliesMiddleware(t *testing.T) {
+	source := &recordingSource{}
+	seen := 0
+
+	watcher, _ := NewWatcherBuilder(nil, "test").
+		WithSource(source).
+		WithHandler(func(ctx context.Context, event *EventMessage) error {
+			return fmt.Errorf("transient")
+		}).
+		Use(func(next Handler) Handler {
+			return func(ctx context.Context, event *EventMessage) error {
+				seen++
+				return nil // swallow the handler's error
+			}
+		}).
+		Build()
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "", 1)})
+
+	if seen != 1 || len(source.acked) != 1 {
+		t.Errorf("Expected middleware to run and the message to be acked, got seen=%d acked=%v", seen, source.acked)
+	}
+}
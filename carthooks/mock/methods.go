@@ -0,0 +1,400 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// GetOAuthToken implements carthooks.ClientInterface
+func (m *MockClient) GetOAuthToken(request *carthooks.OAuthTokenRequest) *carthooks.Result {
+	return m.result("GetOAuthToken", request)
+}
+
+// RefreshOAuthToken implements carthooks.ClientInterface
+func (m *MockClient) RefreshOAuthToken(refreshToken ...string) *carthooks.Result {
+	return m.result("RefreshOAuthToken", refreshToken)
+}
+
+// InitializeOAuth implements carthooks.ClientInterface
+func (m *MockClient) InitializeOAuth(userAccessToken ...string) *carthooks.Result {
+	return m.result("InitializeOAuth", userAccessToken)
+}
+
+// ExchangeAuthorizationCode implements carthooks.ClientInterface
+func (m *MockClient) ExchangeAuthorizationCode(code, redirectURI string) *carthooks.Result {
+	return m.result("ExchangeAuthorizationCode", code, redirectURI)
+}
+
+// GetConnectionToken implements carthooks.ClientInterface
+func (m *MockClient) GetConnectionToken(connectionID uint) *carthooks.Result {
+	return m.result("GetConnectionToken", connectionID)
+}
+
+// CreateDevClient implements carthooks.ClientInterface
+func (m *MockClient) CreateDevClient(request *carthooks.CreateDevClientRequest) *carthooks.Result {
+	return m.result("CreateDevClient", request)
+}
+
+// ListDevClients implements carthooks.ClientInterface
+func (m *MockClient) ListDevClients() *carthooks.Result {
+	return m.result("ListDevClients")
+}
+
+// RotateDevClientSecret implements carthooks.ClientInterface
+func (m *MockClient) RotateDevClientSecret(devClientID uint, gracePeriod time.Duration) *carthooks.Result {
+	return m.result("RotateDevClientSecret", devClientID, gracePeriod)
+}
+
+// RevokeDevClient implements carthooks.ClientInterface
+func (m *MockClient) RevokeDevClient(devClientID uint) *carthooks.Result {
+	return m.result("RevokeDevClient", devClientID)
+}
+
+// GetOAuthAuthorizeCode implements carthooks.ClientInterface
+func (m *MockClient) GetOAuthAuthorizeCode(request *carthooks.OAuthAuthorizeCodeRequest) *carthooks.Result {
+	return m.result("GetOAuthAuthorizeCode", request)
+}
+
+// GetCurrentUser implements carthooks.ClientInterface
+func (m *MockClient) GetCurrentUser() *carthooks.Result {
+	return m.result("GetCurrentUser")
+}
+
+// GetUserTenants implements carthooks.ClientInterface
+func (m *MockClient) GetUserTenants() *carthooks.Result {
+	return m.result("GetUserTenants")
+}
+
+// GetItems implements carthooks.ClientInterface
+func (m *MockClient) GetItems(appID, collectionID uint, limit, start int, options map[string]string) *carthooks.Result {
+	return m.result("GetItems", appID, collectionID, limit, start, options)
+}
+
+// GetItemByID implements carthooks.ClientInterface
+func (m *MockClient) GetItemByID(appID, collectionID, itemID uint, fields []string) *carthooks.Result {
+	return m.result("GetItemByID", appID, collectionID, itemID, fields)
+}
+
+// GetItemByIDIfChanged implements carthooks.ClientInterface
+func (m *MockClient) GetItemByIDIfChanged(appID, collectionID, itemID uint, fields []string, etag string) *carthooks.Result {
+	return m.result("GetItemByIDIfChanged", appID, collectionID, itemID, fields, etag)
+}
+
+// QueryItems implements carthooks.ClientInterface
+func (m *MockClient) QueryItems(appID, collectionID uint, options *carthooks.QueryOptions) *carthooks.Result {
+	return m.result("QueryItems", appID, collectionID, options)
+}
+
+// CreateItem implements carthooks.ClientInterface
+func (m *MockClient) CreateItem(appID, collectionID uint, data map[string]interface{}) *carthooks.Result {
+	return m.result("CreateItem", appID, collectionID, data)
+}
+
+// UpdateItem implements carthooks.ClientInterface
+func (m *MockClient) UpdateItem(appID, collectionID, itemID uint, data map[string]interface{}) *carthooks.Result {
+	return m.result("UpdateItem", appID, collectionID, itemID, data)
+}
+
+// DeleteItem implements carthooks.ClientInterface
+func (m *MockClient) DeleteItem(appID, collectionID, itemID uint) *carthooks.Result {
+	return m.result("DeleteItem", appID, collectionID, itemID)
+}
+
+// LockItem implements carthooks.ClientInterface
+func (m *MockClient) LockItem(appID, collectionID, itemID uint, options *carthooks.LockOptions) *carthooks.Result {
+	return m.result("LockItem", appID, collectionID, itemID, options)
+}
+
+// UnlockItem implements carthooks.ClientInterface
+func (m *MockClient) UnlockItem(appID, collectionID, itemID uint, lockID string) *carthooks.Result {
+	return m.result("UnlockItem", appID, collectionID, itemID, lockID)
+}
+
+// ForceUnlockItem implements carthooks.ClientInterface
+func (m *MockClient) ForceUnlockItem(appID, collectionID, itemID uint, reason string) *carthooks.Result {
+	return m.result("ForceUnlockItem", appID, collectionID, itemID, reason)
+}
+
+// GetItemLock implements carthooks.ClientInterface
+func (m *MockClient) GetItemLock(appID, collectionID, itemID uint) *carthooks.Result {
+	return m.result("GetItemLock", appID, collectionID, itemID)
+}
+
+// CreateItemWithExternalID implements carthooks.ClientInterface
+func (m *MockClient) CreateItemWithExternalID(appID, collectionID uint, data map[string]interface{}) *carthooks.Result {
+	return m.result("CreateItemWithExternalID", appID, collectionID, data)
+}
+
+// GetItemByExternalID implements carthooks.ClientInterface
+func (m *MockClient) GetItemByExternalID(appID, collectionID uint, externalID string) *carthooks.Result {
+	return m.result("GetItemByExternalID", appID, collectionID, externalID)
+}
+
+// CreateSubItem implements carthooks.ClientInterface
+func (m *MockClient) CreateSubItem(appID, collectionID, itemID, fieldID uint, data map[string]interface{}) *carthooks.Result {
+	return m.result("CreateSubItem", appID, collectionID, itemID, fieldID, data)
+}
+
+// UpdateSubItem implements carthooks.ClientInterface
+func (m *MockClient) UpdateSubItem(appID, collectionID, itemID, fieldID, subItemID uint, data map[string]interface{}) *carthooks.Result {
+	return m.result("UpdateSubItem", appID, collectionID, itemID, fieldID, subItemID, data)
+}
+
+// DeleteSubItem implements carthooks.ClientInterface
+func (m *MockClient) DeleteSubItem(appID, collectionID, itemID, fieldID, subItemID uint) *carthooks.Result {
+	return m.result("DeleteSubItem", appID, collectionID, itemID, fieldID, subItemID)
+}
+
+// CreateConnection implements carthooks.ClientInterface
+func (m *MockClient) CreateConnection(appID uint, request *carthooks.CreateConnectionRequest) *carthooks.Result {
+	return m.result("CreateConnection", appID, request)
+}
+
+// UpdateConnection implements carthooks.ClientInterface
+func (m *MockClient) UpdateConnection(appID, connectionID uint, request *carthooks.UpdateConnectionRequest) *carthooks.Result {
+	return m.result("UpdateConnection", appID, connectionID, request)
+}
+
+// GetConnection implements carthooks.ClientInterface
+func (m *MockClient) GetConnection(appID, connectionID uint) *carthooks.Result {
+	return m.result("GetConnection", appID, connectionID)
+}
+
+// ListConnections implements carthooks.ClientInterface
+func (m *MockClient) ListConnections(appID uint, filters map[string]string, pagination *carthooks.PaginationOptions) *carthooks.Result {
+	return m.result("ListConnections", appID, filters, pagination)
+}
+
+// SetConnectionStatus implements carthooks.ClientInterface
+func (m *MockClient) SetConnectionStatus(appID, connectionID uint, status carthooks.ConnectionStatus) *carthooks.Result {
+	return m.result("SetConnectionStatus", appID, connectionID, status)
+}
+
+// DeleteConnection implements carthooks.ClientInterface
+func (m *MockClient) DeleteConnection(appID, connectionID uint) *carthooks.Result {
+	return m.result("DeleteConnection", appID, connectionID)
+}
+
+// CreateConnectionLog implements carthooks.ClientInterface
+func (m *MockClient) CreateConnectionLog(appID, connectionID uint, request *carthooks.CreateConnectionLogRequest) *carthooks.Result {
+	return m.result("CreateConnectionLog", appID, connectionID, request)
+}
+
+// GetConnectionLogs implements carthooks.ClientInterface
+func (m *MockClient) GetConnectionLogs(appID, connectionID uint, status carthooks.ConnectionLogStatus, pagination *carthooks.PaginationOptions) *carthooks.Result {
+	return m.result("GetConnectionLogs", appID, connectionID, status, pagination)
+}
+
+// CreateConnectionUsage implements carthooks.ClientInterface
+func (m *MockClient) CreateConnectionUsage(appID, connectionID uint, request *carthooks.CreateConnectionUsageRequest) *carthooks.Result {
+	return m.result("CreateConnectionUsage", appID, connectionID, request)
+}
+
+// GetConnectionUsage implements carthooks.ClientInterface
+func (m *MockClient) GetConnectionUsage(appID, connectionID uint, from, to time.Time, granularity carthooks.UsageGranularity) *carthooks.Result {
+	return m.result("GetConnectionUsage", appID, connectionID, from, to, granularity)
+}
+
+// RegisterConnectionWebhook implements carthooks.ClientInterface
+func (m *MockClient) RegisterConnectionWebhook(appID, connectionID uint, request *carthooks.RegisterConnectionWebhookRequest) *carthooks.Result {
+	return m.result("RegisterConnectionWebhook", appID, connectionID, request)
+}
+
+// ListConnectionWebhooks implements carthooks.ClientInterface
+func (m *MockClient) ListConnectionWebhooks(appID, connectionID uint) *carthooks.Result {
+	return m.result("ListConnectionWebhooks", appID, connectionID)
+}
+
+// UnregisterConnectionWebhook implements carthooks.ClientInterface
+func (m *MockClient) UnregisterConnectionWebhook(appID, connectionID uint, webhookID string) *carthooks.Result {
+	return m.result("UnregisterConnectionWebhook", appID, connectionID, webhookID)
+}
+
+// CreateVendorTask implements carthooks.ClientInterface
+func (m *MockClient) CreateVendorTask(request *carthooks.CreateVendorTaskRequest) *carthooks.Result {
+	return m.result("CreateVendorTask", request)
+}
+
+// GetVendorTask implements carthooks.ClientInterface
+func (m *MockClient) GetVendorTask(taskID string) *carthooks.Result {
+	return m.result("GetVendorTask", taskID)
+}
+
+// CompleteVendorTask implements carthooks.ClientInterface
+func (m *MockClient) CompleteVendorTask(taskID string, request *carthooks.CompleteVendorTaskRequest) *carthooks.Result {
+	return m.result("CompleteVendorTask", taskID, request)
+}
+
+// GetSubmissionToken implements carthooks.ClientInterface
+func (m *MockClient) GetSubmissionToken(appID, collectionID uint, options *carthooks.SubmissionTokenOptions) *carthooks.Result {
+	return m.result("GetSubmissionToken", appID, collectionID, options)
+}
+
+// UpdateSubmissionToken implements carthooks.ClientInterface
+func (m *MockClient) UpdateSubmissionToken(appID, collectionID, itemID uint, options *carthooks.UpdateTokenOptions) *carthooks.Result {
+	return m.result("UpdateSubmissionToken", appID, collectionID, itemID, options)
+}
+
+// GetUploadToken implements carthooks.ClientInterface
+func (m *MockClient) GetUploadToken() *carthooks.Result {
+	return m.result("GetUploadToken")
+}
+
+// GetUser implements carthooks.ClientInterface
+func (m *MockClient) GetUser(userID uint) *carthooks.Result {
+	return m.result("GetUser", userID)
+}
+
+// GetUserByToken implements carthooks.ClientInterface
+func (m *MockClient) GetUserByToken(token string) *carthooks.Result {
+	return m.result("GetUserByToken", token)
+}
+
+// ListTenantUsers implements carthooks.ClientInterface
+func (m *MockClient) ListTenantUsers(pagination *carthooks.PaginationOptions) *carthooks.Result {
+	return m.result("ListTenantUsers", pagination)
+}
+
+// InviteUser implements carthooks.ClientInterface
+func (m *MockClient) InviteUser(request *carthooks.InviteUserRequest) *carthooks.Result {
+	return m.result("InviteUser", request)
+}
+
+// RemoveUser implements carthooks.ClientInterface
+func (m *MockClient) RemoveUser(userID uint) *carthooks.Result {
+	return m.result("RemoveUser", userID)
+}
+
+// UpdateUserRole implements carthooks.ClientInterface
+func (m *MockClient) UpdateUserRole(userID uint, role carthooks.TenantRole) *carthooks.Result {
+	return m.result("UpdateUserRole", userID, role)
+}
+
+// GetAuditLogs implements carthooks.ClientInterface
+func (m *MockClient) GetAuditLogs(filter *carthooks.AuditLogFilter, pagination *carthooks.PaginationOptions) *carthooks.Result {
+	return m.result("GetAuditLogs", filter, pagination)
+}
+
+// GetTenantUsage implements carthooks.ClientInterface
+func (m *MockClient) GetTenantUsage(from, to time.Time) *carthooks.Result {
+	return m.result("GetTenantUsage", from, to)
+}
+
+// GetQuotaStatus implements carthooks.ClientInterface
+func (m *MockClient) GetQuotaStatus() *carthooks.Result {
+	return m.result("GetQuotaStatus")
+}
+
+// StartWatchData implements carthooks.ClientInterface
+func (m *MockClient) StartWatchData(options *carthooks.WatchDataOptions) *carthooks.Result {
+	return m.result("StartWatchData", options)
+}
+
+// StopWatchData implements carthooks.ClientInterface
+func (m *MockClient) StopWatchData(options *carthooks.WatchDataOptions) *carthooks.Result {
+	return m.result("StopWatchData", options)
+}
+
+// ListWatches implements carthooks.ClientInterface
+func (m *MockClient) ListWatches(appID, collectionID uint) *carthooks.Result {
+	return m.result("ListWatches", appID, collectionID)
+}
+
+// GetWatch implements carthooks.ClientInterface
+func (m *MockClient) GetWatch(watchID string) *carthooks.Result {
+	return m.result("GetWatch", watchID)
+}
+
+// UpdateWatchData implements carthooks.ClientInterface
+func (m *MockClient) UpdateWatchData(watchID string, options *carthooks.WatchDataOptions) *carthooks.Result {
+	return m.result("UpdateWatchData", watchID, options)
+}
+
+// PauseWatch implements carthooks.ClientInterface
+func (m *MockClient) PauseWatch(watchID string) *carthooks.Result {
+	return m.result("PauseWatch", watchID)
+}
+
+// ResumeWatch implements carthooks.ClientInterface
+func (m *MockClient) ResumeWatch(watchID string) *carthooks.Result {
+	return m.result("ResumeWatch", watchID)
+}
+
+// DeleteWatch implements carthooks.ClientInterface
+func (m *MockClient) DeleteWatch(watchID string) *carthooks.Result {
+	return m.result("DeleteWatch", watchID)
+}
+
+// GetCollections implements carthooks.ClientInterface
+func (m *MockClient) GetCollections(appID uint) *carthooks.Result {
+	return m.result("GetCollections", appID)
+}
+
+// GetCollection implements carthooks.ClientInterface
+func (m *MockClient) GetCollection(appID, collectionID uint) *carthooks.Result {
+	return m.result("GetCollection", appID, collectionID)
+}
+
+// GetCollectionIfChanged implements carthooks.ClientInterface
+func (m *MockClient) GetCollectionIfChanged(appID, collectionID uint, etag string) *carthooks.Result {
+	return m.result("GetCollectionIfChanged", appID, collectionID, etag)
+}
+
+// GetApps implements carthooks.ClientInterface
+func (m *MockClient) GetApps() *carthooks.Result {
+	return m.result("GetApps")
+}
+
+// GetApp implements carthooks.ClientInterface
+func (m *MockClient) GetApp(appID uint) *carthooks.Result {
+	return m.result("GetApp", appID)
+}
+
+// BulkCreateItems implements carthooks.ClientInterface by calling
+// CreateItem for each item, so per-item responses can be queued with Once
+func (m *MockClient) BulkCreateItems(appID, collectionID uint, items []map[string]interface{}) *carthooks.BulkResult {
+	m.record("BulkCreateItems", appID, collectionID, items)
+	result := &carthooks.BulkResult{}
+	for i, data := range items {
+		result.Items = append(result.Items, bulkItem(i, 0, m.CreateItem(appID, collectionID, data)))
+	}
+	return result
+}
+
+// BulkUpdateItems implements carthooks.ClientInterface by calling
+// UpdateItem for each update
+func (m *MockClient) BulkUpdateItems(appID, collectionID uint, updates []carthooks.ItemUpdate) *carthooks.BulkResult {
+	m.record("BulkUpdateItems", appID, collectionID, updates)
+	result := &carthooks.BulkResult{}
+	for i, update := range updates {
+		result.Items = append(result.Items, bulkItem(i, update.ItemID, m.UpdateItem(appID, collectionID, update.ItemID, update.Data)))
+	}
+	return result
+}
+
+// BulkDeleteItems implements carthooks.ClientInterface by calling
+// DeleteItem for each item
+func (m *MockClient) BulkDeleteItems(appID, collectionID uint, itemIDs []uint) *carthooks.BulkResult {
+	m.record("BulkDeleteItems", appID, collectionID, itemIDs)
+	result := &carthooks.BulkResult{}
+	for i, itemID := range itemIDs {
+		result.Items = append(result.Items, bulkItem(i, itemID, m.DeleteItem(appID, collectionID, itemID)))
+	}
+	return result
+}
+
+// SubmitExportJob implements carthooks.ClientInterface
+func (m *MockClient) SubmitExportJob(appID, collectionID uint, options *carthooks.ExportJobOptions) *carthooks.Result {
+	return m.result("SubmitExportJob", appID, collectionID, options)
+}
+
+// GetExportJobStatus implements carthooks.ClientInterface
+func (m *MockClient) GetExportJobStatus(jobID string) *carthooks.Result {
+	return m.result("GetExportJobStatus", jobID)
+}
+
+// bulkItem returns the outcome of one item of a bulk operation
+func bulkItem(index int, itemID uint, result *carthooks.Result) carthooks.BulkItemResult {
+	return carthooks.BulkItemResult{Index: index, ItemID: itemID, Result: result, Err: result.Err()}
+}
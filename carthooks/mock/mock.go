@@ -0,0 +1,295 @@
+// Package mock provides MockClient, a carthooks.ClientInterface for unit
+// tests that records calls and returns configured responses, so code using
+// the SDK can be tested without an HTTP server.
+//
+//	client := mock.New().
+//		On("GetItemByID", &carthooks.Result{Success: true, Data: map[string]interface{}{"id": 1}}).
+//		OnError("EnsureValidToken", nil)
+//
+//	svc := NewService(client)
+//	svc.Sync(ctx)
+//
+//	client.AssertCalled(t, "GetItemByID", uint(1), uint(2), uint(3), []string(nil))
+package mock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// Call records one method call on a MockClient
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// ResponseFunc computes the response to a call from its arguments
+type ResponseFunc func(args ...interface{}) *carthooks.Result
+
+// MockClient implements carthooks.ClientInterface. Methods returning a
+// *carthooks.Result answer with, in order of precedence, the next response
+// queued with Once, the function set with OnFunc, or the response set with
+// On. Unconfigured methods return an unsuccessful Result. It is safe for
+// concurrent use.
+type MockClient struct {
+	// BaseURL is returned by GetBaseURL
+	BaseURL string
+	// Events are delivered to SubscribeSSE handlers
+	Events []*carthooks.EventMessage
+
+	mu          sync.Mutex
+	calls       []Call
+	responses   map[string]*carthooks.Result
+	queued      map[string][]*carthooks.Result
+	funcs       map[string]ResponseFunc
+	errs        map[string]error
+	accessToken string
+	oauthConfig *carthooks.OAuthConfig
+	tokens      *carthooks.OAuthTokens
+	externalIDs int
+}
+
+// Ensure MockClient implements ClientInterface
+var _ carthooks.ClientInterface = (*MockClient)(nil)
+
+// New creates a MockClient with no responses configured
+func New() *MockClient {
+	return &MockClient{
+		BaseURL:   "https://mock.carthooks.test",
+		responses: map[string]*carthooks.Result{},
+		queued:    map[string][]*carthooks.Result{},
+		funcs:     map[string]ResponseFunc{},
+		errs:      map[string]error{},
+	}
+}
+
+// On sets the response returned by every call to method
+func (m *MockClient) On(method string, result *carthooks.Result) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[method] = result
+	return m
+}
+
+// Once queues a response for the next call to method; queued responses are
+// used in order before falling back to OnFunc or On
+func (m *MockClient) Once(method string, result *carthooks.Result) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued[method] = append(m.queued[method], result)
+	return m
+}
+
+// OnFunc computes the response to each call to method from its arguments
+func (m *MockClient) OnFunc(method string, fn ResponseFunc) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.funcs[method] = fn
+	return m
+}
+
+// OnError sets the error returned by EnsureValidToken, TryLockItem,
+// NewExternalID or SubscribeSSE
+func (m *MockClient) OnError(method string, err error) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errs[method] = err
+	return m
+}
+
+// Calls returns every recorded call, in order
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls to method, in order
+func (m *MockClient) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets recorded calls, keeping configured responses
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+// AssertCalled fails the test unless method was called with args at least
+// once. Without args any call matches.
+func (m *MockClient) AssertCalled(t testing.TB, method string, args ...interface{}) {
+	t.Helper()
+	calls := m.CallsTo(method)
+	if len(calls) == 0 {
+		t.Errorf("Expected %s to be called", method)
+		return
+	}
+	if len(args) == 0 {
+		return
+	}
+	for _, call := range calls {
+		if reflect.DeepEqual(call.Args, args) {
+			return
+		}
+	}
+	t.Errorf("Expected %s to be called with %v, got calls %v", method, args, calls)
+}
+
+// AssertNotCalled fails the test if method was called
+func (m *MockClient) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if calls := m.CallsTo(method); len(calls) > 0 {
+		t.Errorf("Expected %s not to be called, got %v", method, calls)
+	}
+}
+
+// AssertCallCount fails the test unless method was called n times
+func (m *MockClient) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+	if calls := m.CallsTo(method); len(calls) != n {
+		t.Errorf("Expected %s to be called %d times, got %d", method, n, len(calls))
+	}
+}
+
+// record records a call and returns the error configured for method
+func (m *MockClient) record(method string, args ...interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	return m.errs[method]
+}
+
+// result records a call and returns the response configured for method
+func (m *MockClient) result(method string, args ...interface{}) *carthooks.Result {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	var result *carthooks.Result
+	if queued := m.queued[method]; len(queued) > 0 {
+		result = queued[0]
+		m.queued[method] = queued[1:]
+	}
+	fn := m.funcs[method]
+	if result == nil && fn == nil {
+		result = m.responses[method]
+	}
+	m.mu.Unlock()
+
+	if result == nil && fn != nil {
+		result = fn(args...)
+	}
+	if result == nil {
+		return &carthooks.Result{
+			Success: false,
+			Error:   fmt.Sprintf("mock: no response configured for %s", method),
+		}
+	}
+	return result
+}
+
+// SetAccessToken implements carthooks.ClientInterface
+func (m *MockClient) SetAccessToken(token string) {
+	m.record("SetAccessToken", token)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accessToken = token
+}
+
+// AccessToken returns the token last passed to SetAccessToken
+func (m *MockClient) AccessToken() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.accessToken
+}
+
+// GetBaseURL implements carthooks.ClientInterface
+func (m *MockClient) GetBaseURL() string {
+	m.record("GetBaseURL")
+	return m.BaseURL
+}
+
+// EnsureValidToken implements carthooks.ClientInterface
+func (m *MockClient) EnsureValidToken() error {
+	return m.record("EnsureValidToken")
+}
+
+// GetCurrentTokens implements carthooks.ClientInterface
+func (m *MockClient) GetCurrentTokens() *carthooks.OAuthTokens {
+	m.record("GetCurrentTokens")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tokens
+}
+
+// SetCurrentTokens sets the tokens returned by GetCurrentTokens
+func (m *MockClient) SetCurrentTokens(tokens *carthooks.OAuthTokens) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens = tokens
+}
+
+// SetOAuthConfig implements carthooks.ClientInterface
+func (m *MockClient) SetOAuthConfig(config *carthooks.OAuthConfig) {
+	m.record("SetOAuthConfig", config)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.oauthConfig = config
+}
+
+// GetOAuthConfig implements carthooks.ClientInterface
+func (m *MockClient) GetOAuthConfig() *carthooks.OAuthConfig {
+	m.record("GetOAuthConfig")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.oauthConfig
+}
+
+// TryLockItem implements carthooks.ClientInterface
+func (m *MockClient) TryLockItem(appID, collectionID, itemID uint, options *carthooks.LockOptions, retry *carthooks.LockRetryOptions) (*carthooks.Result, error) {
+	result := m.result("TryLockItem", appID, collectionID, itemID, options, retry)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return result, m.errs["TryLockItem"]
+}
+
+// NewExternalID implements carthooks.ClientInterface, returning sequential
+// IDs "mock-external-id-1", "mock-external-id-2", ...
+func (m *MockClient) NewExternalID() (string, error) {
+	if err := m.record("NewExternalID"); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.externalIDs++
+	return fmt.Sprintf("mock-external-id-%d", m.externalIDs), nil
+}
+
+// SubscribeSSE implements carthooks.ClientInterface. It delivers Events to
+// handler, then returns the error set with OnError, or blocks until ctx is
+// cancelled if none is set.
+func (m *MockClient) SubscribeSSE(ctx context.Context, appID, collectionID uint, filters map[string]interface{}, handler func(event *carthooks.EventMessage) error) error {
+	err := m.record("SubscribeSSE", appID, collectionID, filters)
+	for _, event := range m.Events {
+		if handlerErr := handler(event); handlerErr != nil {
+			return handlerErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
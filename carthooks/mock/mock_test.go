@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+func TestMockClient_Responses(t *testing.T) {
+	client := New().
+		On("GetItemByID", &carthooks.Result{Success: true, Data: "default"}).
+		Once("GetItemByID", &carthooks.Result{Success: true, Data: "first"})
+
+	var api carthooks.ClientInterface = client
+	if got := api.GetItemByID(1, 2, 3, nil).Data; got != "first" {
+		t.Errorf("Expected queued response first, got %v", got)
+	}
+	if got := api.GetItemByID(1, 2, 4, nil).Data; got != "default" {
+		t.Errorf("Expected default response, got %v", got)
+	}
+	if result := api.DeleteItem(1, 2, 3); result.Success || result.Error == "" {
+		t.Errorf("Expected unconfigured method to fail, got %+v", result)
+	}
+
+	client.OnFunc("CreateItem", func(args ...interface{}) *carthooks.Result {
+		data := args[2].(map[string]interface{})
+		return &carthooks.Result{Success: true, Data: data["title"]}
+	})
+	if got := api.CreateItem(1, 2, map[string]interface{}{"title": "Widget"}).Data; got != "Widget" {
+		t.Errorf("Expected computed response, got %v", got)
+	}
+}
+
+func TestMockClient_Assertions(t *testing.T) {
+	client := New()
+	client.GetItemByID(1, 2, 3, []string{"title"})
+	client.GetItemByID(1, 2, 4, nil)
+	client.SetAccessToken("token")
+
+	client.AssertCalled(t, "GetItemByID", uint(1), uint(2), uint(4), []string(nil))
+	client.AssertCallCount(t, "GetItemByID", 2)
+	client.AssertNotCalled(t, "DeleteItem")
+	if client.AccessToken() != "token" {
+		t.Errorf("Expected token to be stored, got %q", client.AccessToken())
+	}
+
+	recorder := &testing.T{}
+	client.AssertCalled(recorder, "GetItemByID", uint(9))
+	if !recorder.Failed() {
+		t.Error("Expected AssertCalled to fail for unmatched arguments")
+	}
+
+	client.Reset()
+	client.AssertCallCount(t, "GetItemByID", 0)
+}
+
+func TestMockClient_Errors(t *testing.T) {
+	expired := errors.New("token expired")
+	client := New().OnError("EnsureValidToken", expired)
+	if err := client.EnsureValidToken(); err != expired {
+		t.Errorf("Expected configured error, got %v", err)
+	}
+
+	id1, _ := client.NewExternalID()
+	id2, _ := client.NewExternalID()
+	if id1 == id2 {
+		t.Errorf("Expected distinct external IDs, got %s twice", id1)
+	}
+
+	client.Events = []*carthooks.EventMessage{{Meta: carthooks.EventMessageMeta{Event: carthooks.EventCodeRecordCreated}}}
+	client.OnError("SubscribeSSE", context.Canceled)
+	received := 0
+	err := client.SubscribeSSE(context.Background(), 1, 2, nil, func(event *carthooks.EventMessage) error {
+		received++
+		return nil
+	})
+	if err != context.Canceled || received != 1 {
+		t.Errorf("Expected 1 event then the configured error, got %d, %v", received, err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package natssource provides a NATS JetStream carthooks.MessageSource using a
+// durable pull consumer with explicit acknowledgement.
+package natssource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Config holds configuration for a JetStream source
+type Config struct {
+	// URL is the NATS server URL, e.g. nats://localhost:4222
+	URL    string
+	Stream string
+	// Durable is the durable consumer name; watchers sharing it split
+	// the stream's messages between them
+	Durable string
+	// FilterSubject optionally restricts the consumer to a subject
+	FilterSubject string
+	// BatchSize is the maximum number of messages per fetch (default 10)
+	BatchSize int
+	// MaxWait is how long a fetch waits for messages (default 20s)
+	MaxWait time.Duration
+	// AckWait is how long JetStream waits for an ack before redelivering
+	// (default 5 minutes)
+	AckWait time.Duration
+	// Options are passed to nats.Connect, e.g. credentials
+	Options []nats.Option
+}
+
+// Source is a carthooks.MessageSource backed by a JetStream durable consumer
+type Source struct {
+	config   Config
+	conn     *nats.Conn
+	consumer jetstream.Consumer
+}
+
+// New connects to NATS and creates (or updates) the durable consumer
+func New(ctx context.Context, config Config) (*Source, error) {
+	if config.URL == "" || config.Stream == "" || config.Durable == "" {
+		return nil, fmt.Errorf("nats source requires a URL, stream and durable name")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	if config.MaxWait <= 0 {
+		config.MaxWait = 20 * time.Second
+	}
+	if config.AckWait <= 0 {
+		config.AckWait = 5 * time.Minute
+	}
+
+	conn, err := nats.Connect(config.URL, config.Options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(ctx, config.Stream, jetstream.ConsumerConfig{
+		Durable:       config.Durable,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       config.AckWait,
+		FilterSubject: config.FilterSubject,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create consumer %s: %w", config.Durable, err)
+	}
+
+	return &Source{
+		config:   config,
+		conn:     conn,
+		consumer: consumer,
+	}, nil
+}
+
+// Receive fetches up to BatchSize messages, waiting at most MaxWait
+func (s *Source) Receive(ctx context.Context) ([]*carthooks.Message, error) {
+	maxWait := s.config.MaxWait
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < maxWait {
+		maxWait = time.Until(deadline)
+	}
+
+	batch, err := s.consumer.Fetch(s.config.BatchSize, jetstream.FetchMaxWait(maxWait))
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*carthooks.Message
+	for msg := range batch.Messages() {
+		messages = append(messages, natsMessage(msg))
+	}
+	if err := batch.Error(); err != nil && !errors.Is(err, nats.ErrTimeout) {
+		return messages, err
+	}
+	return messages, nil
+}
+
+// Ack acknowledges the message
+func (s *Source) Ack(ctx context.Context, message *carthooks.Message) error {
+	msg, ok := message.Raw.(jetstream.Msg)
+	if !ok {
+		return fmt.Errorf("not a JetStream message")
+	}
+	return msg.Ack()
+}
+
+// Nack asks JetStream to redeliver the message
+func (s *Source) Nack(ctx context.Context, message *carthooks.Message) error {
+	msg, ok := message.Raw.(jetstream.Msg)
+	if !ok {
+		return fmt.Errorf("not a JetStream message")
+	}
+	return msg.Nak()
+}
+
+// Close drains and closes the NATS connection
+func (s *Source) Close() error {
+	return s.conn.Drain()
+}
+
+// natsMessage converts a JetStream message into a Message
+func natsMessage(msg jetstream.Msg) *carthooks.Message {
+	attributes := make(map[string]string, len(msg.Headers())+2)
+	for k := range msg.Headers() {
+		attributes[k] = msg.Headers().Get(k)
+	}
+	attributes["subject"] = msg.Subject()
+
+	id := ""
+	if metadata, err := msg.Metadata(); err == nil {
+		id = strconv.FormatUint(metadata.Sequence.Stream, 10)
+		attributes["numDelivered"] = strconv.FormatUint(metadata.NumDelivered, 10)
+	}
+
+	return &carthooks.Message{
+		ID:         id,
+		Body:       msg.Data(),
+		Attributes: attributes,
+		Raw:        msg,
+	}
+}
@@ -0,0 +1,166 @@
+package natssource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeConsumer serves queued messages from Fetch. Acked messages are
+// removed; nacked ones are queued again with their delivery count raised,
+// as JetStream redelivers them.
+type fakeConsumer struct {
+	jetstream.Consumer
+
+	mu       sync.Mutex
+	queue    []*fakeMsg
+	acked    []uint64
+	fetchErr error
+}
+
+func (c *fakeConsumer) publish(seq uint64, data string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue = append(c.queue, &fakeMsg{consumer: c, seq: seq, delivered: 1, data: []byte(data)})
+}
+
+func (c *fakeConsumer) Fetch(batch int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &fakeBatch{msgs: make(chan jetstream.Msg, batch), err: c.fetchErr}
+	for len(c.queue) > 0 && len(result.msgs) < batch {
+		result.msgs <- c.queue[0]
+		c.queue = c.queue[1:]
+	}
+	if len(result.msgs) == 0 && result.err == nil {
+		result.err = nats.ErrTimeout
+	}
+	close(result.msgs)
+	return result, nil
+}
+
+type fakeBatch struct {
+	msgs chan jetstream.Msg
+	err  error
+}
+
+func (b *fakeBatch) Messages() <-chan jetstream.Msg { return b.msgs }
+func (b *fakeBatch) Error() error                   { return b.err }
+
+type fakeMsg struct {
+	jetstream.Msg
+
+	consumer  *fakeConsumer
+	seq       uint64
+	delivered uint64
+	data      []byte
+}
+
+func (m *fakeMsg) Data() []byte         { return m.data }
+func (m *fakeMsg) Subject() string      { return "carthooks.events" }
+func (m *fakeMsg) Headers() nats.Header { return nats.Header{"Tenant": []string{"3"}} }
+
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{
+		Sequence:     jetstream.SequencePair{Stream: m.seq},
+		NumDelivered: m.delivered,
+	}, nil
+}
+
+func (m *fakeMsg) Ack() error {
+	m.consumer.mu.Lock()
+	defer m.consumer.mu.Unlock()
+	m.consumer.acked = append(m.consumer.acked, m.seq)
+	return nil
+}
+
+func (m *fakeMsg) Nak() error {
+	m.consumer.mu.Lock()
+	defer m.consumer.mu.Unlock()
+	m.consumer.queue = append(m.consumer.queue, &fakeMsg{consumer: m.consumer, seq: m.seq, delivered: m.delivered + 1, data: m.data})
+	return nil
+}
+
+func newTestSource(consumer *fakeConsumer) *Source {
+	return &Source{
+		config:   Config{BatchSize: 10, MaxWait: time.Second},
+		consumer: consumer,
+	}
+}
+
+func TestSource_Receive(t *testing.T) {
+	consumer := &fakeConsumer{}
+	consumer.publish(41, `{"version":"1"}`)
+	consumer.publish(42, `{"version":"1"}`)
+	source := newTestSource(consumer)
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != "41" || string(messages[0].Body) != `{"version":"1"}` {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+	attributes := messages[0].Attributes
+	if attributes["Tenant"] != "3" || attributes["subject"] != "carthooks.events" || attributes["numDelivered"] != "1" {
+		t.Errorf("Unexpected attributes %v", attributes)
+	}
+
+	// An empty fetch times out without an error
+	messages, err = source.Receive(context.Background())
+	if err != nil || len(messages) != 0 {
+		t.Errorf("Expected no messages, got %d, %v", len(messages), err)
+	}
+}
+
+func TestSource_AckAndNackRedelivers(t *testing.T) {
+	consumer := &fakeConsumer{}
+	consumer.publish(1, `{}`)
+	consumer.publish(2, `{}`)
+	source := newTestSource(consumer)
+	ctx := context.Background()
+
+	messages, _ := source.Receive(ctx)
+	if err := source.Ack(ctx, messages[0]); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if err := source.Nack(ctx, messages[1]); err != nil {
+		t.Fatalf("Nack() failed: %v", err)
+	}
+	if len(consumer.acked) != 1 || consumer.acked[0] != 1 {
+		t.Errorf("Unexpected acks %v", consumer.acked)
+	}
+
+	messages, err := source.Receive(ctx)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("Receive() = %d messages, %v", len(messages), err)
+	}
+	if messages[0].ID != "2" || messages[0].Attributes["numDelivered"] != "2" {
+		t.Errorf("Expected the nacked message redelivered, got %+v", messages[0])
+	}
+}
+
+func TestSource_ReceiveError(t *testing.T) {
+	consumer := &fakeConsumer{fetchErr: errors.New("consumer deleted")}
+	if _, err := newTestSource(consumer).Receive(context.Background()); err == nil {
+		t.Error("Expected the batch error to be returned")
+	}
+}
+
+func TestSource_RejectsForeignMessages(t *testing.T) {
+	source := newTestSource(&fakeConsumer{})
+	message := &carthooks.Message{ID: "x", Raw: "not a JetStream message"}
+	if err := source.Ack(context.Background(), message); err == nil {
+		t.Error("Expected Ack to reject a non-JetStream message")
+	}
+	if err := source.Nack(context.Background(), message); err == nil {
+		t.Error("Expected Nack to reject a non-JetStream message")
+	}
+}
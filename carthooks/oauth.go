@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -28,20 +29,21 @@ func (c *Client) GetOAuthToken(request *OAuthTokenRequest) *Result {
 	if request.RefreshToken != "" {
 		formData.Set("refresh_token", request.RefreshToken)
 	}
+	if request.ConnectionID != 0 {
+		formData.Set("connection_id", strconv.FormatUint(uint64(request.ConnectionID), 10))
+	}
 
 	// Create a custom request for form data
 	resp, err := c.makeFormRequest("POST", "/oauth/token", formData)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	result := c.parseResponse(resp)
 
-	// Store tokens if this is our client and request was successful
-	if result.Success && c.oauthConfig != nil && request.ClientID == c.oauthConfig.ClientID {
+	// Store tokens if this is our client and request was successful.
+	// Connection-scoped tokens are not the client's own.
+	if result.Success && c.oauthConfig != nil && request.ClientID == c.oauthConfig.ClientID && request.ConnectionID == 0 {
 		if tokenData, ok := result.Data.(map[string]interface{}); ok {
 			tokens := &OAuthTokens{}
 			if accessToken, ok := tokenData["access_token"].(string); ok {
@@ -156,10 +158,7 @@ func (c *Client) ExchangeAuthorizationCode(code, redirectURI string) *Result {
 func (c *Client) GetOAuthAuthorizeCode(request *OAuthAuthorizeCodeRequest) *Result {
 	resp, err := c.makeRequest("POST", "/oauth/get-authorize-code", request, nil)
 	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
+		return requestFailed(err)
 	}
 
 	return c.parseResponse(resp)
@@ -167,28 +166,12 @@ func (c *Client) GetOAuthAuthorizeCode(request *OAuthAuthorizeCodeRequest) *Resu
 
 // GetCurrentUser gets current user information (requires OAuth token)
 func (c *Client) GetCurrentUser() *Result {
-	resp, err := c.makeRequest("GET", "/v1/me", nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.readRequest("GET", "/v1/me", nil, nil)
 }
 
 // GetUserTenants gets user's tenants (requires OAuth token)
 func (c *Client) GetUserTenants() *Result {
-	resp, err := c.makeRequest("GET", "/v1/tenants", nil, nil)
-	if err != nil {
-		return &Result{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	return c.parseResponse(resp)
+	return c.readRequest("GET", "/v1/tenants", nil, nil)
 }
 
 // EnsureValidToken checks if token needs refresh and refreshes if necessary
@@ -238,7 +221,7 @@ func (c *Client) makeFormRequest(method, path string, formData url.Values) (*htt
 	fullURL := c.baseURL + path
 
 	// Create request with form data
-	req, err := http.NewRequest(method, fullURL, strings.NewReader(formData.Encode()))
+	req, err := http.NewRequestWithContext(c.context(), method, fullURL, strings.NewReader(formData.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -253,23 +236,21 @@ func (c *Client) makeFormRequest(method, path string, formData url.Values) (*htt
 			req.Header.Set(k, v)
 		}
 	}
+	requestID := setRequestID(req)
+	c.propagateTrace(req)
 
 	// Debug logging
-	if c.debug {
-		fmt.Printf("[DEBUG] %s %s\n", method, fullURL)
-		fmt.Printf("[DEBUG] Form data: %s\n", formData.Encode())
+	if c.debugEnabled() {
+		c.logger.Debug("sending request", "method", method, "url", fullURL, "request_id", requestID, "form", c.logBody(c.redact.values(formData)))
 	}
 
 	// Make request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	// Debug response
-	if c.debug {
-		fmt.Printf("[DEBUG] Response status: %s\n", resp.Status)
-	}
+	c.logger.Debug("received response", "method", method, "url", fullURL, "request_id", requestID, "status", resp.StatusCode)
 
 	return resp, nil
 }
@@ -0,0 +1,397 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader carries the key that makes the API apply a write
+// once, however often it is sent
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// OutboxEntry is a write queued while the API was unreachable
+type OutboxEntry struct {
+	// Key is the idempotency key sent with every attempt of the write
+	Key       string                 `json:"key"`
+	Method    string                 `json:"method"`
+	Path      string                 `json:"path"`
+	Body      map[string]interface{} `json:"body"`
+	QueuedAt  time.Time              `json:"queued_at"`
+	Attempts  int                    `json:"attempts"`
+	LastError string                 `json:"last_error,omitempty"`
+}
+
+// OutboxStore persists queued writes in order. Implementations must be safe
+// for concurrent use.
+type OutboxStore interface {
+	// Append adds an entry to the end of the queue
+	Append(ctx context.Context, entry *OutboxEntry) error
+	// List returns the queued entries, oldest first
+	List(ctx context.Context) ([]*OutboxEntry, error)
+	// Update replaces the entry with the same key
+	Update(ctx context.Context, entry *OutboxEntry) error
+	// Remove deletes the entry with the given key
+	Remove(ctx context.Context, key string) error
+}
+
+// MemoryOutboxStore is an in-process OutboxStore. Queued writes are lost
+// when the process exits; use FileOutboxStore to keep them.
+type MemoryOutboxStore struct {
+	mu      sync.Mutex
+	entries []*OutboxEntry
+}
+
+// NewMemoryOutboxStore creates an empty in-memory outbox
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{}
+}
+
+// Append implements OutboxStore
+func (s *MemoryOutboxStore) Append(ctx context.Context, entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *entry
+	s.entries = append(s.entries, &cp)
+	return nil
+}
+
+// List implements OutboxStore
+func (s *MemoryOutboxStore) List(ctx context.Context) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]*OutboxEntry, len(s.entries))
+	for i, entry := range s.entries {
+		cp := *entry
+		entries[i] = &cp
+	}
+	return entries, nil
+}
+
+// Update implements OutboxStore
+func (s *MemoryOutboxStore) Update(ctx context.Context, entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.entries {
+		if existing.Key == entry.Key {
+			cp := *entry
+			s.entries[i] = &cp
+		}
+	}
+	return nil
+}
+
+// Remove implements OutboxStore
+func (s *MemoryOutboxStore) Remove(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, entry := range s.entries {
+		if entry.Key == key {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// FileOutboxStore keeps queued writes in a JSON file so they survive
+// restarts. The file is replaced atomically on every change.
+type FileOutboxStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileOutboxStore creates an outbox backed by the file at path, which is
+// created on the first Append
+func NewFileOutboxStore(path string) *FileOutboxStore {
+	return &FileOutboxStore{path: path}
+}
+
+// Append implements OutboxStore
+func (s *FileOutboxStore) Append(ctx context.Context, entry *OutboxEntry) error {
+	return s.modify(func(entries []*OutboxEntry) []*OutboxEntry {
+		return append(entries, entry)
+	})
+}
+
+// List implements OutboxStore
+func (s *FileOutboxStore) List(ctx context.Context) ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Update implements OutboxStore
+func (s *FileOutboxStore) Update(ctx context.Context, entry *OutboxEntry) error {
+	return s.modify(func(entries []*OutboxEntry) []*OutboxEntry {
+		for i, existing := range entries {
+			if existing.Key == entry.Key {
+				entries[i] = entry
+			}
+		}
+		return entries
+	})
+}
+
+// Remove implements OutboxStore
+func (s *FileOutboxStore) Remove(ctx context.Context, key string) error {
+	return s.modify(func(entries []*OutboxEntry) []*OutboxEntry {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if entry.Key != key {
+				kept = append(kept, entry)
+			}
+		}
+		return kept
+	})
+}
+
+func (s *FileOutboxStore) modify(fn func([]*OutboxEntry) []*OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fn(entries), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+	return nil
+}
+
+func (s *FileOutboxStore) read() ([]*OutboxEntry, error) {
+	var entries []*OutboxEntry
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse outbox: %w", err)
+	}
+	return entries, nil
+}
+
+// writeFileAtomic replaces the file at path with data via a temporary file
+// in the same directory
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ErrQueued is returned by Result.Err for a write that was queued in the
+// outbox instead of being applied. ReplayOutbox sends it later.
+var ErrQueued = errors.New("write queued in outbox")
+
+// writeRequest sends a CreateItem or UpdateItem write. With an Outbox
+// configured, the write carries an idempotency key and is queued for
+// ReplayOutbox when the API is unreachable. While earlier writes are queued,
+// they are replayed first and a write that cannot follow them is queued
+// behind them, so writes are applied in order.
+func (c *Client) writeRequest(method, path string, body map[string]interface{}) *Result {
+	if c.outbox == nil {
+		resp, err := c.makeRequest(method, path, body, nil)
+		if err != nil {
+			return requestFailed(err)
+		}
+		return c.parseResponse(resp)
+	}
+
+	key, err := UUIDv7Generator{}.NewID()
+	if err != nil {
+		return requestFailed(fmt.Errorf("failed to generate idempotency key: %w", err))
+	}
+	entry := &OutboxEntry{Key: key, Method: method, Path: path, Body: body}
+
+	pending, err := c.outbox.List(c.context())
+	if err != nil {
+		return requestFailed(fmt.Errorf("failed to read outbox: %w", err))
+	}
+	if len(pending) > 0 {
+		// Rejected writes are logged by ReplayOutbox; only whether the
+		// outbox drained matters here
+		c.ReplayOutbox(c.context())
+		if pending, err = c.outbox.List(c.context()); err != nil {
+			return requestFailed(fmt.Errorf("failed to read outbox: %w", err))
+		}
+	}
+	if len(pending) > 0 {
+		entry.LastError = fmt.Sprintf("queued behind %d earlier writes", len(pending))
+		return c.queueWrite(entry, nil)
+	}
+
+	result := c.sendOutboxEntry(entry)
+	if !unreachable(result) {
+		return result
+	}
+	entry.Attempts = 1
+	entry.LastError = result.Err().Error()
+	return c.queueWrite(entry, result)
+}
+
+// queueWrite appends entry to the outbox and returns a failed result
+// wrapping ErrQueued, or failed when the outbox cannot take the entry
+func (c *Client) queueWrite(entry *OutboxEntry, failed *Result) *Result {
+	entry.QueuedAt = time.Now()
+	if err := c.outbox.Append(c.context(), entry); err != nil {
+		c.logger.Error("failed to queue write", "method", entry.Method, "path", entry.Path, "error", err)
+		if failed == nil {
+			failed = requestFailed(fmt.Errorf("failed to queue write: %w", err))
+		}
+		return failed
+	}
+	c.logger.Warn("queued write for replay", "method", entry.Method, "path", entry.Path, "key", entry.Key, "reason", entry.LastError)
+
+	err := fmt.Errorf("%w: %s", ErrQueued, entry.LastError)
+	return &Result{
+		Success: false,
+		Error:   err.Error(),
+		Meta:    map[string]interface{}{"queued": true},
+		err:     err,
+		queued:  entry,
+	}
+}
+
+// sendOutboxEntry sends a write with its idempotency key
+func (c *Client) sendOutboxEntry(entry *OutboxEntry) *Result {
+	resp, err := c.makeRequestWithHeaders(entry.Method, entry.Path, entry.Body, nil, map[string]string{
+		IdempotencyKeyHeader: entry.Key,
+	})
+	if err != nil {
+		return requestFailed(err)
+	}
+	return c.parseResponse(resp)
+}
+
+// unreachable reports whether a write failed because the API could not be
+// reached, as opposed to being rejected
+func unreachable(result *Result) bool {
+	if result.Success {
+		return false
+	}
+	switch result.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case 0:
+		return result.err != nil && IsRetryable(result.err)
+	}
+	return false
+}
+
+// ReplayOutbox sends queued writes in order, removing each once the API
+// has applied or rejected it. It stops at the first write the API is still
+// unreachable for, so later writes are not applied before earlier ones. It
+// returns how many writes were applied, and the rejected writes as
+// *OutboxError values joined with any error that stopped the replay.
+func (c *Client) ReplayOutbox(ctx context.Context) (int, error) {
+	if c.outbox == nil {
+		return 0, errors.New("no outbox configured")
+	}
+
+	entries, err := c.outbox.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	client := c.WithContext(ctx)
+	applied := 0
+	var errs []error
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return applied, errors.Join(append(errs, err)...)
+		}
+
+		result := client.sendOutboxEntry(entry)
+		entry.Attempts++
+		if unreachable(result) {
+			entry.LastError = result.Err().Error()
+			if err := c.outbox.Update(ctx, entry); err != nil {
+				errs = append(errs, err)
+			}
+			return applied, errors.Join(append(errs, fmt.Errorf("API unreachable: %w", result.Err()))...)
+		}
+
+		if result.Success {
+			applied++
+		} else {
+			c.logger.Warn("queued write rejected", "method", entry.Method, "path", entry.Path, "key", entry.Key, "error", result.Error)
+			errs = append(errs, &OutboxError{Entry: entry, Err: result.Err()})
+		}
+		if err := c.outbox.Remove(ctx, entry.Key); err != nil {
+			return applied, errors.Join(append(errs, err)...)
+		}
+	}
+
+	return applied, errors.Join(errs...)
+}
+
+// RunOutbox calls ReplayOutbox every interval until ctx is cancelled,
+// passing errors to onError if it is not nil
+func (c *Client) RunOutbox(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.ReplayOutbox(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// OutboxError is a queued write the API rejected on replay. The write is
+// removed from the outbox.
+type OutboxError struct {
+	Entry *OutboxEntry
+	Err   error
+}
+
+func (e *OutboxError) Error() string {
+	return fmt.Sprintf("queued %s %s rejected: %v", e.Entry.Method, e.Entry.Path, e.Err)
+}
+
+// Unwrap returns the API error
+func (e *OutboxError) Unwrap() error {
+	return e.Err
+}
+
+// IsQueued reports whether the write was queued in the outbox because the
+// API was unreachable or earlier writes were still queued; it is sent by
+// ReplayOutbox. Err returns an error wrapping ErrQueued for such results.
+func (r *Result) IsQueued() bool {
+	return r.queued != nil
+}
+
+// QueuedEntry returns the outbox entry of a queued write, or nil
+func (r *Result) QueuedEntry() *OutboxEntry {
+	return r.queued
+}
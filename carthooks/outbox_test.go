@@ -0,0 +1,176 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestClient_OutboxQueuesAndReplays(t *testing.T) {
+	var mu sync.Mutex
+	status := http.StatusServiceUnavailable
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error": {"message": "service unavailable"}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"id": 1}}`))
+	}))
+	defer server.Close()
+
+	outbox := NewMemoryOutboxStore()
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Outbox: outbox})
+
+	result := client.CreateItem(1, 2, map[string]interface{}{"title": "a"})
+	if result.Success || !result.IsQueued() || !errors.Is(result.Err(), ErrQueued) {
+		t.Fatalf("Expected queued result, got %s", result)
+	}
+	client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "b"})
+
+	ctx := context.Background()
+	if applied, err := client.ReplayOutbox(ctx); applied != 0 || err == nil {
+		t.Errorf("Expected replay to stop while unreachable, got %d, %v", applied, err)
+	}
+
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+
+	applied, err := client.ReplayOutbox(ctx)
+	if applied != 2 || err != nil {
+		t.Fatalf("ReplayOutbox() = %d, %v", applied, err)
+	}
+	if entries, _ := outbox.List(ctx); len(entries) != 0 {
+		t.Errorf("Expected empty outbox, got %d entries", len(entries))
+	}
+
+	// create, create replayed before the update, create retry, create
+	// replay, update replay
+	if len(keys) != 5 || keys[0] == "" || keys[0] != keys[1] || keys[0] != keys[2] || keys[0] != keys[3] || keys[4] == "" || keys[0] == keys[4] {
+		t.Errorf("Expected a stable idempotency key per write, got %v", keys)
+	}
+}
+
+func TestClient_OutboxKeepsWriteOrder(t *testing.T) {
+	var mu sync.Mutex
+	reachable := false
+	var applied []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !reachable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		applied = append(applied, body.Data["title"].(string))
+		w.Write([]byte(`{"data": {"id": 3}}`))
+	}))
+	defer server.Close()
+
+	outbox := NewMemoryOutboxStore()
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Outbox: outbox})
+
+	client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "stale"})
+
+	mu.Lock()
+	reachable = true
+	mu.Unlock()
+
+	// The queued write is replayed before the newer one is sent
+	result := client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "fresh"})
+	if !result.Success {
+		t.Fatalf("Expected the newer write to succeed, got %s", result)
+	}
+	if len(applied) != 2 || applied[0] != "stale" || applied[1] != "fresh" {
+		t.Errorf("Expected writes applied in order, got %v", applied)
+	}
+	if entries, _ := outbox.List(context.Background()); len(entries) != 0 {
+		t.Errorf("Expected empty outbox, got %d entries", len(entries))
+	}
+}
+
+func TestClient_OutboxQueuesBehindPendingWrites(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	outbox := NewMemoryOutboxStore()
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Outbox: outbox})
+
+	client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "a"})
+	result := client.UpdateItem(1, 2, 3, map[string]interface{}{"title": "b"})
+	if !result.IsQueued() || result.QueuedEntry().Attempts != 0 {
+		t.Fatalf("Expected the write to be queued unsent, got %s", result)
+	}
+	// The first write and its replay; the second write is never sent
+	// ahead of the first
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	entries, _ := outbox.List(context.Background())
+	if len(entries) != 2 || entries[1].Body["data"].(map[string]interface{})["title"] != "b" {
+		t.Errorf("Expected both writes queued in order, got %+v", entries)
+	}
+}
+
+func TestClient_OutboxDropsRejectedWrites(t *testing.T) {
+	rejected := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rejected {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error": {"message": "invalid field", "code": "VALIDATION_ERROR"}}`))
+	}))
+	defer server.Close()
+
+	outbox := NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox.json"))
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Outbox: outbox})
+	client.CreateItem(1, 2, map[string]interface{}{"title": "a"})
+
+	rejected = true
+	applied, err := client.ReplayOutbox(context.Background())
+	var outboxErr *OutboxError
+	if applied != 0 || !errors.As(err, &outboxErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("ReplayOutbox() = %d, %v", applied, err)
+	}
+	if outboxErr.Entry.Body["data"].(map[string]interface{})["title"] != "a" {
+		t.Errorf("Unexpected entry %+v", outboxErr.Entry)
+	}
+	if entries, _ := outbox.List(context.Background()); len(entries) != 0 {
+		t.Errorf("Expected rejected write to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestClient_WritesWithoutOutboxAreNotQueued(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(IdempotencyKeyHeader) != "" {
+			t.Error("Expected no idempotency key without an outbox")
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	if result := client.CreateItem(1, 2, nil); result.Success || result.IsQueued() {
+		t.Errorf("Expected failure, got %s", result)
+	}
+}
@@ -0,0 +1,316 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PollingOptions configures a PollingSource
+type PollingOptions struct {
+	// Interval between polls (default 5s)
+	Interval time.Duration
+	// PageSize is the maximum number of changed items fetched per poll
+	// (default 100)
+	PageSize int
+	// Since is the point in time to start from (default now)
+	Since time.Time
+	// Filters restrict which items are watched, as in QueryOptions
+	Filters map[string]interface{}
+}
+
+// PollingSource is a MessageSource that periodically queries a collection for
+// items changed since the last poll, so a Watcher can run without any queue.
+// It suits local development; it cannot observe deletions.
+//
+// The cursor only advances past items once they are acked. Nacking an item
+// rewinds polling to the cursor so the item is fetched and delivered again.
+type PollingSource struct {
+	client       *Client
+	appID        uint
+	collectionID uint
+	options      PollingOptions
+
+	mu           sync.Mutex
+	cursor       int64         // updated_at of the newest acked item
+	seenAtCursor map[uint]bool // items acked at cursor
+	pending      []*pollEntry  // delivered items past cursor, oldest first
+	byMessage    map[string]*pollEntry
+
+	// Read position of the next poll: updated_at, IDs delivered in that
+	// second and the page of the $gte query to fetch
+	readCursor int64
+	readSeen   map[uint]bool
+	page       int
+	lastPoll   time.Time
+}
+
+// pollEntry tracks a delivered item until the cursor moves past it
+type pollEntry struct {
+	id        uint
+	updatedAt int64
+	state     pollState
+}
+
+type pollState int
+
+const (
+	pollInFlight pollState = iota
+	pollAcked
+	pollNacked
+)
+
+// NewPollingSource creates a polling source for a collection
+func NewPollingSource(client *Client, appID, collectionID uint, options *PollingOptions) *PollingSource {
+	var opts PollingOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+	if opts.Since.IsZero() {
+		opts.Since = time.Now()
+	}
+
+	return &PollingSource{
+		client:       client,
+		appID:        appID,
+		collectionID: collectionID,
+		options:      opts,
+		cursor:       opts.Since.Unix(),
+		seenAtCursor: map[uint]bool{},
+		byMessage:    map[string]*pollEntry{},
+		readCursor:   opts.Since.Unix(),
+		readSeen:     map[uint]bool{},
+		page:         1,
+	}
+}
+
+// Receive waits for the poll interval and returns items changed since the
+// previous poll, oldest first
+func (s *PollingSource) Receive(ctx context.Context) ([]*Message, error) {
+	if wait := time.Until(s.lastPoll.Add(s.options.Interval)); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	s.lastPoll = time.Now()
+
+	client := s.client.WithContext(ctx)
+	for {
+		s.mu.Lock()
+		filters := make(map[string]interface{}, len(s.options.Filters)+1)
+		for k, v := range s.options.Filters {
+			filters[k] = v
+		}
+		filters["updated_at"] = map[string]interface{}{"$gte": s.readCursor}
+		options := &QueryOptions{
+			Pagination: &PaginationOptions{
+				Page:     s.page,
+				PageSize: s.options.PageSize,
+			},
+			Filters: filters,
+			Sort:    []string{"updated_at:asc", "id:asc"},
+		}
+		s.mu.Unlock()
+
+		result := client.QueryItems(s.appID, s.collectionID, options)
+		if !result.Success {
+			return nil, fmt.Errorf("failed to poll for changes: %s", result.Error)
+		}
+
+		var items []map[string]interface{}
+		if err := result.GetData(&items); err != nil {
+			return nil, fmt.Errorf("failed to decode polled items: %w", err)
+		}
+
+		messages, err := s.deliver(items)
+		if err != nil || len(messages) > 0 || len(items) < s.options.PageSize {
+			return messages, err
+		}
+		// A whole page was delivered already; read on without waiting
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// deliver turns the polled items not yet delivered into messages and moves
+// the read position past them, as exportItemsSince does for pages
+func (s *PollingSource) deliver(items []map[string]interface{}) ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []*Message
+	var newest int64
+	for _, item := range items {
+		var record RecordFormat
+		if data, err := json.Marshal(item); err == nil {
+			json.Unmarshal(data, &record)
+		}
+
+		// Items updated within the read cursor's second are returned again
+		// by the next $gte query; skip those already delivered
+		if record.UpdatedAt == s.readCursor && s.readSeen[record.ID] {
+			continue
+		}
+		if record.UpdatedAt > newest {
+			newest = record.UpdatedAt
+		}
+		// After a rewind, skip items acked or still in flight
+		if record.UpdatedAt < s.cursor || record.UpdatedAt == s.cursor && s.seenAtCursor[record.ID] {
+			continue
+		}
+		message, err := s.message(record, item)
+		if err != nil {
+			return nil, err
+		}
+		if entry := s.byMessage[message.ID]; entry != nil && entry.state != pollNacked {
+			continue
+		}
+		s.track(record, message.ID)
+		messages = append(messages, message)
+	}
+
+	if newest > s.readCursor {
+		s.readCursor = newest
+		s.readSeen = map[uint]bool{}
+		s.page = 1
+	} else if len(items) == s.options.PageSize {
+		s.page++
+	}
+	for _, item := range items {
+		if id, updatedAt := pollItemKey(item); updatedAt == s.readCursor {
+			s.readSeen[id] = true
+		}
+	}
+	return messages, nil
+}
+
+// track records a delivered item as in flight, dropping nacked deliveries
+// it supersedes so they no longer hold the cursor back
+func (s *PollingSource) track(record RecordFormat, messageID string) {
+	kept := s.pending[:0]
+	for _, entry := range s.pending {
+		if entry.id == record.ID && entry.state == pollNacked {
+			delete(s.byMessage, pollMessageID(entry.id, entry.updatedAt))
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	s.pending = kept
+
+	entry := &pollEntry{id: record.ID, updatedAt: record.UpdatedAt}
+	s.byMessage[messageID] = entry
+	s.pending = append(s.pending, entry)
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		a, b := s.pending[i], s.pending[j]
+		if a.updatedAt != b.updatedAt {
+			return a.updatedAt < b.updatedAt
+		}
+		return a.id < b.id
+	})
+	s.commit()
+}
+
+// commit advances the cursor over the acked items at the head of pending
+func (s *PollingSource) commit() {
+	for len(s.pending) > 0 && s.pending[0].state == pollAcked {
+		entry := s.pending[0]
+		if entry.updatedAt > s.cursor {
+			s.cursor = entry.updatedAt
+			s.seenAtCursor = map[uint]bool{}
+		}
+		s.seenAtCursor[entry.id] = true
+		delete(s.byMessage, pollMessageID(entry.id, entry.updatedAt))
+		s.pending = s.pending[1:]
+	}
+}
+
+// pollItemKey returns the ID and updated_at of a polled item
+func pollItemKey(item map[string]interface{}) (uint, int64) {
+	var record RecordFormat
+	if data, err := json.Marshal(item); err == nil {
+		json.Unmarshal(data, &record)
+	}
+	return record.ID, record.UpdatedAt
+}
+
+func pollMessageID(id uint, updatedAt int64) string {
+	return strconv.FormatUint(uint64(id), 10) + "@" + strconv.FormatInt(updatedAt, 10)
+}
+
+// message wraps a changed item in the same envelope queue-based sources
+// deliver
+func (s *PollingSource) message(record RecordFormat, item map[string]interface{}) (*Message, error) {
+	event := EventCodeRecordUpdated
+	if record.CreatedAt == record.UpdatedAt {
+		event = EventCodeRecordCreated
+	}
+
+	body, err := json.Marshal(&EventMessage{
+		Version: "1",
+		Meta: EventMessageMeta{
+			CollectionID: s.collectionID,
+			Event:        event,
+			TriggerType:  "polling",
+		},
+		Payload: item,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode polled item: %w", err)
+	}
+
+	return &Message{
+		ID:   pollMessageID(record.ID, record.UpdatedAt),
+		Body: body,
+		Raw:  item,
+	}, nil
+}
+
+// Ack moves the cursor past the item once every older delivery is acked
+func (s *PollingSource) Ack(ctx context.Context, message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry := s.byMessage[message.ID]; entry != nil {
+		entry.state = pollAcked
+		s.commit()
+	}
+	return nil
+}
+
+// Nack rewinds polling to the cursor so the item is delivered again by a
+// later Receive. Items still in flight or acked are not redelivered.
+func (s *PollingSource) Nack(ctx context.Context, message *Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.byMessage[message.ID]
+	if entry == nil {
+		return nil
+	}
+	entry.state = pollNacked
+	s.readCursor = s.cursor
+	s.readSeen = make(map[uint]bool, len(s.seenAtCursor))
+	for id := range s.seenAtCursor {
+		s.readSeen[id] = true
+	}
+	s.page = 1
+	return nil
+}
+
+// Close is a no-op
+func (s *PollingSource) Close() error {
+	return nil
+}
@@ -0,0 +1,201 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPollingSource_Receive(t *testing.T) {
+	items := []map[string]interface{}{
+		{"id": 1, "title": "New", "created_at": 1000, "updated_at": 1000},
+		{"id": 2, "title": "Changed", "created_at": 900, "updated_at": 1000},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var options QueryOptions
+		json.NewDecoder(r.Body).Decode(&options)
+
+		filter := options.Filters["updated_at"].(map[string]interface{})
+		since := filter["$gte"].(float64)
+
+		var changed []map[string]interface{}
+		for _, item := range items {
+			if float64(item["updated_at"].(int)) >= since {
+				changed = append(changed, item)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": changed})
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	source := NewPollingSource(client, 123, 456, &PollingOptions{
+		Interval: time.Millisecond,
+		Since:    time.Unix(500, 0),
+	})
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+
+	var event EventMessage
+	if err := json.Unmarshal(messages[0].Body, &event); err != nil {
+		t.Fatalf("Failed to decode message body: %v", err)
+	}
+	if event.Meta.Event != EventCodeRecordCreated || event.Meta.CollectionID != 456 {
+		t.Errorf("Unexpected meta %+v", event.Meta)
+	}
+
+	// Items at the cursor are not delivered twice
+	messages, err = source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected no new messages, got %d", len(messages))
+	}
+
+	items = append(items, map[string]interface{}{"id": 3, "created_at": 1001, "updated_at": 1001})
+	messages, _ = source.Receive(context.Background())
+	if len(messages) != 1 {
+		t.Errorf("Expected 1 new message, got %d", len(messages))
+	}
+}
+
+// pollingServer serves items filtered by updated_at $gte and paged in
+// updated_at, id order, as the query endpoint does
+func pollingServer(t *testing.T, items *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var options QueryOptions
+		json.NewDecoder(r.Body).Decode(&options)
+
+		filter := options.Filters["updated_at"].(map[string]interface{})
+		since := filter["$gte"].(float64)
+
+		var changed []map[string]interface{}
+		for _, item := range *items {
+			if float64(item["updated_at"].(int)) >= since {
+				changed = append(changed, item)
+			}
+		}
+		sort.Slice(changed, func(i, j int) bool {
+			a, b := changed[i], changed[j]
+			if a["updated_at"].(int) != b["updated_at"].(int) {
+				return a["updated_at"].(int) < b["updated_at"].(int)
+			}
+			return a["id"].(int) < b["id"].(int)
+		})
+
+		page, size := options.Pagination.Page, options.Pagination.PageSize
+		start := (page - 1) * size
+		if start > len(changed) {
+			start = len(changed)
+		}
+		end := start + size
+		if end > len(changed) {
+			end = len(changed)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": changed[start:end]})
+	}))
+}
+
+func TestPollingSource_PagesThroughOneSecond(t *testing.T) {
+	var items []map[string]interface{}
+	for id := 1; id <= 5; id++ {
+		items = append(items, map[string]interface{}{"id": id, "created_at": 1000, "updated_at": 1000})
+	}
+	server := pollingServer(t, &items)
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	source := NewPollingSource(client, 123, 456, &PollingOptions{
+		Interval: time.Millisecond,
+		PageSize: 2,
+		Since:    time.Unix(500, 0),
+	})
+
+	delivered := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		messages, err := source.Receive(context.Background())
+		if err != nil {
+			t.Fatalf("Receive() failed: %v", err)
+		}
+		for _, message := range messages {
+			if delivered[message.ID] {
+				t.Errorf("Message %s delivered twice", message.ID)
+			}
+			delivered[message.ID] = true
+			source.Ack(context.Background(), message)
+		}
+	}
+	if len(delivered) != 5 {
+		t.Fatalf("Expected all 5 items sharing one second to be delivered, got %d", len(delivered))
+	}
+
+	items = append(items, map[string]interface{}{"id": 6, "created_at": 1000, "updated_at": 1001})
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != "6@1001" {
+		t.Errorf("Expected item 6 after the crowded second, got %+v", messages)
+	}
+}
+
+func TestPollingSource_NackRedelivers(t *testing.T) {
+	items := []map[string]interface{}{
+		{"id": 1, "created_at": 1000, "updated_at": 1000},
+		{"id": 2, "created_at": 1000, "updated_at": 1001},
+		{"id": 3, "created_at": 1000, "updated_at": 1002},
+	}
+	server := pollingServer(t, &items)
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	source := NewPollingSource(client, 123, 456, &PollingOptions{
+		Interval: time.Millisecond,
+		Since:    time.Unix(500, 0),
+	})
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(messages))
+	}
+	source.Ack(context.Background(), messages[0])
+	source.Nack(context.Background(), messages[1])
+	source.Ack(context.Background(), messages[2])
+
+	messages, err = source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != "2@1001" {
+		t.Fatalf("Expected only the nacked item to be redelivered, got %+v", messages)
+	}
+	if source.cursor != 1000 {
+		t.Errorf("Expected cursor to stay before the nacked item, got %d", source.cursor)
+	}
+
+	source.Ack(context.Background(), messages[0])
+	if source.cursor != 1002 || len(source.pending) != 0 {
+		t.Errorf("Expected cursor to advance past acked items, got %d with %d pending", source.cursor, len(source.pending))
+	}
+	messages, _ = source.Receive(context.Background())
+	if len(messages) != 0 {
+		t.Errorf("Expected no messages after acking everything, got %d", len(messages))
+	}
+}
@@ -0,0 +1,102 @@
+// Package promclient exports carthooks.Client request metrics to Prometheus.
+//
+//	metrics := promclient.New(prometheus.DefaultRegisterer, promclient.Config{})
+//	client := carthooks.NewClient(&carthooks.ClientConfig{Metrics: metrics})
+//
+// Requests are labelled with the HTTP method and the endpoint's path
+// template, e.g. /v1/apps/{app_id}/collections/{collection_id}/items, so
+// latency and error rates can be charted per endpoint.
+package promclient
+
+import (
+	"strconv"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config configures the exported metrics
+type Config struct {
+	// Namespace prefixes metric names (default "carthooks")
+	Namespace string
+	// ConstLabels are added to every metric, e.g. to tell several clients
+	// in one process apart
+	ConstLabels prometheus.Labels
+	// Buckets for the request duration histogram (default
+	// prometheus.DefBuckets)
+	Buckets []float64
+}
+
+// Metrics is a carthooks.MetricsHook backed by Prometheus collectors
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	queueWait *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+}
+
+var _ carthooks.MetricsHook = (*Metrics)(nil)
+
+// New creates the client metrics and registers them with registerer
+func New(registerer prometheus.Registerer, config Config) *Metrics {
+	if config.Namespace == "" {
+		config.Namespace = "carthooks"
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "client",
+			Name:        "requests_total",
+			Help:        "API requests by endpoint and status; status is \"error\" when no response was received.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"method", "path", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "client",
+			Name:        "request_duration_seconds",
+			Help:        "Duration of API requests by endpoint.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     config.Buckets,
+		}, []string{"method", "path"}),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "client",
+			Name:        "request_queue_wait_seconds",
+			Help:        "Time API requests waited for a slot under MaxConcurrentRequests, by endpoint.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     config.Buckets,
+		}, []string{"method", "path"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "client",
+			Name:        "requests_in_flight",
+			Help:        "API requests currently in flight by endpoint.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"method", "path"}),
+	}
+
+	registerer.MustRegister(m.requests, m.duration, m.queueWait, m.inFlight)
+	return m
+}
+
+// OnRequestStart implements carthooks.MetricsHook
+func (m *Metrics) OnRequestStart(method, path string) {
+	m.inFlight.WithLabelValues(method, path).Inc()
+}
+
+// OnRequestEnd implements carthooks.MetricsHook
+func (m *Metrics) OnRequestEnd(metric carthooks.RequestMetric) {
+	m.inFlight.WithLabelValues(metric.Method, metric.Path).Dec()
+
+	status := "error"
+	if metric.Err == nil {
+		status = strconv.Itoa(metric.StatusCode)
+	}
+	m.requests.WithLabelValues(metric.Method, metric.Path, status).Inc()
+	m.duration.WithLabelValues(metric.Method, metric.Path).Observe(metric.Duration.Seconds())
+	m.queueWait.WithLabelValues(metric.Method, metric.Path).Observe(metric.QueueWait.Seconds())
+}
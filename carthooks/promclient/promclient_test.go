@@ -0,0 +1,48 @@
+package promclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_WithClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	metrics := New(registry, Config{})
+	client := carthooks.NewClient(&carthooks.ClientConfig{BaseURL: server.URL, Metrics: metrics})
+
+	client.GetItemByID(1, 2, 3, nil)
+	client.GetItemByID(1, 2, 4, nil)
+	client.DeleteItem(1, 2, 3)
+
+	item := "/v1/apps/{app_id}/collections/{collection_id}/items/{item_id}"
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues("GET", item, "200")); got != 2 {
+		t.Errorf("Expected 2 successful reads, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues("DELETE", item, "404")); got != 1 {
+		t.Errorf("Expected 1 failed delete, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.duration); got != 2 {
+		t.Errorf("Expected a duration histogram per endpoint, got %d series", got)
+	}
+	if got := testutil.CollectAndCount(metrics.queueWait); got != 2 {
+		t.Errorf("Expected a queue wait histogram per endpoint, got %d series", got)
+	}
+	if got := testutil.ToFloat64(metrics.inFlight.WithLabelValues("GET", item)); got != 0 {
+		t.Errorf("Expected no requests in flight, got %v", got)
+	}
+}
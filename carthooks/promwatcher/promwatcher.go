@@ -0,0 +1,123 @@
+// Package promwatcher exports carthooks.Watcher metrics to Prometheus.
+//
+//	metrics := promwatcher.New(prometheus.DefaultRegisterer, promwatcher.Config{WatcherID: "inventory-sync"})
+//	watcher, _ := carthooks.NewWatcherBuilder(client, "inventory-sync").WithMetrics(metrics).Build()
+//
+// To alert on a stalled watcher, compare
+// carthooks_watcher_last_poll_timestamp_seconds with time().
+package promwatcher
+
+import (
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config configures the exported metrics
+type Config struct {
+	// Namespace prefixes metric names (default "carthooks")
+	Namespace string
+	// WatcherID is added as the "watcher" label, to tell several watchers
+	// in one process apart
+	WatcherID string
+	// Buckets for the handler duration histogram (default
+	// prometheus.DefBuckets)
+	Buckets []float64
+}
+
+// Metrics is a carthooks.WatcherMetrics backed by Prometheus collectors
+type Metrics struct {
+	received        prometheus.Counter
+	processed       prometheus.Counter
+	failed          prometheus.Counter
+	pollErrors      prometheus.Counter
+	emptyPolls      prometheus.Counter
+	handlerDuration prometheus.Histogram
+	lastPoll        prometheus.Gauge
+}
+
+var _ carthooks.WatcherMetrics = (*Metrics)(nil)
+
+// New creates the watcher metrics and registers them with registerer
+func New(registerer prometheus.Registerer, config Config) *Metrics {
+	if config.Namespace == "" {
+		config.Namespace = "carthooks"
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	var labels prometheus.Labels
+	if config.WatcherID != "" {
+		labels = prometheus.Labels{"watcher": config.WatcherID}
+	}
+
+	counter := func(name, help string) prometheus.Counter {
+		return prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "watcher",
+			Name:        name,
+			Help:        help,
+			ConstLabels: labels,
+		})
+	}
+
+	m := &Metrics{
+		received:   counter("messages_received_total", "Messages received from the source."),
+		processed:  counter("messages_processed_total", "Messages handled successfully."),
+		failed:     counter("messages_failed_total", "Messages that failed parsing or handling."),
+		pollErrors: counter("poll_errors_total", "Failed receives from the source."),
+		emptyPolls: counter("empty_polls_total", "Receives that returned no messages."),
+		handlerDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "watcher",
+			Name:        "handler_duration_seconds",
+			Help:        "Duration of handler invocations.",
+			ConstLabels: labels,
+			Buckets:     config.Buckets,
+		}),
+		lastPoll: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   "watcher",
+			Name:        "last_poll_timestamp_seconds",
+			Help:        "Unix time of the last completed receive, successful or empty.",
+			ConstLabels: labels,
+		}),
+	}
+
+	registerer.MustRegister(m.received, m.processed, m.failed, m.pollErrors, m.emptyPolls, m.handlerDuration, m.lastPoll)
+	return m
+}
+
+// MessagesReceived implements carthooks.WatcherMetrics
+func (m *Metrics) MessagesReceived(count int) {
+	m.received.Add(float64(count))
+	m.lastPoll.SetToCurrentTime()
+}
+
+// EmptyPoll implements carthooks.WatcherMetrics
+func (m *Metrics) EmptyPoll() {
+	m.emptyPolls.Inc()
+	m.lastPoll.SetToCurrentTime()
+}
+
+// PollError implements carthooks.WatcherMetrics
+func (m *Metrics) PollError() {
+	m.pollErrors.Inc()
+}
+
+// MessageProcessed implements carthooks.WatcherMetrics
+func (m *Metrics) MessageProcessed() {
+	m.processed.Inc()
+}
+
+// MessageFailed implements carthooks.WatcherMetrics
+func (m *Metrics) MessageFailed() {
+	m.failed.Inc()
+}
+
+// HandlerDuration implements carthooks.WatcherMetrics
+func (m *Metrics) HandlerDuration(duration time.Duration) {
+	m.handlerDuration.Observe(duration.Seconds())
+}
@@ -0,0 +1,58 @@
+package promwatcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/carthooks/carthooks-sdk-go/carthooks/memwatcher"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_WithWatcher(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := New(registry, Config{WatcherID: "test"})
+
+	source := memwatcher.New()
+	watcher, err := carthooks.NewWatcher(&carthooks.WatcherConfig{
+		Source:  source,
+		Metrics: metrics,
+		Handler: func(ctx context.Context, event *carthooks.EventMessage) error {
+			if event.Record()["title"] == "bad" {
+				return errors.New("rejected")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+
+	go watcher.Run(context.Background())
+	defer watcher.Stop()
+
+	source.Push(memwatcher.Created(456, map[string]interface{}{"id": 1, "title": "good"}))
+	source.Push(memwatcher.Created(456, map[string]interface{}{"id": 2, "title": "bad"}))
+	if err := source.Drain(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testutil.ToFloat64(metrics.processed); got != 1 {
+		t.Errorf("Expected 1 processed, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.failed); got != 1 {
+		t.Errorf("Expected 1 failed, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.received); got != 2 {
+		t.Errorf("Expected 2 received, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.handlerDuration); got != 1 {
+		t.Errorf("Expected handler duration histogram, got %d series", got)
+	}
+	if testutil.ToFloat64(metrics.lastPoll) == 0 {
+		t.Error("Expected last poll timestamp to be set")
+	}
+}
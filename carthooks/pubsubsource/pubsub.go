@@ -0,0 +1,182 @@
+// Package pubsubsource provides a Google Cloud Pub/Sub carthooks.MessageSource
+// using subscription pull over the Pub/Sub REST API.
+//
+// The source does not handle Google authentication itself. Pass an
+// authenticated *http.Client, for example from
+// golang.org/x/oauth2/google.DefaultClient(ctx, "https://www.googleapis.com/auth/pubsub").
+package pubsubsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// DefaultEndpoint is the Pub/Sub REST API base URL
+const DefaultEndpoint = "https://pubsub.googleapis.com/v1"
+
+// Config holds configuration for a Pub/Sub source
+type Config struct {
+	// Subscription is the full subscription name,
+	// projects/{project}/subscriptions/{subscription}
+	Subscription string
+	// HTTPClient must attach Google credentials to requests
+	HTTPClient *http.Client
+	// MaxMessages is the maximum number of messages per pull (default 10)
+	MaxMessages int
+	// Endpoint overrides DefaultEndpoint, e.g. for the Pub/Sub emulator
+	Endpoint string
+}
+
+// Source is a carthooks.MessageSource backed by a Pub/Sub subscription.
+// Ack acknowledges a message; Nack sets its ack deadline to zero so Pub/Sub
+// redelivers it immediately.
+type Source struct {
+	config Config
+}
+
+type pubsubMessage struct {
+	Data        string            `json:"data"`
+	Attributes  map[string]string `json:"attributes"`
+	MessageID   string            `json:"messageId"`
+	PublishTime string            `json:"publishTime"`
+}
+
+type pullResponse struct {
+	ReceivedMessages []struct {
+		AckID           string        `json:"ackId"`
+		Message         pubsubMessage `json:"message"`
+		DeliveryAttempt int           `json:"deliveryAttempt"`
+	} `json:"receivedMessages"`
+}
+
+// New creates a Pub/Sub source
+func New(config Config) (*Source, error) {
+	if config.Subscription == "" {
+		return nil, fmt.Errorf("pubsub source requires a subscription")
+	}
+	if config.HTTPClient == nil {
+		return nil, fmt.Errorf("pubsub source requires an authenticated HTTP client")
+	}
+	if config.MaxMessages <= 0 {
+		config.MaxMessages = 10
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultEndpoint
+	}
+
+	return &Source{config: config}, nil
+}
+
+// Receive pulls a batch of messages from the subscription. A message whose
+// data cannot be decoded is delivered with a nil Body and the decoding error
+// in its "decodeError" attribute, so the Watcher fails it like any other
+// malformed message and its MaxAttempts and DeadLetter handling applies.
+func (s *Source) Receive(ctx context.Context) ([]*carthooks.Message, error) {
+	var resp pullResponse
+	if err := s.call(ctx, "pull", map[string]interface{}{"maxMessages": s.config.MaxMessages}, &resp); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*carthooks.Message, 0, len(resp.ReceivedMessages))
+	for _, received := range resp.ReceivedMessages {
+		attributes := make(map[string]string, len(received.Message.Attributes)+2)
+		for k, v := range received.Message.Attributes {
+			attributes[k] = v
+		}
+		if received.DeliveryAttempt > 0 {
+			attributes["deliveryAttempt"] = strconv.Itoa(received.DeliveryAttempt)
+		}
+
+		body, err := base64.StdEncoding.DecodeString(received.Message.Data)
+		if err != nil {
+			body = nil
+			attributes["decodeError"] = err.Error()
+		}
+
+		messages = append(messages, &carthooks.Message{
+			ID:           received.Message.MessageID,
+			Body:         body,
+			Attributes:   attributes,
+			ReceiveCount: received.DeliveryAttempt,
+			Raw:          received.AckID,
+		})
+	}
+	return messages, nil
+}
+
+// Ack acknowledges the message
+func (s *Source) Ack(ctx context.Context, message *carthooks.Message) error {
+	ackID, ok := message.Raw.(string)
+	if !ok {
+		return fmt.Errorf("not a Pub/Sub message")
+	}
+	return s.call(ctx, "acknowledge", map[string]interface{}{"ackIds": []string{ackID}}, nil)
+}
+
+// Nack makes the message immediately available for redelivery
+func (s *Source) Nack(ctx context.Context, message *carthooks.Message) error {
+	ackID, ok := message.Raw.(string)
+	if !ok {
+		return fmt.Errorf("not a Pub/Sub message")
+	}
+	return s.modifyAckDeadline(ctx, []string{ackID}, 0)
+}
+
+// modifyAckDeadline sets the ack deadline of messages; zero makes them
+// available for redelivery
+func (s *Source) modifyAckDeadline(ctx context.Context, ackIDs []string, seconds int) error {
+	return s.call(ctx, "modifyAckDeadline", map[string]interface{}{
+		"ackIds":             ackIDs,
+		"ackDeadlineSeconds": seconds,
+	}, nil)
+}
+
+// Close is a no-op; the HTTP client is owned by the caller
+func (s *Source) Close() error {
+	return nil
+}
+
+// call invokes a subscription method such as "pull" or "acknowledge"
+func (s *Source) call(ctx context.Context, method string, body interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s:%s", s.config.Endpoint, s.config.Subscription, method)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pubsub %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pubsub %s failed: %s: %s", method, resp.Status, string(respBody))
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse %s response: %w", method, err)
+		}
+	}
+	return nil
+}
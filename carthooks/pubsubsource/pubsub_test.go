@@ -0,0 +1,162 @@
+package pubsubsource
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const subscription = "projects/acme/subscriptions/carthooks"
+
+// recordedCall is a subscription method call received by the test server
+type recordedCall struct {
+	Method string
+	Body   map[string]interface{}
+}
+
+func newTestSource(t *testing.T, pull string) (*Source, func() []recordedCall) {
+	var mu sync.Mutex
+	var calls []recordedCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		prefix := "/v1/" + subscription + ":"
+		if r.Method != "POST" || len(r.URL.Path) <= len(prefix) || r.URL.Path[:len(prefix)] != prefix {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		method := r.URL.Path[len(prefix):]
+		mu.Lock()
+		calls = append(calls, recordedCall{Method: method, Body: body})
+		mu.Unlock()
+
+		if method == "pull" {
+			fmt.Fprint(w, pull)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	source, err := New(Config{
+		Subscription: subscription,
+		HTTPClient:   server.Client(),
+		Endpoint:     server.URL + "/v1",
+		MaxMessages:  5,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return source, func() []recordedCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedCall(nil), calls...)
+	}
+}
+
+func TestSource_Receive(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString([]byte(`{"version":"1"}`))
+	source, calls := newTestSource(t, `{"receivedMessages":[{"ackId":"ack-1","deliveryAttempt":2,"message":{"data":"`+data+`","messageId":"m-1","attributes":{"tenant":"3"}}}]}`)
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+	message := messages[0]
+	if message.ID != "m-1" || string(message.Body) != `{"version":"1"}` || message.Raw != "ack-1" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+	if message.Attributes["tenant"] != "3" || message.Attributes["deliveryAttempt"] != "2" || message.ReceiveCount != 2 {
+		t.Errorf("Unexpected attributes %v", message.Attributes)
+	}
+
+	got := calls()
+	if len(got) != 1 || got[0].Method != "pull" || got[0].Body["maxMessages"] != float64(5) {
+		t.Errorf("Unexpected calls %+v", got)
+	}
+}
+
+func TestSource_ReceivePassesOnUndecodableMessage(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString([]byte(`{"version":"1"}`))
+	source, calls := newTestSource(t, `{"receivedMessages":[
+		{"ackId":"ack-bad","deliveryAttempt":3,"message":{"data":"not base64!","messageId":"m-bad"}},
+		{"ackId":"ack-ok","message":{"data":"`+data+`","messageId":"m-ok"}}
+	]}`)
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 2 || messages[1].ID != "m-ok" {
+		t.Fatalf("Expected both messages, got %+v", messages)
+	}
+	bad := messages[0]
+	if bad.ID != "m-bad" || bad.Body != nil || bad.Attributes["decodeError"] == "" || bad.ReceiveCount != 3 || bad.Raw != "ack-bad" {
+		t.Errorf("Expected the undecodable message with a nil body and its decoding error, got %+v", bad)
+	}
+
+	if got := calls(); len(got) != 1 {
+		t.Errorf("Expected the undecodable message left to the Watcher, got %+v", got)
+	}
+}
+
+func TestSource_AckAndNack(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString([]byte(`{}`))
+	source, calls := newTestSource(t, `{"receivedMessages":[
+		{"ackId":"ack-1","message":{"data":"`+data+`","messageId":"m-1"}},
+		{"ackId":"ack-2","message":{"data":"`+data+`","messageId":"m-2"}}
+	]}`)
+	ctx := context.Background()
+
+	messages, err := source.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if err := source.Ack(ctx, messages[0]); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if err := source.Nack(ctx, messages[1]); err != nil {
+		t.Fatalf("Nack() failed: %v", err)
+	}
+
+	got := calls()
+	if len(got) != 3 {
+		t.Fatalf("Expected pull, acknowledge and modifyAckDeadline, got %+v", got)
+	}
+	if got[1].Method != "acknowledge" || got[1].Body["ackIds"].([]interface{})[0] != "ack-1" {
+		t.Errorf("Unexpected ack %+v", got[1])
+	}
+	if got[2].Method != "modifyAckDeadline" || got[2].Body["ackIds"].([]interface{})[0] != "ack-2" || got[2].Body["ackDeadlineSeconds"] != float64(0) {
+		t.Errorf("Unexpected nack %+v", got[2])
+	}
+}
+
+func TestSource_CallError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"message":"permission denied"}}`)
+	}))
+	defer server.Close()
+
+	source, _ := New(Config{Subscription: subscription, HTTPClient: server.Client(), Endpoint: server.URL})
+	if _, err := source.Receive(context.Background()); err == nil {
+		t.Error("Expected an error for a rejected pull")
+	}
+}
+
+func TestNew_RequiresSubscriptionAndClient(t *testing.T) {
+	if _, err := New(Config{HTTPClient: http.DefaultClient}); err == nil {
+		t.Error("Expected an error without a subscription")
+	}
+	if _, err := New(Config{Subscription: subscription}); err == nil {
+		t.Error("Expected an error without an HTTP client")
+	}
+}
@@ -0,0 +1,58 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const defaultRecordsPageSize = 100
+
+// errStopRecords stops EachRecord without reporting an error
+var errStopRecords = errors.New("stop records")
+
+// EachRecord calls fn for each record matching query, fetching pages with
+// QueryItems as they are needed. Pages have query.Pagination.PageSize
+// records (default 100), starting at query.Pagination.Page. It stops at the
+// first error from the API or fn, and returns it. ctx cancels the page
+// requests.
+func (c *Client) EachRecord(ctx context.Context, appID, collectionID uint, query *QueryOptions, fn func(record RecordFormat) error) error {
+	options := QueryOptions{}
+	if query != nil {
+		options = *query
+	}
+	pagination := PaginationOptions{}
+	if options.Pagination != nil {
+		pagination = *options.Pagination
+	}
+	if pagination.PageSize <= 0 {
+		pagination.PageSize = defaultRecordsPageSize
+	}
+	if pagination.Page <= 0 {
+		pagination.Page = 1
+	}
+
+	client := c.WithContext(ctx)
+	for page := pagination.Page; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		pageOptions := pagination
+		pageOptions.Page = page
+		options.Pagination = &pageOptions
+		records, err := client.QueryItems(appID, collectionID, &options).Records()
+		if err != nil {
+			return fmt.Errorf("failed to fetch page %d: %w", page, err)
+		}
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		if len(records) < pagination.PageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+//go:build go1.23
+
+package carthooks
+
+import (
+	"context"
+	"iter"
+)
+
+// RecordsSeq returns an iterator over the result's records. It yields
+// nothing if the result failed or holds no records; use Records to tell
+// these apart.
+func (r *Result) RecordsSeq() iter.Seq[RecordFormat] {
+	return func(yield func(RecordFormat) bool) {
+		records, err := r.Records()
+		if err != nil {
+			return
+		}
+		for _, record := range records {
+			if !yield(record) {
+				return
+			}
+		}
+	}
+}
+
+// Records returns an iterator over the records matching query, fetching
+// pages lazily as EachRecord does. A failed page request is yielded as the
+// final error:
+//
+//	for record, err := range client.Records(ctx, appID, collectionID, query) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Records(ctx context.Context, appID, collectionID uint, query *QueryOptions) iter.Seq2[RecordFormat, error] {
+	return func(yield func(RecordFormat, error) bool) {
+		err := c.EachRecord(ctx, appID, collectionID, query, func(record RecordFormat) error {
+			if !yield(record, nil) {
+				return errStopRecords
+			}
+			return nil
+		})
+		if err != nil && err != errStopRecords {
+			yield(RecordFormat{}, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go1.23
+
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestClient_Records(t *testing.T) {
+	var pages []int
+	server := newPagedServer(t, 5, &pages)
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var ids []uint
+	for record, err := range client.Records(context.Background(), 1, 2, &QueryOptions{Pagination: &PaginationOptions{PageSize: 2}}) {
+		if err != nil {
+			t.Fatalf("Records: %v", err)
+		}
+		ids = append(ids, record.ID)
+		if record.ID == 3 {
+			break
+		}
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" || fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("ids %v, pages %v; want lazy fetching up to page 2", ids, pages)
+	}
+
+	server.Close()
+	var errs int
+	for _, err := range client.Records(context.Background(), 1, 2, nil) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("expected one error for an unreachable API, got %d", errs)
+	}
+}
+
+func TestResult_RecordsSeq(t *testing.T) {
+	result := &Result{Success: true, Data: []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}}}
+	var ids []uint
+	for record := range result.RecordsSeq() {
+		ids = append(ids, record.ID)
+	}
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("RecordsSeq yielded %v", ids)
+	}
+	for range (&Result{Success: false, Error: "boom"}).RecordsSeq() {
+		t.Error("RecordsSeq yielded a record for a failed result")
+	}
+}
@@ -0,0 +1,55 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newPagedServer serves QueryItems pages of total records
+func newPagedServer(t *testing.T, total int, pages *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query QueryOptions
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Errorf("Failed to decode query: %v", err)
+		}
+		page, size := query.Pagination.Page, query.Pagination.PageSize
+		*pages = append(*pages, page)
+
+		records := []map[string]interface{}{}
+		for id := (page-1)*size + 1; id <= page*size && id <= total; id++ {
+			records = append(records, map[string]interface{}{"id": id})
+		}
+		data, _ := json.Marshal(records)
+		fmt.Fprintf(w, `{"data":%s}`, data)
+	}))
+}
+
+func TestClient_EachRecord(t *testing.T) {
+	var pages []int
+	server := newPagedServer(t, 5, &pages)
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var ids []uint
+	err := client.EachRecord(context.Background(), 1, 2, &QueryOptions{Pagination: &PaginationOptions{PageSize: 2}}, func(record RecordFormat) error {
+		ids = append(ids, record.ID)
+		return nil
+	})
+	if err != nil || fmt.Sprint(ids) != "[1 2 3 4 5]" || fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("EachRecord = %v, ids %v, pages %v", err, ids, pages)
+	}
+
+	pages = nil
+	stop := errors.New("stop")
+	err = client.EachRecord(context.Background(), 1, 2, &QueryOptions{Pagination: &PaginationOptions{PageSize: 2}}, func(record RecordFormat) error {
+		return stop
+	})
+	if err != stop || len(pages) != 1 {
+		t.Errorf("EachRecord = %v after pages %v, want stop after one page", err, pages)
+	}
+}
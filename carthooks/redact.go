@@ -0,0 +1,101 @@
+package carthooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces sensitive values in logs
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactKeys are the body, form and query keys whose values are
+// never logged. Matching ignores case.
+var DefaultRedactKeys = []string{
+	"access_token",
+	"refresh_token",
+	"user_access_token",
+	"id_token",
+	"client_secret",
+	"code",
+	"password",
+	"secret",
+	"token",
+	"api_key",
+	"authorization",
+}
+
+// redactor hides the values of sensitive keys before they are logged
+type redactor map[string]bool
+
+// newRedactor returns a redactor for DefaultRedactKeys plus extra
+func newRedactor(extra []string) redactor {
+	r := redactor{}
+	for _, key := range append(append([]string(nil), DefaultRedactKeys...), extra...) {
+		r[strings.ToLower(key)] = true
+	}
+	return r
+}
+
+func (r redactor) sensitive(key string) bool {
+	return r[strings.ToLower(key)]
+}
+
+// json returns data with the values of sensitive keys redacted at any
+// depth. Data that is not JSON is returned as is.
+func (r redactor) json(data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return string(data)
+	}
+	redacted, err := json.Marshal(r.value(v))
+	if err != nil {
+		return string(data)
+	}
+	return string(redacted)
+}
+
+func (r redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.sensitive(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = r.value(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.value(value)
+		}
+	}
+	return v
+}
+
+// values returns the encoded form with the values of sensitive keys
+// redacted
+func (r redactor) values(values url.Values) string {
+	redacted := url.Values{}
+	for key, vs := range values {
+		if r.sensitive(key) {
+			redacted[key] = []string{redactedValue}
+		} else {
+			redacted[key] = vs
+		}
+	}
+	return strings.ReplaceAll(redacted.Encode(), url.QueryEscape(redactedValue), redactedValue)
+}
+
+// url returns rawURL with the values of sensitive query parameters redacted
+func (r redactor) url(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	u.RawQuery = r.values(u.Query())
+	return u.String()
+}
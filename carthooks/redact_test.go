@@ -0,0 +1,33 @@
+package carthooks
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor([]string{"X-Internal-Key"})
+
+	got := r.json([]byte(`{"data":{"Access_Token":"abc","items":[{"password":"p","id":12345678901}],"x-internal-key":"k"},"title":"ok"}`))
+	for _, secret := range []string{"abc", `"p"`, `"k"`} {
+		if strings.Contains(got, secret) {
+			t.Errorf("json(...) = %s, leaks %s", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"id":12345678901`) || !strings.Contains(got, `"title":"ok"`) {
+		t.Errorf("json(...) = %s, lost non-sensitive values", got)
+	}
+	if got := r.json([]byte("not json")); got != "not json" {
+		t.Errorf("json(non-JSON) = %q", got)
+	}
+
+	form := url.Values{"grant_type": {"refresh_token"}, "client_secret": {"s3cret"}, "refresh_token": {"r"}}
+	if got, want := r.values(form), "client_secret=[REDACTED]&grant_type=refresh_token&refresh_token=[REDACTED]"; got != want {
+		t.Errorf("values(...) = %q, want %q", got, want)
+	}
+
+	if got, want := r.url("https://api.example.com/v1?token=t&page=2"), "https://api.example.com/v1?page=2&token=[REDACTED]"; got != want {
+		t.Errorf("url(...) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+// Package redisdedupe provides a Redis-backed carthooks.DedupeStore so
+// several watcher instances consuming the same queue share which messages
+// have been processed.
+package redisdedupe
+
+import (
+	"context"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config holds configuration for a Redis dedupe store
+type Config struct {
+	Client *redis.Client
+	// Prefix is prepended to message IDs to form keys (default
+	// "carthooks:dedupe:")
+	Prefix string
+	// TTL is how long a processed message is remembered (default 24h). It
+	// should exceed the source's maximum redelivery delay.
+	TTL time.Duration
+}
+
+// keyClient is the part of *redis.Client the store uses
+type keyClient interface {
+	Exists(ctx context.Context, keys ...string) *redis.IntCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+}
+
+// Store is a carthooks.DedupeStore backed by Redis keys with a TTL
+type Store struct {
+	config Config
+	client keyClient
+}
+
+var _ carthooks.DedupeStore = (*Store)(nil)
+
+// New creates a Redis dedupe store
+func New(config Config) *Store {
+	return newStore(config, config.Client)
+}
+
+func newStore(config Config, client keyClient) *Store {
+	if config.Prefix == "" {
+		config.Prefix = "carthooks:dedupe:"
+	}
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	return &Store{config: config, client: client}
+}
+
+// Seen reports whether messageID was processed within the TTL
+func (s *Store) Seen(ctx context.Context, messageID string) (bool, error) {
+	n, err := s.client.Exists(ctx, s.config.Prefix+messageID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// Mark records messageID as processed
+func (s *Store) Mark(ctx context.Context, messageID string) error {
+	return s.client.Set(ctx, s.config.Prefix+messageID, 1, s.config.TTL).Err()
+}
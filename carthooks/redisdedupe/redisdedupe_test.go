@@ -0,0 +1,109 @@
+package redisdedupe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeKeys is an in-memory keyspace with per-key expiry
+type fakeKeys struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	ttls    map[string]time.Duration
+	err     error
+}
+
+func newFakeKeys() *fakeKeys {
+	return &fakeKeys{expires: map[string]time.Time{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeKeys) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return redis.NewIntResult(0, f.err)
+	}
+	var n int64
+	for _, key := range keys {
+		if expires, ok := f.expires[key]; ok && time.Now().Before(expires) {
+			n++
+		}
+	}
+	return redis.NewIntResult(n, nil)
+}
+
+func (f *fakeKeys) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return redis.NewStatusResult("", f.err)
+	}
+	f.expires[key] = time.Now().Add(expiration)
+	f.ttls[key] = expiration
+	return redis.NewStatusResult("OK", nil)
+}
+
+func TestStore_SeenAndMark(t *testing.T) {
+	keys := newFakeKeys()
+	store := newStore(Config{Prefix: "test:"}, keys)
+	ctx := context.Background()
+
+	if seen, err := store.Seen(ctx, "m-1"); err != nil || seen {
+		t.Fatalf("Expected an unmarked message unseen, got %t (%v)", seen, err)
+	}
+	if err := store.Mark(ctx, "m-1"); err != nil {
+		t.Fatalf("Mark() failed: %v", err)
+	}
+	if seen, err := store.Seen(ctx, "m-1"); err != nil || !seen {
+		t.Errorf("Expected a marked message seen, got %t (%v)", seen, err)
+	}
+	if seen, _ := store.Seen(ctx, "m-2"); seen {
+		t.Error("Expected another message unseen")
+	}
+	if _, ok := keys.expires["test:m-1"]; !ok {
+		t.Errorf("Expected the key to use the prefix, got %v", keys.expires)
+	}
+}
+
+func TestStore_MarkExpiresAfterTTL(t *testing.T) {
+	keys := newFakeKeys()
+	store := newStore(Config{Prefix: "test:", TTL: 20 * time.Millisecond}, keys)
+	ctx := context.Background()
+
+	store.Mark(ctx, "m-1")
+	if keys.ttls["test:m-1"] != 20*time.Millisecond {
+		t.Fatalf("Expected the key set with the configured TTL, got %v", keys.ttls)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if seen, _ := store.Seen(ctx, "m-1"); seen {
+		t.Error("Expected the message forgotten once its TTL passed")
+	}
+}
+
+func TestStore_Defaults(t *testing.T) {
+	keys := newFakeKeys()
+	store := newStore(Config{}, keys)
+	store.Mark(context.Background(), "m-1")
+	if keys.ttls["carthooks:dedupe:m-1"] != 24*time.Hour {
+		t.Errorf("Expected the default prefix and TTL, got %v", keys.ttls)
+	}
+}
+
+func TestStore_Errors(t *testing.T) {
+	keys := newFakeKeys()
+	keys.err = errors.New("connection refused")
+	store := newStore(Config{}, keys)
+	ctx := context.Background()
+
+	if _, err := store.Seen(ctx, "m-1"); err == nil {
+		t.Error("Expected Seen to return the Redis error")
+	}
+	if err := store.Mark(ctx, "m-1"); err == nil {
+		t.Error("Expected Mark to return the Redis error")
+	}
+}
@@ -0,0 +1,155 @@
+// Package redissource provides a Redis Streams carthooks.MessageSource using
+// consumer groups (XREADGROUP/XACK).
+package redissource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config holds configuration for a Redis Streams source
+type Config struct {
+	Client *redis.Client
+	Stream string
+	Group  string
+	// Consumer names this watcher within the group; must be unique per
+	// running instance
+	Consumer string
+	// BodyField is the stream entry field holding the event envelope
+	// (default "body"); other fields become message attributes
+	BodyField string
+	// Count is the maximum number of entries per read (default 10)
+	Count int64
+	// Block is how long a read waits for new entries (default 20s)
+	Block time.Duration
+	// ClaimMinIdle is how long an entry must stay unacknowledged before it
+	// is reclaimed and redelivered (default 5 minutes)
+	ClaimMinIdle time.Duration
+}
+
+// streamClient is the part of *redis.Client the source uses
+type streamClient interface {
+	XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd
+	XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd
+	XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd
+	XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd
+}
+
+// Source is a carthooks.MessageSource backed by a Redis stream consumer
+// group. Nacked entries stay pending and are redelivered once they have been
+// idle for ClaimMinIdle.
+type Source struct {
+	config Config
+	client streamClient
+}
+
+// New creates a Redis Streams source, creating the stream and consumer group
+// if they don't exist
+func New(ctx context.Context, config Config) (*Source, error) {
+	if config.Client == nil || config.Stream == "" || config.Group == "" || config.Consumer == "" {
+		return nil, fmt.Errorf("redis source requires a client, stream, group and consumer")
+	}
+	if config.BodyField == "" {
+		config.BodyField = "body"
+	}
+	if config.Count <= 0 {
+		config.Count = 10
+	}
+	if config.Block <= 0 {
+		config.Block = 20 * time.Second
+	}
+	if config.ClaimMinIdle <= 0 {
+		config.ClaimMinIdle = 5 * time.Minute
+	}
+
+	return newSource(ctx, config, config.Client)
+}
+
+func newSource(ctx context.Context, config Config, client streamClient) (*Source, error) {
+	err := client.XGroupCreateMkStream(ctx, config.Stream, config.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create consumer group %s: %w", config.Group, err)
+	}
+
+	return &Source{config: config, client: client}, nil
+}
+
+// Receive reclaims idle pending entries, or otherwise reads new entries
+func (s *Source) Receive(ctx context.Context) ([]*carthooks.Message, error) {
+	claimed, _, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   s.config.Stream,
+		Group:    s.config.Group,
+		Consumer: s.config.Consumer,
+		MinIdle:  s.config.ClaimMinIdle,
+		Start:    "0-0",
+		Count:    s.config.Count,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending entries: %w", err)
+	}
+	if len(claimed) > 0 {
+		return s.messages(claimed), nil
+	}
+
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    s.config.Group,
+		Consumer: s.config.Consumer,
+		Streams:  []string{s.config.Stream, ">"},
+		Count:    s.config.Count,
+		Block:    s.config.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*carthooks.Message
+	for _, stream := range streams {
+		messages = append(messages, s.messages(stream.Messages)...)
+	}
+	return messages, nil
+}
+
+// Ack acknowledges the entry for the consumer group
+func (s *Source) Ack(ctx context.Context, message *carthooks.Message) error {
+	return s.client.XAck(ctx, s.config.Stream, s.config.Group, message.ID).Err()
+}
+
+// Nack leaves the entry pending so it is reclaimed after ClaimMinIdle
+func (s *Source) Nack(ctx context.Context, message *carthooks.Message) error {
+	return nil
+}
+
+// Close is a no-op; the Redis client is owned by the caller
+func (s *Source) Close() error {
+	return nil
+}
+
+// messages converts stream entries into Messages
+func (s *Source) messages(entries []redis.XMessage) []*carthooks.Message {
+	messages := make([]*carthooks.Message, 0, len(entries))
+	for _, entry := range entries {
+		message := &carthooks.Message{
+			ID:         entry.ID,
+			Attributes: make(map[string]string, len(entry.Values)),
+			Raw:        entry,
+		}
+		for k, v := range entry.Values {
+			if k == s.config.BodyField {
+				message.Body = []byte(fmt.Sprint(v))
+			} else {
+				message.Attributes[k] = fmt.Sprint(v)
+			}
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
@@ -0,0 +1,168 @@
+package redissource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeStream is a consumer group over an in-memory stream. Read entries
+// stay pending until acked and can be claimed once idle for MinIdle.
+type fakeStream struct {
+	mu        sync.Mutex
+	groupErr  error
+	unread    []redis.XMessage
+	pending   map[string]pendingEntry
+	order     []string
+	acked     []string
+	readCount int
+}
+
+type pendingEntry struct {
+	entry     redis.XMessage
+	deliverAt time.Time
+}
+
+func newFakeStream(entries ...redis.XMessage) *fakeStream {
+	return &fakeStream{unread: entries, pending: map[string]pendingEntry{}}
+}
+
+func (f *fakeStream) XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd {
+	return redis.NewStatusResult("OK", f.groupErr)
+}
+
+func (f *fakeStream) XAutoClaim(ctx context.Context, a *redis.XAutoClaimArgs) *redis.XAutoClaimCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var claimed []redis.XMessage
+	for _, id := range f.order {
+		p, ok := f.pending[id]
+		if ok && time.Since(p.deliverAt) >= a.MinIdle && int64(len(claimed)) < a.Count {
+			claimed = append(claimed, p.entry)
+			f.pending[id] = pendingEntry{entry: p.entry, deliverAt: time.Now()}
+		}
+	}
+	cmd := redis.NewXAutoClaimCmd(ctx)
+	cmd.SetVal(claimed, "0-0")
+	return cmd
+}
+
+func (f *fakeStream) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readCount++
+
+	if len(f.unread) == 0 {
+		return redis.NewXStreamSliceCmdResult(nil, redis.Nil)
+	}
+	n := int(a.Count)
+	if n > len(f.unread) {
+		n = len(f.unread)
+	}
+	read := f.unread[:n]
+	f.unread = f.unread[n:]
+	for _, entry := range read {
+		f.pending[entry.ID] = pendingEntry{entry: entry, deliverAt: time.Now()}
+		f.order = append(f.order, entry.ID)
+	}
+	return redis.NewXStreamSliceCmdResult([]redis.XStream{{Stream: a.Streams[0], Messages: read}}, nil)
+}
+
+func (f *fakeStream) XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range ids {
+		delete(f.pending, id)
+		f.acked = append(f.acked, id)
+	}
+	return redis.NewIntResult(int64(len(ids)), nil)
+}
+
+func newTestSource(t *testing.T, stream *fakeStream, minIdle time.Duration) *Source {
+	source, err := newSource(context.Background(), Config{
+		Stream:       "events",
+		Group:        "watchers",
+		Consumer:     "w-1",
+		BodyField:    "body",
+		Count:        10,
+		ClaimMinIdle: minIdle,
+	}, stream)
+	if err != nil {
+		t.Fatalf("newSource() failed: %v", err)
+	}
+	return source
+}
+
+func TestSource_Receive(t *testing.T) {
+	stream := newFakeStream(redis.XMessage{ID: "1-0", Values: map[string]interface{}{"body": `{"version":"1"}`, "tenant": "3"}})
+	source := newTestSource(t, stream, time.Hour)
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != "1-0" || string(messages[0].Body) != `{"version":"1"}` || messages[0].Attributes["tenant"] != "3" {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+
+	// No new entries and nothing idle long enough to reclaim
+	messages, err = source.Receive(context.Background())
+	if err != nil || len(messages) != 0 {
+		t.Errorf("Expected no messages, got %d, %v", len(messages), err)
+	}
+}
+
+func TestSource_AckAndNackRedelivers(t *testing.T) {
+	stream := newFakeStream(
+		redis.XMessage{ID: "1-0", Values: map[string]interface{}{"body": "{}"}},
+		redis.XMessage{ID: "2-0", Values: map[string]interface{}{"body": "{}"}},
+	)
+	source := newTestSource(t, stream, 10*time.Millisecond)
+	ctx := context.Background()
+
+	messages, err := source.Receive(ctx)
+	if err != nil || len(messages) != 2 {
+		t.Fatalf("Receive() = %d messages, %v", len(messages), err)
+	}
+	if err := source.Ack(ctx, messages[0]); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	if err := source.Nack(ctx, messages[1]); err != nil {
+		t.Fatalf("Nack() failed: %v", err)
+	}
+	if len(stream.acked) != 1 || stream.acked[0] != "1-0" {
+		t.Errorf("Unexpected acks %v", stream.acked)
+	}
+
+	// The nacked entry stays pending and is reclaimed once idle
+	time.Sleep(20 * time.Millisecond)
+	messages, err = source.Receive(ctx)
+	if err != nil || len(messages) != 1 || messages[0].ID != "2-0" {
+		t.Fatalf("Expected the nacked entry redelivered, got %+v, %v", messages, err)
+	}
+	if stream.readCount != 1 {
+		t.Errorf("Expected reclaimed entries to be returned without reading new ones, got %d reads", stream.readCount)
+	}
+}
+
+func TestNewSource_ToleratesExistingGroup(t *testing.T) {
+	stream := newFakeStream()
+	stream.groupErr = errors.New("BUSYGROUP Consumer Group name already exists")
+	newTestSource(t, stream, time.Minute)
+
+	stream.groupErr = errors.New("NOPERM")
+	if _, err := newSource(context.Background(), Config{Stream: "events", Group: "watchers"}, stream); err == nil {
+		t.Error("Expected an error when the group cannot be created")
+	}
+}
+
+func TestNew_RequiresConfig(t *testing.T) {
+	if _, err := New(context.Background(), Config{Stream: "events", Group: "watchers", Consumer: "w-1"}); err == nil {
+		t.Error("Expected an error without a client")
+	}
+}
@@ -0,0 +1,109 @@
+package carthooks
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMissingTimestamp is returned for events without meta.timestamp
+	ErrMissingTimestamp = errors.New("event timestamp missing")
+	// ErrEventTooOld is returned for events older than the guard's MaxAge
+	ErrEventTooOld = errors.New("event too old")
+	// ErrEventFromFuture is returned for events timestamped further ahead
+	// than the allowed clock skew
+	ErrEventFromFuture = errors.New("event timestamp in the future")
+	// ErrEventReplayed is returned for a nonce that was already seen
+	ErrEventReplayed = errors.New("event replayed")
+)
+
+const (
+	defaultReplayMaxAge  = 5 * time.Minute
+	defaultReplayMaxSkew = time.Minute
+)
+
+// ReplayGuard rejects stale and replayed events by checking
+// meta.timestamp against a maximum age and meta.nonce against the nonces
+// seen within that window. Use it with signed deliveries, so the
+// timestamp and nonce cannot be altered.
+type ReplayGuard struct {
+	// MaxAge is how old an event may be (default 5 minutes)
+	MaxAge time.Duration
+	// MaxSkew is how far in the future a timestamp may be, to allow for
+	// clock differences (default 1 minute)
+	MaxSkew time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+	now  func() time.Time
+}
+
+// NewReplayGuard creates a guard rejecting events older than maxAge
+func NewReplayGuard(maxAge time.Duration) *ReplayGuard {
+	return &ReplayGuard{MaxAge: maxAge}
+}
+
+// Check returns nil if event is fresh and its nonce has not been seen
+// before, and records the nonce. Events without a nonce are only checked
+// for age. If the event then fails to be processed, call Forget so a
+// redelivery is accepted.
+func (g *ReplayGuard) Check(event *EventMessage) error {
+	maxAge := g.MaxAge
+	if maxAge <= 0 {
+		maxAge = defaultReplayMaxAge
+	}
+	maxSkew := g.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultReplayMaxSkew
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if g.now != nil {
+		now = g.now()
+	}
+
+	if event.Meta.Timestamp == 0 {
+		return ErrMissingTimestamp
+	}
+	sent := time.Unix(event.Meta.Timestamp, 0)
+	if now.Sub(sent) > maxAge {
+		return ErrEventTooOld
+	}
+	if sent.Sub(now) > maxSkew {
+		return ErrEventFromFuture
+	}
+
+	if event.Meta.Nonce == "" {
+		return nil
+	}
+
+	// Forget nonces of events that would now be rejected as too old anyway
+	for nonce, expires := range g.seen {
+		if now.After(expires) {
+			delete(g.seen, nonce)
+		}
+	}
+	if _, ok := g.seen[event.Meta.Nonce]; ok {
+		return ErrEventReplayed
+	}
+	if g.seen == nil {
+		g.seen = map[string]time.Time{}
+	}
+	g.seen[event.Meta.Nonce] = sent.Add(maxAge)
+	return nil
+}
+
+// Forget removes the nonce Check recorded for event, so a redelivery of an
+// event whose processing failed is not rejected as a replay
+func (g *ReplayGuard) Forget(event *EventMessage) {
+	if event.Meta.Nonce == "" {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.seen, event.Meta.Nonce)
+}
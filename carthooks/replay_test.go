@@ -0,0 +1,102 @@
+package carthooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReplayGuard(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	guard := NewReplayGuard(5 * time.Minute)
+	guard.now = func() time.Time { return now }
+
+	event := func(sent time.Time, nonce string) *EventMessage {
+		return &EventMessage{Meta: EventMessageMeta{Timestamp: sent.Unix(), Nonce: nonce}}
+	}
+
+	tests := []struct {
+		name  string
+		event *EventMessage
+		want  error
+	}{
+		{"fresh", event(now.Add(-time.Minute), "a"), nil},
+		{"replayed nonce", event(now.Add(-time.Minute), "a"), ErrEventReplayed},
+		{"new nonce", event(now, "b"), nil},
+		{"too old", event(now.Add(-6*time.Minute), "c"), ErrEventTooOld},
+		{"within skew", event(now.Add(30*time.Second), "d"), nil},
+		{"from the future", event(now.Add(2*time.Minute), "e"), ErrEventFromFuture},
+		{"no timestamp", &EventMessage{}, ErrMissingTimestamp},
+	}
+	for _, tt := range tests {
+		if err := guard.Check(tt.event); err != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Nonces are forgotten once their events would be too old anyway
+	now = now.Add(10 * time.Minute)
+	if len(guard.seen) != 3 {
+		t.Fatalf("Expected 3 recorded nonces, got %d", len(guard.seen))
+	}
+	guard.Check(event(now, "f"))
+	if len(guard.seen) != 1 {
+		t.Errorf("Expected expired nonces to be pruned, got %v", guard.seen)
+	}
+}
+
+func TestWebhookHandler_RejectsReplays(t *testing.T) {
+	handler := NewWebhookHandler("secret", nil)
+	handler.Replay = NewReplayGuard(5 * time.Minute)
+
+	body := []byte(fmt.Sprintf(`{"meta":{"event":"collection.item.created","timestamp":%d,"nonce":"n1"}}`, time.Now().Unix()))
+	send := func() int {
+		req := httptest.NewRequest("POST", "/events", bytes.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send(); code != http.StatusAccepted {
+		t.Fatalf("Expected first delivery accepted, got %d", code)
+	}
+	if code := send(); code != http.StatusUnauthorized {
+		t.Errorf("Expected replay rejected with 401, got %d", code)
+	}
+}
+
+func TestWebhookHandler_AcceptsRedeliveryAfterHandlerFailure(t *testing.T) {
+	calls := 0
+	handler := NewWebhookHandler("secret", EventHandlerFunc(func(event *EventMessage) error {
+		calls++
+		if calls == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}))
+	handler.Replay = NewReplayGuard(5 * time.Minute)
+
+	body := []byte(fmt.Sprintf(`{"meta":{"event":"collection.item.created","timestamp":%d,"nonce":"n1"}}`, time.Now().Unix()))
+	send := func() int {
+		req := httptest.NewRequest("POST", "/events", bytes.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send(); code != http.StatusInternalServerError {
+		t.Fatalf("Expected the failed delivery to get 500, got %d", code)
+	}
+	if code := send(); code != http.StatusNoContent {
+		t.Fatalf("Expected the redelivery to be handled, got %d", code)
+	}
+	if code := send(); code != http.StatusUnauthorized {
+		t.Errorf("Expected a replay of the handled event rejected with 401, got %d", code)
+	}
+}
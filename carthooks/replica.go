@@ -0,0 +1,207 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReplicaRecord is a record as kept in a local replica. Deleted records are
+// kept as tombstones so late redeliveries of older events cannot bring them
+// back.
+type ReplicaRecord struct {
+	CollectionID uint
+	RecordFormat
+	Deleted bool
+}
+
+// ReplicaStore persists the records of a local replica, e.g. a
+// sqlreplica.Store. Implementations must be safe for concurrent use.
+type ReplicaStore interface {
+	// Get returns the stored record, including tombstones, or nil if there
+	// is none
+	Get(ctx context.Context, collectionID, itemID uint) (*ReplicaRecord, error)
+	// Put inserts a record, or replaces the stored one unless that has a
+	// newer UpdatedAt or is a tombstone with the same UpdatedAt. The
+	// comparison and write must be atomic, since Sync may apply events for
+	// the same record concurrently.
+	Put(ctx context.Context, record *ReplicaRecord) error
+}
+
+// MemoryReplicaStore is an in-process ReplicaStore, mainly for tests and
+// small collections
+type MemoryReplicaStore struct {
+	mu      sync.RWMutex
+	records map[[2]uint]*ReplicaRecord
+}
+
+// NewMemoryReplicaStore creates an empty in-memory replica store
+func NewMemoryReplicaStore() *MemoryReplicaStore {
+	return &MemoryReplicaStore{records: map[[2]uint]*ReplicaRecord{}}
+}
+
+// Get implements ReplicaStore
+func (s *MemoryReplicaStore) Get(ctx context.Context, collectionID, itemID uint) (*ReplicaRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.records[[2]uint{collectionID, itemID}]
+	if !ok {
+		return nil, nil
+	}
+	cp := *record
+	return &cp, nil
+}
+
+// Put implements ReplicaStore
+func (s *MemoryReplicaStore) Put(ctx context.Context, record *ReplicaRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := [2]uint{record.CollectionID, record.ID}
+	if existing, ok := s.records[key]; ok && supersedes(existing, record) {
+		return nil
+	}
+	cp := *record
+	s.records[key] = &cp
+	return nil
+}
+
+// supersedes reports whether a stored record must be kept over an incoming
+// one. Timestamps have one-second precision, so on a tie a tombstone wins:
+// an update from the same second as a delete must not bring the record back.
+func supersedes(existing, record *ReplicaRecord) bool {
+	return existing.UpdatedAt > record.UpdatedAt ||
+		(existing.UpdatedAt == record.UpdatedAt && existing.Deleted)
+}
+
+// Records returns the collection's records that are not deleted, ordered by
+// ID
+func (s *MemoryReplicaStore) Records(collectionID uint) []RecordFormat {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var records []RecordFormat
+	for key, record := range s.records {
+		if key[0] == collectionID && !record.Deleted {
+			records = append(records, record.RecordFormat)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+// Sync maintains a local replica of a collection in a ReplicaStore. It runs
+// a watcher that backfills every record on first start and then applies
+// live changes. With Checkpoints configured, a restarted Sync resumes from
+// its last checkpoint instead of backfilling again.
+//
+// An event older than the stored record, by updated_at, is ignored, so
+// redelivered or reordered events never overwrite newer data.
+type Sync struct {
+	watcher *Watcher
+	store   ReplicaStore
+	next    Handler
+}
+
+// NewSync creates a Sync that keeps store up to date with the collection
+// watched per config. The config's Handler, if set, is called after each
+// event has been applied; BatchHandler is not supported.
+func NewSync(store ReplicaStore, config *WatcherConfig) (*Sync, error) {
+	if store == nil {
+		return nil, errors.New("replica store is required")
+	}
+	if config.BatchHandler != nil {
+		return nil, errors.New("sync does not support BatchHandler")
+	}
+
+	s := &Sync{store: store, next: chainHandler(config)}
+
+	watcherConfig := *config
+	watcherConfig.Handler = s.apply
+	watcherConfig.Middleware = nil
+	watcherConfig.Backfill = true
+
+	watcher, err := NewWatcher(&watcherConfig)
+	if err != nil {
+		return nil, err
+	}
+	s.watcher = watcher
+	return s, nil
+}
+
+// Run backfills, if needed, and applies changes until ctx is cancelled or
+// Stop is called; see Watcher.Run
+func (s *Sync) Run(ctx context.Context) error {
+	return s.watcher.Run(ctx)
+}
+
+// Stop signals a running Sync to shut down
+func (s *Sync) Stop() {
+	s.watcher.Stop()
+}
+
+// Watcher returns the underlying watcher
+func (s *Sync) Watcher() *Watcher {
+	return s.watcher
+}
+
+// apply writes a record event to the store, unless the store already holds
+// a newer version of the record. The Get only skips stale events early;
+// Put's own check decides when events for a record race.
+func (s *Sync) apply(ctx context.Context, event *EventMessage) error {
+	record, err := s.replicaRecord(event)
+	if err != nil {
+		return err
+	}
+
+	if record != nil {
+		existing, err := s.store.Get(ctx, record.CollectionID, record.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read replica record %d: %w", record.ID, err)
+		}
+		if existing != nil && supersedes(existing, record) {
+			s.watcher.logger.Debug("skipping stale replica event", "item_id", record.ID, "event", event.Meta.Event)
+		} else if err := s.store.Put(ctx, record); err != nil {
+			return fmt.Errorf("failed to write replica record %d: %w", record.ID, err)
+		}
+	}
+
+	if s.next != nil {
+		return s.next(ctx, event)
+	}
+	return nil
+}
+
+// replicaRecord returns the record an event carries, or nil for events that
+// do not change records
+func (s *Sync) replicaRecord(event *EventMessage) (*ReplicaRecord, error) {
+	collectionID := event.Meta.CollectionID
+	if collectionID == 0 {
+		collectionID = s.watcher.config.CollectionID
+	}
+
+	switch event.Meta.Event {
+	case EventCodeRecordCreated, EventCodeRecordUpdated, EventCodeFieldChanged:
+		record := &ReplicaRecord{CollectionID: collectionID}
+		if err := event.DecodePayload(&record.RecordFormat); err != nil {
+			return nil, err
+		}
+		return record, nil
+	case EventCodeRecordDeleted:
+		var payload RecordDeletedPayload
+		if err := event.DecodePayload(&payload); err != nil {
+			return nil, err
+		}
+		deletedAt := payload.DeletedAt
+		if deletedAt == 0 {
+			deletedAt = time.Now().Unix()
+		}
+		return &ReplicaRecord{
+			CollectionID: collectionID,
+			RecordFormat: RecordFormat{ID: payload.ID, Title: payload.Title, UpdatedAt: deletedAt},
+			Deleted:      true,
+		}, nil
+	}
+	return nil, nil
+}
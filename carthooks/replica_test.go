@@ -0,0 +1,182 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func replicaMessage(id, event string, payload string) *Message {
+	return &Message{
+		ID:   id,
+		Body: []byte(fmt.Sprintf(`{"version":"1","meta":{"collection_id":2,"event":%q},"payload":%s}`, event, payload)),
+	}
+}
+
+func TestSync_AppliesEventsInUpdatedOrder(t *testing.T) {
+	store := NewMemoryReplicaStore()
+	var handled int
+	replica, err := NewSync(store, &WatcherConfig{
+		Source:       &recordingSource{},
+		CollectionID: 2,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			handled++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSync() returned %v", err)
+	}
+
+	ctx := context.Background()
+	replica.watcher.processBatch(ctx, []*Message{
+		replicaMessage("1", "collection.item.created", `{"id":1,"title":"a","updated_at":10}`),
+		replicaMessage("2", "collection.item.updated", `{"id":1,"title":"b","updated_at":20}`),
+		replicaMessage("3", "collection.item.updated", `{"id":1,"title":"stale","updated_at":15}`),
+		replicaMessage("4", "collection.item.created", `{"id":2,"title":"c","updated_at":10}`),
+	})
+
+	records := store.Records(2)
+	if len(records) != 2 || records[0].Title != "b" || records[1].Title != "c" {
+		t.Fatalf("Unexpected replica %+v", records)
+	}
+	if handled != 4 {
+		t.Errorf("Expected handler to see 4 events, got %d", handled)
+	}
+
+	// A late update must not resurrect a deleted record
+	replica.watcher.processBatch(ctx, []*Message{
+		replicaMessage("5", "collection.item.deleted", `{"id":2,"deleted_at":30}`),
+		replicaMessage("6", "collection.item.updated", `{"id":2,"title":"late","updated_at":25}`),
+	})
+
+	records = store.Records(2)
+	if len(records) != 1 || records[0].ID != 1 {
+		t.Errorf("Expected only record 1 to remain, got %+v", records)
+	}
+	tombstone, _ := store.Get(ctx, 2, 2)
+	if tombstone == nil || !tombstone.Deleted {
+		t.Errorf("Expected a tombstone for record 2, got %+v", tombstone)
+	}
+
+	// Nor may an update from the same second as the delete
+	replica.watcher.processBatch(ctx, []*Message{
+		replicaMessage("7", "collection.item.deleted", `{"id":1,"deleted_at":40}`),
+		replicaMessage("8", "collection.item.updated", `{"id":1,"title":"same second","updated_at":40}`),
+	})
+	if records := store.Records(2); len(records) != 0 {
+		t.Errorf("Expected the tombstone to win a tie, got %+v", records)
+	}
+}
+
+func TestSync_BackfillsOnFirstRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":[{"id":1,"title":"a"},{"id":2,"title":"b"}]}`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewMemoryReplicaStore()
+	replica, _ := NewSync(store, &WatcherConfig{
+		Client:       NewClient(&ClientConfig{BaseURL: server.URL}),
+		AppID:        1,
+		CollectionID: 2,
+		Source:       &onceSource{batch: []*Message{replicaMessage("live", "collection.item.updated", `{"id":3,"title":"c"}`)}},
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			if event.Meta.TriggerType != TriggerTypeBackfill {
+				cancel()
+			}
+			return nil
+		},
+	})
+
+	if err := replica.Run(ctx); err != nil {
+		t.Fatalf("Run() returned %v", err)
+	}
+	if records := store.Records(2); len(records) != 3 {
+		t.Errorf("Expected backfilled and live records, got %+v", records)
+	}
+}
+
+// interleavingStore makes concurrent applies read before either writes, and
+// writes the older record last
+type interleavingStore struct {
+	*MemoryReplicaStore
+	read  sync.WaitGroup
+	newer chan struct{}
+}
+
+func (s *interleavingStore) Get(ctx context.Context, collectionID, itemID uint) (*ReplicaRecord, error) {
+	record, err := s.MemoryReplicaStore.Get(ctx, collectionID, itemID)
+	s.read.Done()
+	s.read.Wait()
+	return record, err
+}
+
+func (s *interleavingStore) Put(ctx context.Context, record *ReplicaRecord) error {
+	if record.Title == "older" {
+		<-s.newer
+	}
+	err := s.MemoryReplicaStore.Put(ctx, record)
+	if record.Title == "newer" {
+		close(s.newer)
+	}
+	return err
+}
+
+func TestSync_ConcurrentEventsKeepNewest(t *testing.T) {
+	store := &interleavingStore{MemoryReplicaStore: NewMemoryReplicaStore(), newer: make(chan struct{})}
+	store.read.Add(2)
+	replica, err := NewSync(store, &WatcherConfig{Source: &recordingSource{}, CollectionID: 2})
+	if err != nil {
+		t.Fatalf("NewSync() returned %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, payload := range []string{
+		`{"id":1,"title":"newer","updated_at":20}`,
+		`{"id":1,"title":"older","updated_at":10}`,
+	} {
+		var event EventMessage
+		json.Unmarshal(replicaMessage("", "collection.item.updated", payload).Body, &event)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := replica.apply(context.Background(), &event); err != nil {
+				t.Errorf("apply() returned %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if records := store.Records(2); len(records) != 1 || records[0].Title != "newer" {
+		t.Errorf("Expected the newer event to win, got %+v", records)
+	}
+}
+
+func TestMemoryReplicaStore_TombstoneWinsTies(t *testing.T) {
+	store := NewMemoryReplicaStore()
+	ctx := context.Background()
+
+	tombstone := &ReplicaRecord{CollectionID: 2, RecordFormat: RecordFormat{ID: 1, UpdatedAt: 10}, Deleted: true}
+	update := &ReplicaRecord{CollectionID: 2, RecordFormat: RecordFormat{ID: 1, Title: "late", UpdatedAt: 10}}
+	store.Put(ctx, tombstone)
+	store.Put(ctx, update)
+	if record, _ := store.Get(ctx, 2, 1); !record.Deleted {
+		t.Errorf("Expected the tombstone kept on a tie, got %+v", record)
+	}
+
+	// A delete from the same second as an update still applies
+	store = NewMemoryReplicaStore()
+	store.Put(ctx, update)
+	store.Put(ctx, tombstone)
+	if record, _ := store.Get(ctx, 2, 1); !record.Deleted {
+		t.Errorf("Expected the tombstone to replace an update on a tie, got %+v", record)
+	}
+}
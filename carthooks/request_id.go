@@ -0,0 +1,50 @@
+package carthooks
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestIDHeader carries the client-generated ID of each API request, so
+// client logs can be correlated with server logs
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying id, which is sent as the
+// X-Request-ID of requests made with the context instead of a generated ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// setRequestID sets the X-Request-ID header of req, unless it is already
+// set, taking the ID from the request's context or generating a new one.
+// It returns the request's ID.
+func setRequestID(req *http.Request) string {
+	if id := req.Header.Get(RequestIDHeader); id != "" {
+		return id
+	}
+	id := RequestIDFromContext(req.Context())
+	if id == "" {
+		id, _ = UUIDv7Generator{}.NewID()
+	}
+	if id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+	return id
+}
+
+// responseRequestID returns the X-Request-ID sent with the request resp
+// answers, which middleware may have changed after it was set
+func responseRequestID(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	return resp.Request.Header.Get(RequestIDHeader)
+}
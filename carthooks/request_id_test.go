@@ -0,0 +1,47 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_RequestID(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(RequestIDHeader))
+		fmt.Fprint(w, `{"data":{},"trace_id":"trace-1"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	first := client.GetApps()
+	second := client.GetApps()
+
+	if first.RequestID == "" || first.RequestID != sent[0] {
+		t.Errorf("RequestID = %q, sent %q", first.RequestID, sent[0])
+	}
+	if second.RequestID == first.RequestID {
+		t.Errorf("expected a new request ID per request, got %q twice", first.RequestID)
+	}
+
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(RequestIDHeader, "from-middleware")
+			return next(req)
+		}
+	})
+	if result := client.GetApps(); result.GetRequestID() != "from-middleware" || sent[2] != "from-middleware" {
+		t.Errorf("RequestID = %q, sent %q, want from-middleware", result.GetRequestID(), sent[2])
+	}
+}
+
+func TestSetRequestID_FromContext(t *testing.T) {
+	req := httptest.NewRequest("GET", "/v1/apps", nil)
+	req = req.WithContext(WithRequestID(req.Context(), "req-42"))
+
+	if id := setRequestID(req); id != "req-42" || req.Header.Get(RequestIDHeader) != "req-42" {
+		t.Errorf("setRequestID() = %q, header %q, want req-42", id, req.Header.Get(RequestIDHeader))
+	}
+}
@@ -0,0 +1,44 @@
+package carthooks
+
+import "net/http"
+
+// RoundTripFunc sends an API request and returns its response
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RequestMiddleware wraps how the client sends API requests, e.g. to add
+// headers, sign or log requests. It can modify the request before calling
+// next, inspect the response afterwards, or answer without calling next.
+type RequestMiddleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds request middleware to the client. Middleware added first runs
+// outermost. Add middleware before making requests; Use is not safe to call
+// concurrently with requests.
+func (c *Client) Use(middleware ...RequestMiddleware) *Client {
+	c.middleware = append(c.middleware, middleware...)
+	return c
+}
+
+// do sends req through the client's middleware, reporting it to the
+// metrics hook if one is set
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	send := RoundTripFunc(c.httpClient.Do)
+	if c.limiter != nil {
+		send = c.limiter.wrap(send)
+	}
+	if c.failover != nil {
+		send = c.failover.wrap(send)
+	}
+	if c.hedger != nil {
+		send = c.hedger.wrap(send)
+	}
+	if c.breaker != nil {
+		send = c.breaker.wrap(send)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		send = c.middleware[i](send)
+	}
+	if c.metrics != nil {
+		send = instrument(c.metrics, send)
+	}
+	return send(req)
+}
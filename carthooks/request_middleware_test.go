@@ -0,0 +1,68 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Use(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("X-Tenant")+"/"+r.Header.Get("X-Order"))
+		fmt.Fprint(w, `{"data":{"access_token":"t"}}`)
+	}))
+	defer server.Close()
+
+	var calls []string
+	tag := func(name string) RequestMiddleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+">")
+				req.Header.Set("X-Order", req.Header.Get("X-Order")+name)
+				resp, err := next(req)
+				calls = append(calls, "<"+name)
+				return resp, err
+			}
+		}
+	}
+
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		OAuth:   &OAuthConfig{ClientID: "id", ClientSecret: "secret"},
+		Middleware: []RequestMiddleware{func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Tenant", "acme")
+				return next(req)
+			}
+		}},
+	})
+	client.Use(tag("a"), tag("b"))
+
+	client.GetItemByID(1, 2, 3, nil)
+	client.InitializeOAuth()
+
+	if fmt.Sprint(headers) != "[acme/ab acme/ab]" {
+		t.Errorf("Expected middleware on JSON and form requests, got %v", headers)
+	}
+	if fmt.Sprint(calls) != "[a> b> <b <a a> b> <b <a]" {
+		t.Errorf("Unexpected middleware order %v", calls)
+	}
+}
+
+func TestClient_UseShortCircuit(t *testing.T) {
+	client := NewClient(&ClientConfig{BaseURL: "http://127.0.0.1:0"})
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			rec := httptest.NewRecorder()
+			fmt.Fprint(rec, `{"data":{"id":7}}`)
+			return rec.Result(), nil
+		}
+	})
+
+	result := client.GetItemByID(1, 2, 7, nil)
+	if !result.Success || result.Data.(map[string]interface{})["id"] != float64(7) {
+		t.Errorf("Expected the middleware's response, got %+v", result)
+	}
+}
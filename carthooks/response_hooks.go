@@ -0,0 +1,136 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxHookRetries = 3
+	defaultMaxRetryAfter  = time.Minute
+	hookRetryBaseDelay    = 200 * time.Millisecond
+)
+
+// HookResponse is the API response passed to a ResponseHook
+type HookResponse struct {
+	Request    *http.Request
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Attempt is 1 for the first response to a call and counts up on
+	// retries
+	Attempt int
+	// RetryAfter is how long to wait before a retry the hook requests;
+	// defaults to exponential backoff from 200ms and is capped by
+	// ClientConfig.MaxRetryAfter
+	RetryAfter time.Duration
+}
+
+// ResponseHook inspects an API response and the Result parsed from it. It
+// may modify result, e.g. to map error codes to an organization's policy,
+// and returns true to send the request again. Retries are capped by
+// ClientConfig.MaxHookRetries.
+type ResponseHook func(resp *HookResponse, result *Result) (retry bool)
+
+// OnResponse adds response hooks to the client, run in the order added. Add
+// hooks before making requests; OnResponse is not safe to call concurrently
+// with requests.
+func (c *Client) OnResponse(hooks ...ResponseHook) *Client {
+	c.responseHooks = append(c.responseHooks, hooks...)
+	return c
+}
+
+// hookAttemptKey carries the number of the previous attempt in a retried
+// request's context
+type hookAttemptKey struct{}
+
+// applyResponseHooks runs the response hooks and, if one asks for it,
+// resends the request and returns the Result of the retry instead
+func (c *Client) applyResponseHooks(resp *http.Response, body []byte, result *Result) *Result {
+	attempt := 1
+	if resp.Request != nil {
+		if previous, ok := resp.Request.Context().Value(hookAttemptKey{}).(int); ok {
+			attempt = previous + 1
+		}
+	}
+
+	info := &HookResponse{
+		Request:    resp.Request,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Attempt:    attempt,
+	}
+	retry := false
+	for _, hook := range c.responseHooks {
+		if hook(info, result) {
+			retry = true
+		}
+	}
+
+	maxRetries := c.maxHookRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxHookRetries
+	}
+	if !retry || attempt > maxRetries {
+		return result
+	}
+
+	req, ok := retryRequest(c.context(), resp.Request, attempt)
+	if !ok {
+		return result
+	}
+
+	delay := info.RetryAfter
+	if delay <= 0 {
+		delay = hookRetryBaseDelay << (attempt - 1)
+	}
+	maxDelay := c.maxRetryAfter
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryAfter
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	c.logger.Debug("retrying request", "method", req.Method, "url", c.redact.url(req.URL.String()), "delay", delay, "attempt", attempt+1)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return requestFailed(fmt.Errorf("request failed: %w", req.Context().Err()))
+	case <-timer.C:
+	}
+
+	retried, err := c.do(req)
+	if err != nil {
+		return requestFailed(fmt.Errorf("request failed: %w", err))
+	}
+	return c.parseResponse(retried)
+}
+
+// retryRequest copies req for another attempt on ctx, the caller's
+// context, rewinding its body. It reports false if the body cannot be
+// replayed. req's own context is not reused: http.Client cancels it once
+// the response body is closed when a timeout is set, and hedged or failed
+// over attempts cancel theirs when another attempt wins.
+func retryRequest(ctx context.Context, req *http.Request, attempt int) (*http.Request, bool) {
+	if req == nil {
+		return nil, false
+	}
+
+	ctx = context.WithValue(ctx, hookAttemptKey{}, attempt)
+	retry := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		retry.Body = body
+	}
+	return retry, true
+}
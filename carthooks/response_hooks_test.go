@@ -0,0 +1,128 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_OnResponseRetries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.Header().Set("X-Busy", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"message":"busy","code":"BUSY"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":1}}`)
+	}))
+	defer server.Close()
+
+	var attempts []int
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	client.OnResponse(func(resp *HookResponse, result *Result) bool {
+		attempts = append(attempts, resp.Attempt)
+		resp.RetryAfter = time.Millisecond
+		return resp.Header.Get("X-Busy") != ""
+	})
+
+	result := client.CreateItem(1, 2, map[string]interface{}{"title": "Widget"})
+	if !result.Success {
+		t.Fatalf("Expected success after retries, got %+v", result)
+	}
+	if fmt.Sprint(attempts) != "[1 2 3]" {
+		t.Errorf("Unexpected attempts %v", attempts)
+	}
+	if len(bodies) != 3 || bodies[2] != bodies[0] {
+		t.Errorf("Expected the request body resent on each retry, got %v", bodies)
+	}
+}
+
+func TestClient_OnResponseMutatesAndCapsRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"message":"slow down","code":"RATE_LIMITED"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:        server.URL,
+		MaxHookRetries: 2,
+		ResponseHooks: []ResponseHook{func(resp *HookResponse, result *Result) bool {
+			resp.RetryAfter = time.Millisecond
+			result.Error = "policy: " + result.Error
+			return resp.StatusCode == http.StatusTooManyRequests
+		}},
+	})
+
+	result := client.GetItemByID(1, 2, 3, nil)
+	if calls != 3 {
+		t.Errorf("Expected 1 call and 2 retries, got %d calls", calls)
+	}
+	if result.Success || result.Error != "policy: slow down" {
+		t.Errorf("Expected the hook's error on the final result, got %+v", result)
+	}
+}
+
+func TestClient_OnResponseRetriesStopWithContext(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient(&ClientConfig{
+		BaseURL: server.URL,
+		ResponseHooks: []ResponseHook{func(resp *HookResponse, result *Result) bool {
+			resp.RetryAfter = time.Hour
+			cancel()
+			return true
+		}},
+	})
+
+	start := time.Now()
+	result := client.WithContext(ctx).GetItemByID(1, 2, 3, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the retry wait to end with the context, took %s", elapsed)
+	}
+	if !errors.Is(result.Err(), context.Canceled) || calls != 1 {
+		t.Errorf("Expected a canceled result after 1 call, got %+v after %d calls", result, calls)
+	}
+}
+
+func TestClient_OnResponseRetriesKeepDeadline(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := NewClient(&ClientConfig{
+		BaseURL:        server.URL,
+		MaxHookRetries: 100,
+		ResponseHooks: []ResponseHook{func(resp *HookResponse, result *Result) bool {
+			resp.RetryAfter = 10 * time.Millisecond
+			return true
+		}},
+	})
+
+	result := client.WithContext(ctx).GetItemByID(1, 2, 3, nil)
+	if !errors.Is(result.Err(), context.DeadlineExceeded) || calls > 10 {
+		t.Errorf("Expected retries to stop at the deadline, got %+v after %d calls", result, calls)
+	}
+}
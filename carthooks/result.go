@@ -2,16 +2,48 @@ package carthooks
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Result represents the response from Carthooks API
 type Result struct {
-	Success bool                   `json:"success"`
-	Data    interface{}            `json:"data,omitempty"`
-	Error   string                 `json:"error,omitempty"`
-	TraceID string                 `json:"trace_id,omitempty"`
-	Meta    map[string]interface{} `json:"meta,omitempty"`
+	Success   bool                   `json:"success"`
+	Data      interface{}            `json:"data,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	ErrorCode ErrorCode              `json:"error_code,omitempty"`
+	TraceID   string                 `json:"trace_id,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Meta      map[string]interface{} `json:"meta,omitempty"`
+
+	// NotModified is set when a conditional request found the resource
+	// unchanged; Data is then nil
+	NotModified bool `json:"not_modified,omitempty"`
+	// StatusCode is the HTTP status of the response, or 0 if the request
+	// got no response
+	StatusCode int `json:"status_code,omitempty"`
+	// Header holds the response headers, e.g. rate limit headers
+	Header http.Header `json:"-"`
+
+	raw    []byte
+	stale  *StaleError
+	queued *OutboxEntry
+	err    error
+}
+
+// requestFailed returns the Result for a request that did not get a
+// response from the API
+func requestFailed(err error) *Result {
+	return &Result{
+		Success: false,
+		Error:   err.Error(),
+		err:     err,
+	}
 }
 
 // String returns a string representation of the Result
@@ -60,6 +92,44 @@ func (r *Result) GetRecord() (*RecordFormat, error) {
 	return &record, nil
 }
 
+// Unwrap returns the result's data, or its error if it failed, as in
+// data, err := client.GetApp(appID).Unwrap(). The error is the one Err
+// returns, so it can be matched with errors.Is and errors.As.
+func (r *Result) Unwrap() (interface{}, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return r.Data, nil
+}
+
+// Decode unmarshals the result's data into v, or returns the result's error
+// if it failed
+func (r *Result) Decode(v interface{}) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	return r.GetData(v)
+}
+
+// Records returns the result's records, or its error if it failed, as in
+// records, err := client.GetItems(...).Records()
+func (r *Result) Records() ([]RecordFormat, error) {
+	var records []RecordFormat
+	if err := r.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Record returns the result's record, or its error if it failed
+func (r *Result) Record() (*RecordFormat, error) {
+	var record RecordFormat
+	if err := r.Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
 // GetString is a convenience method to get a string value from data
 func (r *Result) GetString() (string, error) {
 	if !r.Success {
@@ -73,29 +143,132 @@ func (r *Result) GetString() (string, error) {
 	return "", fmt.Errorf("data is not a string")
 }
 
-// GetInt is a convenience method to get an integer value from data
+// GetInt is a convenience method to get an integer value from data. Numbers
+// with a fractional part and strings that are not integers are rejected.
 func (r *Result) GetInt() (int, error) {
+	n, err := r.GetInt64()
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(n)) != n {
+		return 0, fmt.Errorf("data %d overflows int", n)
+	}
+	return int(n), nil
+}
+
+// GetInt64 is a convenience method to get a 64-bit integer value from data
+func (r *Result) GetInt64() (int64, error) {
 	if !r.Success {
 		return 0, fmt.Errorf("result is not successful: %s", r.Error)
 	}
 
 	switch v := r.Data.(type) {
 	case int:
+		return int64(v), nil
+	case int64:
 		return v, nil
+	case uint:
+		if uint64(v) <= math.MaxInt64 {
+			return int64(v), nil
+		}
 	case float64:
-		return int(v), nil
+		// float64 represents every integer up to 2^53 exactly
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int64(v), nil
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
 	case string:
-		// Try to parse string as int
-		if i, err := fmt.Sscanf(v, "%d", new(int)); err == nil && i == 1 {
-			var result int
-			fmt.Sscanf(v, "%d", &result)
-			return result, nil
+		if n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err == nil {
+			return n, nil
 		}
 	}
 
 	return 0, fmt.Errorf("data is not an integer")
 }
 
+// GetUint is a convenience method to get a non-negative integer value, such
+// as an ID, from data
+func (r *Result) GetUint() (uint, error) {
+	if s, ok := r.Data.(string); ok && r.Success {
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, strconv.IntSize)
+		if err != nil {
+			return 0, fmt.Errorf("data is not an unsigned integer")
+		}
+		return uint(n), nil
+	}
+	if v, ok := r.Data.(uint); ok && r.Success {
+		return v, nil
+	}
+
+	n, err := r.GetInt64()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || uint64(n) > uint64(^uint(0)) {
+		return 0, fmt.Errorf("data is not an unsigned integer")
+	}
+	return uint(n), nil
+}
+
+// GetFloat64 is a convenience method to get a numeric value from data
+func (r *Result) GetFloat64() (float64, error) {
+	if !r.Success {
+		return 0, fmt.Errorf("result is not successful: %s", r.Error)
+	}
+
+	switch v := r.Data.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("data is not a number")
+}
+
+// epochMillisThreshold separates Unix timestamps in seconds from ones in
+// milliseconds: 1e11 seconds is in the year 5138, 1e11 milliseconds in 1973
+const epochMillisThreshold = 1e11
+
+// GetTime is a convenience method to get a time from data, given as Unix
+// seconds or milliseconds (as a number or numeric string) or an RFC 3339
+// string
+func (r *Result) GetTime() (time.Time, error) {
+	if !r.Success {
+		return time.Time{}, fmt.Errorf("result is not successful: %s", r.Error)
+	}
+
+	if s, ok := r.Data.(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(s)); err == nil {
+			return t, nil
+		}
+	}
+
+	epoch, err := r.GetInt64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data is not a time")
+	}
+	if epoch >= epochMillisThreshold || epoch <= -epochMillisThreshold {
+		return time.UnixMilli(epoch), nil
+	}
+	return time.Unix(epoch, 0), nil
+}
+
 // GetBool is a convenience method to get a boolean value from data
 func (r *Result) GetBool() (bool, error) {
 	if !r.Success {
@@ -122,17 +295,109 @@ func (r *Result) GetError() string {
 	return ""
 }
 
+// Err returns the result's error, or nil if it succeeded. Error responses
+// from the API are returned as an *APIError, matching the sentinel error
+// for their status, e.g. ErrNotFound. Errors from requests that never
+// reached the API, such as ErrCircuitOpen, are returned as is and can be
+// matched with errors.Is.
+func (r *Result) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	if !r.HasError() {
+		return nil
+	}
+	if r.StatusCode == 0 {
+		return errors.New(r.Error)
+	}
+	return &APIError{
+		StatusCode: r.StatusCode,
+		Code:       r.ErrorCode,
+		Message:    r.Error,
+		TraceID:    r.TraceID,
+		RequestID:  r.RequestID,
+	}
+}
+
+// Raw returns the response body as received, e.g. to decode fields the
+// SDK's types don't model yet. It is nil if the request got no response.
+func (r *Result) Raw() []byte {
+	return r.raw
+}
+
+// ETag returns the response's entity tag, to pass to a later conditional
+// request such as GetItemByIDIfChanged
+func (r *Result) ETag() string {
+	return r.Header.Get("ETag")
+}
+
+// GetHeader returns the first value of the response header name, or ""
+func (r *Result) GetHeader(name string) string {
+	return r.Header.Get(name)
+}
+
+// IsNotFound reports whether the API responded 404 Not Found
+func (r *Result) IsNotFound() bool {
+	return r.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether the API responded 401 Unauthorized
+func (r *Result) IsUnauthorized() bool {
+	return r.StatusCode == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether the API responded 403 Forbidden
+func (r *Result) IsForbidden() bool {
+	return r.StatusCode == http.StatusForbidden
+}
+
+// IsConflict reports whether the API responded 409 Conflict
+func (r *Result) IsConflict() bool {
+	return r.StatusCode == http.StatusConflict
+}
+
+// IsRateLimited reports whether the API responded 429 Too Many Requests
+func (r *Result) IsRateLimited() bool {
+	return r.StatusCode == http.StatusTooManyRequests
+}
+
+// IsServerError reports whether the API responded with a 5xx status
+func (r *Result) IsServerError() bool {
+	return r.StatusCode >= 500 && r.StatusCode < 600
+}
+
 // GetTraceID returns the trace ID for debugging
 func (r *Result) GetTraceID() string {
 	return r.TraceID
 }
 
+// GetRequestID returns the client-generated X-Request-ID of the request, to
+// correlate client logs with server logs and the trace ID
+func (r *Result) GetRequestID() string {
+	return r.RequestID
+}
+
+// IsStale returns true if the result was served from cache because the API
+// was unreachable
+func (r *Result) IsStale() bool {
+	return r.stale != nil
+}
+
+// StaleError returns why a stale cached result was served, or nil if the
+// result is fresh
+func (r *Result) StaleError() *StaleError {
+	return r.stale
+}
+
 // GetMeta returns the metadata
 func (r *Result) GetMeta() map[string]interface{} {
 	return r.Meta
 }
 
-// GetPagination extracts pagination information from meta
+// GetPagination extracts pagination information from meta. Both the
+// page-based shape (page, pageSize, totalPages) and the offset-based shape
+// used by GetItems (start, limit) are understood, and the missing fields of
+// one are derived from the other.
 func (r *Result) GetPagination() *PaginationMeta {
 	if r.Meta == nil {
 		return nil
@@ -142,6 +407,7 @@ func (r *Result) GetPagination() *PaginationMeta {
 		var pagination PaginationMeta
 		if jsonData, err := json.Marshal(paginationData); err == nil {
 			if err := json.Unmarshal(jsonData, &pagination); err == nil {
+				pagination.normalize()
 				return &pagination
 			}
 		}
@@ -156,4 +422,34 @@ type PaginationMeta struct {
 	PageSize   int `json:"pageSize"`
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
+	// Start is the offset of the page's first record and Limit the page
+	// size, as used by offset-based listings
+	Start int `json:"start"`
+	Limit int `json:"limit"`
+}
+
+// normalize fills in the page-based fields from the offset-based ones and
+// vice versa
+func (p *PaginationMeta) normalize() {
+	switch {
+	case p.PageSize == 0 && p.Limit > 0:
+		p.PageSize = p.Limit
+		p.Page = p.Start/p.Limit + 1
+	case p.Limit == 0 && p.PageSize > 0:
+		p.Limit = p.PageSize
+		if p.Page > 0 {
+			p.Start = (p.Page - 1) * p.PageSize
+		}
+	}
+	if p.TotalPages == 0 && p.PageSize > 0 && p.Total > 0 {
+		p.TotalPages = (p.Total + p.PageSize - 1) / p.PageSize
+	}
+}
+
+// HasMore reports whether records remain after this page
+func (p *PaginationMeta) HasMore() bool {
+	if p.TotalPages > 0 {
+		return p.Page < p.TotalPages
+	}
+	return p.Total > 0 && p.Start+p.Limit < p.Total
 }
@@ -1,7 +1,10 @@
 package carthooks
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestResult_GetData(t *testing.T) {
@@ -360,6 +363,29 @@ func TestResult_GetPagination(t *testing.T) {
 				PageSize:   20,
 				Total:      100,
 				TotalPages: 5,
+				Start:      0,
+				Limit:      20,
+			},
+		},
+		{
+			name: "offset pagination metadata",
+			result: &Result{
+				Success: true,
+				Meta: map[string]interface{}{
+					"pagination": map[string]interface{}{
+						"start": 40,
+						"limit": 20,
+						"total": 95,
+					},
+				},
+			},
+			want: &PaginationMeta{
+				Page:       3,
+				PageSize:   20,
+				Total:      95,
+				TotalPages: 5,
+				Start:      40,
+				Limit:      20,
 			},
 		},
 		{
@@ -393,10 +419,91 @@ func TestResult_GetPagination(t *testing.T) {
 					return
 				}
 				if got.Page != tt.want.Page || got.PageSize != tt.want.PageSize ||
-					got.Total != tt.want.Total || got.TotalPages != tt.want.TotalPages {
+					got.Total != tt.want.Total || got.TotalPages != tt.want.TotalPages ||
+					(tt.want.Limit != 0 && (got.Start != tt.want.Start || got.Limit != tt.want.Limit)) {
 					t.Errorf("Result.GetPagination() = %v, want %v", got, tt.want)
 				}
 			}
 		})
 	}
 }
+
+func TestPaginationMeta_HasMore(t *testing.T) {
+	if !(&PaginationMeta{Page: 1, TotalPages: 2}).HasMore() {
+		t.Error("expected more pages after page 1 of 2")
+	}
+	if (&PaginationMeta{Start: 80, Limit: 20, Total: 95}).HasMore() {
+		t.Error("expected no more records after 80+20 of 95")
+	}
+}
+
+func TestResult_Unwrap(t *testing.T) {
+	data, err := (&Result{Success: true, Data: "ok"}).Unwrap()
+	if err != nil || data != "ok" {
+		t.Errorf("Unwrap() = %v, %v", data, err)
+	}
+
+	failed := &Result{Success: false, Error: "item not found", StatusCode: 404}
+	if data, err := failed.Unwrap(); data != nil || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Unwrap() = %v, %v, want ErrNotFound", data, err)
+	}
+	if records, err := failed.Records(); records != nil || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Records() = %v, %v, want ErrNotFound", records, err)
+	}
+
+	result := &Result{Success: true, Data: []interface{}{map[string]interface{}{"id": 7, "title": "Widget"}}}
+	records, err := result.Records()
+	if err != nil || len(records) != 1 || records[0].ID != 7 {
+		t.Errorf("Records() = %+v, %v", records, err)
+	}
+	if _, err := (&Result{Success: true}).Record(); err == nil {
+		t.Error("Record() with no data should fail")
+	}
+}
+
+func TestResult_NumericGetters(t *testing.T) {
+	ok := func(data interface{}) *Result { return &Result{Success: true, Data: data} }
+
+	if n, err := ok("42").GetInt(); err != nil || n != 42 {
+		t.Errorf("GetInt(\"42\") = %d, %v", n, err)
+	}
+	for _, data := range []interface{}{"42abc", 1.5, "1e3"} {
+		if _, err := ok(data).GetInt(); err == nil {
+			t.Errorf("GetInt(%v) should fail", data)
+		}
+	}
+	if n, err := ok(json.Number("9007199254740993")).GetInt64(); err != nil || n != 9007199254740993 {
+		t.Errorf("GetInt64(json.Number) = %d, %v", n, err)
+	}
+	if n, err := ok(float64(7)).GetUint(); err != nil || n != 7 {
+		t.Errorf("GetUint(7) = %d, %v", n, err)
+	}
+	if _, err := ok(float64(-1)).GetUint(); err == nil {
+		t.Error("GetUint(-1) should fail")
+	}
+	if f, err := ok(" 2.5 ").GetFloat64(); err != nil || f != 2.5 {
+		t.Errorf("GetFloat64(\" 2.5 \") = %v, %v", f, err)
+	}
+	if _, err := (&Result{Success: false, Error: "boom"}).GetFloat64(); err == nil {
+		t.Error("GetFloat64 on a failed result should fail")
+	}
+}
+
+func TestResult_GetTime(t *testing.T) {
+	want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, data := range []interface{}{
+		float64(want.Unix()),
+		float64(want.UnixMilli()),
+		"1714564800",
+		"2024-05-01T12:00:00Z",
+		"2024-05-01T14:00:00+02:00",
+	} {
+		got, err := (&Result{Success: true, Data: data}).GetTime()
+		if err != nil || !got.Equal(want) {
+			t.Errorf("GetTime(%v) = %v, %v, want %v", data, got, err, want)
+		}
+	}
+	if _, err := (&Result{Success: true, Data: "yesterday"}).GetTime(); err == nil {
+		t.Error("GetTime(\"yesterday\") should fail")
+	}
+}
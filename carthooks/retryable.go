@@ -0,0 +1,88 @@
+package carthooks
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// IsRetryable reports whether the request that failed with err may succeed
+// if sent again: network failures and timeouts, ErrCircuitOpen, and API
+// errors with status 408, 429, 500, 502, 503 or 504 or a rate limit or
+// unavailability error code. Canceled requests, client errors and
+// certificate failures are not retryable. Retrying requests that create
+// records after a 5xx or network failure may create them twice unless they
+// carry an external ID.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.StatusCode) || retryableCode(apiErr.Code)
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCert) || errors.As(err, &hostname) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
+
+// IsRetryable reports whether the request failed in a way that may succeed
+// if it is sent again; see IsRetryable
+func (r *Result) IsRetryable() bool {
+	return IsRetryable(r.Err())
+}
+
+// retryableStatus reports whether an HTTP status signals a transient failure
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableCode reports whether an API error code signals a transient
+// failure
+func retryableCode(code ErrorCode) bool {
+	return code == ErrorCodeRateLimited || code == ErrorCodeUnavailable
+}
+
+// RetryTransientErrors is a ResponseHook that retries responses
+// IsRetryable classifies as transient, honoring a Retry-After header given
+// in seconds up to ClientConfig.MaxRetryAfter. Add it with
+// Client.OnResponse; ClientConfig.MaxHookRetries caps the retries.
+func RetryTransientErrors(resp *HookResponse, result *Result) bool {
+	if !result.IsRetryable() {
+		return false
+	}
+	if seconds, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil && seconds > 0 {
+		// Guard the conversion; the client caps the wait itself
+		if seconds > math.MaxInt64/int64(time.Second) {
+			seconds = math.MaxInt64 / int64(time.Second)
+		}
+		resp.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return true
+}
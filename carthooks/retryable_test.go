@@ -0,0 +1,101 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", fmt.Errorf("request failed: %w", context.Canceled), false},
+		{"deadline", fmt.Errorf("request failed: %w", context.DeadlineExceeded), true},
+		{"circuit open", ErrCircuitOpen, true},
+		{"429", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"503", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"404", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"422", &APIError{StatusCode: http.StatusUnprocessableEntity}, false},
+		{"rate limit code", &APIError{StatusCode: http.StatusOK, Code: ErrorCodeRateLimited}, true},
+		{"plain", errors.New("OAuth configuration not provided"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable(%v) = %t, want %t", tt.name, tt.err, got, tt.want)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	if result := NewClient(&ClientConfig{BaseURL: server.URL}).GetApps(); !result.IsRetryable() {
+		t.Errorf("IsRetryable() = false for connection failure %v", result.Err())
+	}
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"message":"busy"}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"id":1}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	client.OnResponse(RetryTransientErrors)
+	if result := client.GetApps(); !result.Success || calls != 2 {
+		t.Errorf("Success = %t after %d calls", result.Success, calls)
+	}
+
+	calls = 10
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"message":"missing"}}`)
+	})
+	if result := client.GetApps(); !result.IsNotFound() || calls != 11 {
+		t.Errorf("expected a single attempt for 404, got %d", calls-10)
+	}
+}
+
+func TestRetryTransientErrorsCapsRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":1}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:       server.URL,
+		MaxRetryAfter: 10 * time.Millisecond,
+		ResponseHooks: []ResponseHook{RetryTransientErrors},
+	})
+
+	start := time.Now()
+	if result := client.GetApps(); !result.Success || calls != 2 {
+		t.Errorf("Success = %t after %d calls", result.Success, calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Retry-After capped by MaxRetryAfter, waited %s", elapsed)
+	}
+}
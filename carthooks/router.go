@@ -0,0 +1,60 @@
+package carthooks
+
+import (
+	"errors"
+)
+
+// ErrNoEventHandler is returned by EventRouter.Dispatch when no handler is
+// registered for an event's code
+var ErrNoEventHandler = errors.New("no handler registered for event")
+
+// EventDispatcher dispatches a decoded EventMessage to application code
+type EventDispatcher interface {
+	Dispatch(event *EventMessage) error
+}
+
+// EventHandlerFunc adapts a function to the EventDispatcher interface,
+// receiving every event
+type EventHandlerFunc func(event *EventMessage) error
+
+// Dispatch calls f(event)
+func (f EventHandlerFunc) Dispatch(event *EventMessage) error {
+	return f(event)
+}
+
+// EventRouter dispatches events to handlers registered per EventCode
+type EventRouter struct {
+	handlers map[EventCode]EventHandlerFunc
+	fallback EventHandlerFunc
+}
+
+// NewEventRouter creates an empty EventRouter
+func NewEventRouter() *EventRouter {
+	return &EventRouter{
+		handlers: make(map[EventCode]EventHandlerFunc),
+	}
+}
+
+// On registers handler for events with the given code
+func (r *EventRouter) On(code EventCode, handler EventHandlerFunc) *EventRouter {
+	r.handlers[code] = handler
+	return r
+}
+
+// Default registers a handler for events with no specific handler
+func (r *EventRouter) Default(handler EventHandlerFunc) *EventRouter {
+	r.fallback = handler
+	return r
+}
+
+// Dispatch calls the handler registered for the event's code, or the
+// default handler. It returns ErrNoEventHandler if neither exists.
+func (r *EventRouter) Dispatch(event *EventMessage) error {
+	if handler, ok := r.handlers[event.Meta.Event]; ok {
+		return handler(event)
+	}
+	if r.fallback != nil {
+		return r.fallback(event)
+	}
+	return ErrNoEventHandler
+}
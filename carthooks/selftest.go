@@ -0,0 +1,220 @@
+package carthooks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is the largest difference between the local clock and the
+// API's that SelfTest accepts; larger skews break token expiry handling
+const maxClockSkew = time.Minute
+
+// certExpiryWarning is how close to expiry the API's certificate makes
+// SelfTest warn
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// Ping checks that the API is reachable by requesting its health endpoint.
+// It returns nil for a 2xx response.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.WithContext(ctx).makeRequest("GET", "/health", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health check returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Self-test check outcomes reported in SelfTestCheck.Status
+const (
+	SelfTestPass = "pass"
+	SelfTestWarn = "warn"
+	SelfTestFail = "fail"
+	SelfTestSkip = "skip"
+)
+
+// SelfTestCheck is the outcome of one SelfTest check
+type SelfTestCheck struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// SelfTestReport is the outcome of SelfTest
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+// OK reports whether no check failed; warnings are allowed
+func (r *SelfTestReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns the failed checks as one error, or nil
+func (r *SelfTestReport) Err() error {
+	var errs []error
+	for _, check := range r.Checks {
+		if check.Status == SelfTestFail {
+			errs = append(errs, fmt.Errorf("%s: %s", check.Name, check.Detail))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// String formats the report one check per line, e.g. for a support ticket
+func (r *SelfTestReport) String() string {
+	var b strings.Builder
+	for _, check := range r.Checks {
+		fmt.Fprintf(&b, "[%s] %s: %s\n", check.Status, check.Name, check.Detail)
+	}
+	return b.String()
+}
+
+func (r *SelfTestReport) add(name, status, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, SelfTestCheck{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+// SelfTest diagnoses the client's environment: that the base URL is valid
+// and reachable, that TLS verifies, that the local clock agrees with the
+// API's, and that the client's token is accepted. Checks that depend on a
+// failed one are skipped. Use WithContext to bound it.
+func (c *Client) SelfTest() *SelfTestReport {
+	report := &SelfTestReport{}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		report.add("base_url", SelfTestFail, "%q is not an http(s) URL", c.baseURL)
+		report.add("reachability", SelfTestSkip, "invalid base URL")
+		report.add("tls", SelfTestSkip, "invalid base URL")
+		report.add("clock_skew", SelfTestSkip, "invalid base URL")
+		report.add("token", SelfTestSkip, "invalid base URL")
+		return report
+	}
+	report.add("base_url", SelfTestPass, "%s", c.baseURL)
+
+	started := time.Now()
+	resp, err := c.makeRequest("GET", "/health", nil, nil)
+	if err != nil {
+		if tlsErr := tlsFailure(err); tlsErr != "" {
+			report.add("reachability", SelfTestPass, "connected to %s", u.Host)
+			report.add("tls", SelfTestFail, "%s", tlsErr)
+		} else {
+			report.add("reachability", SelfTestFail, "%v", err)
+			report.add("tls", SelfTestSkip, "API unreachable")
+		}
+		report.add("clock_skew", SelfTestSkip, "API unreachable")
+		report.add("token", SelfTestSkip, "API unreachable")
+		return report
+	}
+	resp.Body.Close()
+	elapsed := time.Since(started)
+
+	if resp.StatusCode >= 500 {
+		report.add("reachability", SelfTestWarn, "health endpoint returned %s after %s", resp.Status, elapsed.Round(time.Millisecond))
+	} else {
+		report.add("reachability", SelfTestPass, "%s responded in %s", u.Host, elapsed.Round(time.Millisecond))
+	}
+
+	c.checkTLS(report, u, resp.TLS)
+	checkClockSkew(report, resp.Header.Get("Date"), started, elapsed)
+	c.checkToken(report)
+
+	return report
+}
+
+// tlsFailure describes err if it is a TLS handshake or certificate error,
+// or returns ""
+func tlsFailure(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var verification *tls.CertificateVerificationError
+	var header tls.RecordHeaderError
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return "certificate signed by an unknown authority; add the CA with ClientConfig.TLS"
+	case errors.As(err, &hostname):
+		return fmt.Sprintf("certificate is not valid for this host: %v", hostname)
+	case errors.As(err, &invalid):
+		return fmt.Sprintf("invalid certificate: %v", invalid)
+	case errors.As(err, &verification):
+		return fmt.Sprintf("certificate verification failed: %v", verification.Err)
+	case errors.As(err, &header):
+		return "server did not answer with TLS; check the base URL's scheme and port"
+	}
+	return ""
+}
+
+func (c *Client) checkTLS(report *SelfTestReport, u *url.URL, state *tls.ConnectionState) {
+	if u.Scheme != "https" {
+		report.add("tls", SelfTestWarn, "base URL is not HTTPS; tokens are sent in clear text")
+		return
+	}
+	if state == nil || len(state.PeerCertificates) == 0 {
+		report.add("tls", SelfTestPass, "verified")
+		return
+	}
+
+	cert := state.PeerCertificates[0]
+	if until := time.Until(cert.NotAfter); until < certExpiryWarning {
+		report.add("tls", SelfTestWarn, "certificate for %s expires at %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+		return
+	}
+	report.add("tls", SelfTestPass, "%s, certificate valid until %s", tls.VersionName(state.Version), cert.NotAfter.Format("2006-01-02"))
+}
+
+func checkClockSkew(report *SelfTestReport, date string, started time.Time, elapsed time.Duration) {
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		report.add("clock_skew", SelfTestSkip, "API sent no Date header")
+		return
+	}
+
+	// The Date header has second precision and was set during the request
+	skew := started.Add(elapsed / 2).Sub(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew+elapsed+time.Second {
+		report.add("clock_skew", SelfTestFail, "local clock differs from the API's by %s; sync it with NTP", skew.Round(time.Second))
+		return
+	}
+	report.add("clock_skew", SelfTestPass, "within %s", maxClockSkew)
+}
+
+func (c *Client) checkToken(report *SelfTestReport) {
+	if c.accessToken == "" {
+		if c.oauthConfig == nil {
+			report.add("token", SelfTestFail, "no access token or OAuth configuration")
+		} else {
+			report.add("token", SelfTestFail, "OAuth configured but no token obtained; call InitializeOAuth")
+		}
+		return
+	}
+
+	result := c.GetCurrentUser()
+	switch {
+	case result.Success:
+		var user UserInfo
+		result.GetData(&user)
+		report.add("token", SelfTestPass, "authenticated as %s in tenant %d", user.Username, user.TenantID)
+	case result.IsUnauthorized():
+		report.add("token", SelfTestFail, "token rejected: %s", result.Error)
+	case result.IsForbidden():
+		report.add("token", SelfTestWarn, "token accepted but may not read the current user: %s", result.Error)
+	default:
+		report.add("token", SelfTestFail, "could not validate token: %v", result.Err())
+	}
+}
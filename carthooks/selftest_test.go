@@ -0,0 +1,98 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Ping(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() returned %v", err)
+	}
+	healthy = false
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("Expected Ping() to fail on 503")
+	}
+}
+
+func selfTestStatus(report *SelfTestReport, name string) string {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check.Status
+		}
+	}
+	return ""
+}
+
+func TestClient_SelfTest(t *testing.T) {
+	skew := time.Duration(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		switch r.URL.Path {
+		case "/health":
+			fmt.Fprint(w, "ok")
+		case "/v1/me":
+			if r.Header.Get("Authorization") != "Bearer good-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":{"message":"invalid token","code":"UNAUTHORIZED"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"user_id":1,"username":"ops","tenant_id":9}}`)
+		}
+	}))
+	defer server.Close()
+
+	report := NewClient(&ClientConfig{BaseURL: server.URL, AccessToken: "good-token"}).SelfTest()
+	if !report.OK() {
+		t.Fatalf("SelfTest() failed:\n%s", report)
+	}
+	for name, want := range map[string]string{"base_url": SelfTestPass, "reachability": SelfTestPass, "tls": SelfTestWarn, "clock_skew": SelfTestPass, "token": SelfTestPass} {
+		if got := selfTestStatus(report, name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	skew = 10 * time.Minute
+	report = NewClient(&ClientConfig{BaseURL: server.URL, AccessToken: "bad-token"}).SelfTest()
+	if selfTestStatus(report, "clock_skew") != SelfTestFail || selfTestStatus(report, "token") != SelfTestFail {
+		t.Errorf("Expected clock skew and token failures:\n%s", report)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "token rejected") {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestClient_SelfTestUntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	report := NewClient(&ClientConfig{BaseURL: server.URL, AccessToken: "token"}).SelfTest()
+	if selfTestStatus(report, "reachability") != SelfTestPass || selfTestStatus(report, "tls") != SelfTestFail || selfTestStatus(report, "token") != SelfTestSkip {
+		t.Errorf("Expected a TLS failure:\n%s", report)
+	}
+}
+
+func TestClient_SelfTestInvalidBaseURL(t *testing.T) {
+	report := NewClient(&ClientConfig{BaseURL: "api.carthooks.com"}).SelfTest()
+	if report.OK() || selfTestStatus(report, "base_url") != SelfTestFail || selfTestStatus(report, "reachability") != SelfTestSkip {
+		t.Errorf("Expected a base URL failure:\n%s", report)
+	}
+}
@@ -0,0 +1,69 @@
+package carthooks
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrMissingSignature is returned when a delivery carries no signature
+	ErrMissingSignature = errors.New("event signature missing")
+	// ErrInvalidSignature is returned when a signature does not match the
+	// delivery body
+	ErrInvalidSignature = errors.New("event signature invalid")
+)
+
+// EventSignature describes the signature an event was delivered with
+type EventSignature struct {
+	// Algorithm is the signature scheme, e.g. "sha256"
+	Algorithm string
+	// Value is the hex encoded signature
+	Value string
+	// Verified is true once the signature has been checked against the
+	// shared secret
+	Verified bool
+}
+
+// VerifyEventSignature checks the WebhookSignatureHeader of a webhook or
+// queue delivery against body. It returns the parsed signature, and
+// ErrMissingSignature or ErrInvalidSignature if the event cannot be trusted.
+func VerifyEventSignature(headers http.Header, body []byte, secret string) (*EventSignature, error) {
+	header := headers.Get(WebhookSignatureHeader)
+	if header == "" {
+		return nil, ErrMissingSignature
+	}
+
+	algorithm, value, _ := strings.Cut(header, "=")
+	signature := &EventSignature{Algorithm: algorithm, Value: value}
+	if !VerifyWebhookSignature(secret, body, header) {
+		return signature, ErrInvalidSignature
+	}
+	signature.Verified = true
+	return signature, nil
+}
+
+// messageHeaders exposes a message's attributes as headers, so queue
+// deliveries can be verified like webhooks
+func messageHeaders(message *Message) http.Header {
+	headers := http.Header{}
+	for name, value := range message.Attributes {
+		headers.Set(name, value)
+	}
+	return headers
+}
+
+// verifyEvent checks the signature of message when the watcher has a
+// SigningSecret, and attaches it to event
+func (w *Watcher) verifyEvent(message *Message, event *EventMessage) error {
+	if w.config.SigningSecret == "" {
+		return nil
+	}
+
+	signature, err := VerifyEventSignature(messageHeaders(message), message.Body, w.config.SigningSecret)
+	if err != nil {
+		return err
+	}
+	event.Signature = signature
+	return nil
+}
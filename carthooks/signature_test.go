@@ -0,0 +1,55 @@
+package carthooks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyEventSignature(t *testing.T) {
+	body := []byte(`{"version":"1"}`)
+	headers := http.Header{}
+
+	if _, err := VerifyEventSignature(headers, body, "secret"); err != ErrMissingSignature {
+		t.Errorf("Expected ErrMissingSignature, got %v", err)
+	}
+
+	headers.Set(WebhookSignatureHeader, SignWebhookPayload("other-secret", body))
+	signature, err := VerifyEventSignature(headers, body, "secret")
+	if err != ErrInvalidSignature || signature == nil || signature.Verified {
+		t.Errorf("Expected ErrInvalidSignature, got %+v, %v", signature, err)
+	}
+
+	headers.Set(WebhookSignatureHeader, SignWebhookPayload("secret", body))
+	signature, err = VerifyEventSignature(headers, body, "secret")
+	if err != nil || !signature.Verified || signature.Algorithm != "sha256" || len(signature.Value) != 64 {
+		t.Errorf("Expected verified signature, got %+v, %v", signature, err)
+	}
+}
+
+func TestWatcher_RejectsUnsignedMessages(t *testing.T) {
+	source := &recordingSource{}
+	var received []*EventMessage
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:        source,
+		SigningSecret: "secret",
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			received = append(received, event)
+			return nil
+		},
+	})
+
+	signed := fifoMessage("1", "", 1)
+	signed.Attributes = map[string]string{WebhookSignatureHeader: SignWebhookPayload("secret", signed.Body)}
+	forged := fifoMessage("2", "", 2)
+	forged.Attributes = map[string]string{WebhookSignatureHeader: SignWebhookPayload("guess", forged.Body)}
+
+	watcher.processBatch(context.Background(), []*Message{signed, forged, fifoMessage("3", "", 3)})
+
+	if len(received) != 1 || received[0].Signature == nil || !received[0].Signature.Verified {
+		t.Fatalf("Expected only the signed event delivered with its signature, got %v", received)
+	}
+	if len(source.acked) != 1 || len(source.nacked) != 2 {
+		t.Errorf("Expected 1 acked and 2 nacked, got acked=%v nacked=%v", source.acked, source.nacked)
+	}
+}
@@ -0,0 +1,297 @@
+package carthooks
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNS message types
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// snsHostPattern matches the hosts SNS signing certificates and subscription
+// URLs are served from
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSMessage is the JSON document SNS posts to HTTP(S) subscribers
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token,omitempty"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+	UnsubscribeURL   string `json:"UnsubscribeURL,omitempty"`
+}
+
+// stringToSign builds the canonical string SNS signs for this message
+func (m *SNSMessage) stringToSign() string {
+	var fields []string
+	add := func(name, value string) {
+		fields = append(fields, name, value)
+	}
+
+	add("Message", m.Message)
+	add("MessageId", m.MessageID)
+	if m.Type == SNSTypeNotification {
+		if m.Subject != "" {
+			add("Subject", m.Subject)
+		}
+	} else {
+		add("SubscribeURL", m.SubscribeURL)
+	}
+	add("Timestamp", m.Timestamp)
+	if m.Type != SNSTypeNotification {
+		add("Token", m.Token)
+	}
+	add("TopicArn", m.TopicArn)
+	add("Type", m.Type)
+
+	return strings.Join(fields, "\n") + "\n"
+}
+
+// SNSHandler is an http.Handler for SNS HTTP(S) subscriptions. It confirms
+// subscriptions, validates message signatures and unwraps the inner
+// EventMessage before calling Handler.
+type SNSHandler struct {
+	Handler func(event *EventMessage) error
+	// TopicArn, if set, rejects messages from any other topic
+	TopicArn   string
+	HTTPClient *http.Client
+	// Logger receives subscription changes and failures (default
+	// slog.Default())
+	Logger *slog.Logger
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+
+	// fetchCert is overridden in tests
+	fetchCert func(certURL string) (*x509.Certificate, error)
+}
+
+// NewSNSHandler creates an SNSHandler that passes events to handler
+func NewSNSHandler(handler func(event *EventMessage) error) *SNSHandler {
+	return &SNSHandler{
+		Handler:    handler,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SNSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var message SNSMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		http.Error(w, "invalid SNS message", http.StatusBadRequest)
+		return
+	}
+
+	if h.TopicArn != "" && message.TopicArn != h.TopicArn {
+		http.Error(w, "unexpected topic", http.StatusForbidden)
+		return
+	}
+
+	if err := h.VerifySignature(&message); err != nil {
+		orDefaultLogger(h.Logger).Warn("SNS signature verification failed", "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	switch message.Type {
+	case SNSTypeSubscriptionConfirmation:
+		if err := h.confirmSubscription(&message); err != nil {
+			orDefaultLogger(h.Logger).Error("SNS subscription confirmation failed", "error", err)
+			http.Error(w, "subscription confirmation failed", http.StatusBadGateway)
+			return
+		}
+		orDefaultLogger(h.Logger).Info("SNS subscription confirmed", "topic_arn", message.TopicArn)
+
+	case SNSTypeNotification:
+		var event EventMessage
+		if err := json.Unmarshal([]byte(message.Message), &event); err != nil {
+			http.Error(w, fmt.Sprintf("invalid event payload: %v", err), http.StatusBadRequest)
+			return
+		}
+		if h.Handler != nil {
+			if err := h.Handler(&event); err != nil {
+				orDefaultLogger(h.Logger).Warn("SNS handler failed", "error", err)
+				http.Error(w, "handler failed", http.StatusInternalServerError)
+				return
+			}
+		}
+
+	case SNSTypeUnsubscribeConfirmation:
+		orDefaultLogger(h.Logger).Info("SNS subscription removed", "topic_arn", message.TopicArn)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// VerifySignature validates the SNS signature of message against the
+// signing certificate it references
+func (h *SNSHandler) VerifySignature(message *SNSMessage) error {
+	var hash crypto.Hash
+	switch message.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported signature version %q", message.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	cert, err := h.certificate(message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("signing certificate does not hold an RSA key")
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(message.stringToSign()))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(message.stringToSign()))
+		digest = sum[:]
+	}
+
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return fmt.Errorf("signature mismatch: %w", err)
+	}
+
+	return nil
+}
+
+// certificate returns the (cached) signing certificate at certURL
+func (h *SNSHandler) certificate(certURL string) (*x509.Certificate, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if cert, ok := h.certs[certURL]; ok {
+		return cert, nil
+	}
+
+	fetch := h.fetchCert
+	if fetch == nil {
+		fetch = h.downloadCert
+	}
+
+	cert, err := fetch(certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.certs == nil {
+		h.certs = make(map[string]*x509.Certificate)
+	}
+	h.certs[certURL] = cert
+	return cert, nil
+}
+
+// downloadCert fetches and parses a signing certificate from an SNS host
+func (h *SNSHandler) downloadCert(certURL string) (*x509.Certificate, error) {
+	if err := validateSNSURL(certURL); err != nil {
+		return nil, fmt.Errorf("untrusted signing certificate URL: %w", err)
+	}
+
+	resp, err := h.httpClient().Get(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// confirmSubscription visits the SubscribeURL of a confirmation message
+func (h *SNSHandler) confirmSubscription(message *SNSMessage) error {
+	if err := validateSNSURL(message.SubscribeURL); err != nil {
+		return fmt.Errorf("untrusted subscribe URL: %w", err)
+	}
+
+	resp, err := h.httpClient().Get(message.SubscribeURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (h *SNSHandler) httpClient() *http.Client {
+	if h.HTTPClient != nil {
+		return h.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// validateSNSURL ensures rawURL is an HTTPS URL on an SNS host
+func validateSNSURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || !snsHostPattern.MatchString(u.Hostname()) {
+		return fmt.Errorf("%s is not an SNS URL", rawURL)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package carthooks
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestSNSSigner(t *testing.T) (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return key, cert
+}
+
+func signSNSMessage(t *testing.T, key *rsa.PrivateKey, message *SNSMessage) {
+	message.SignatureVersion = "2"
+	message.SigningCertURL = "https://sns.us-east-1.amazonaws.com/cert.pem"
+	digest := sha256.Sum256([]byte(message.stringToSign()))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign message: %v", err)
+	}
+	message.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestSNSHandler_Notification(t *testing.T) {
+	key, cert := newTestSNSSigner(t)
+
+	var received *EventMessage
+	handler := NewSNSHandler(func(event *EventMessage) error {
+		received = event
+		return nil
+	})
+	handler.fetchCert = func(string) (*x509.Certificate, error) { return cert, nil }
+
+	message := &SNSMessage{
+		Type:      SNSTypeNotification,
+		MessageID: "msg-1",
+		TopicArn:  "arn:aws:sns:us-east-1:123456789012:carthooks",
+		Message:   `{"version":"1","meta":{"event":"collection.item.updated"},"payload":{"id":1}}`,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	signSNSMessage(t, key, message)
+
+	body, _ := json.Marshal(message)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/sns", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if received == nil || received.Meta.Event != EventCodeRecordUpdated {
+		t.Errorf("Unexpected event %+v", received)
+	}
+
+	// Tampering with the message invalidates the signature
+	received = nil
+	message.Message = `{"version":"1","meta":{"event":"collection.item.created"}}`
+	body, _ = json.Marshal(message)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/sns", bytes.NewReader(body)))
+	if rec.Code != http.StatusUnauthorized || received != nil {
+		t.Errorf("Expected 401 for tampered message, got %d", rec.Code)
+	}
+}
+
+func TestValidateSNSURL(t *testing.T) {
+	valid := []string{
+		"https://sns.us-east-1.amazonaws.com/SimpleNotificationService-abc.pem",
+		"https://sns.cn-north-1.amazonaws.com.cn/?Action=ConfirmSubscription",
+	}
+	for _, u := range valid {
+		if err := validateSNSURL(u); err != nil {
+			t.Errorf("validateSNSURL(%s) = %v, want nil", u, err)
+		}
+	}
+
+	invalid := []string{
+		"http://sns.us-east-1.amazonaws.com/cert.pem",
+		"https://sns.us-east-1.amazonaws.com.evil.com/cert.pem",
+		"https://example.com/cert.pem",
+	}
+	for _, u := range invalid {
+		if err := validateSNSURL(u); err == nil {
+			t.Errorf("validateSNSURL(%s) = nil, want error", u)
+		}
+	}
+}
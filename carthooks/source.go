@@ -0,0 +1,57 @@
+package carthooks
+
+import (
+	"context"
+	"time"
+)
+
+// Message is a single event message received from a MessageSource
+type Message struct {
+	ID         string
+	Body       []byte
+	Attributes map[string]string
+	// GroupID orders related messages: messages sharing a GroupID are
+	// processed in the order received (e.g. the SQS FIFO MessageGroupId)
+	GroupID string
+	// DeduplicationID identifies redeliveries of the same event (e.g. the
+	// SQS FIFO MessageDeduplicationId)
+	DeduplicationID string
+	// ReceiveCount is how many times the message has been delivered, if the
+	// source reports it (e.g. the SQS ApproximateReceiveCount)
+	ReceiveCount int
+	// Raw is the backend-specific message, e.g. types.Message for SQS
+	Raw interface{}
+}
+
+// MessageSource delivers event messages to a Watcher. Implementations exist
+// for SQS (SQSSource) and, in subpackages, for other brokers.
+type MessageSource interface {
+	// Receive blocks until messages are available, the backend's poll
+	// interval elapses (returning no messages) or ctx is cancelled
+	Receive(ctx context.Context) ([]*Message, error)
+	// Ack marks a message as successfully processed
+	Ack(ctx context.Context, message *Message) error
+	// Nack marks a message as failed so it can be redelivered
+	Nack(ctx context.Context, message *Message) error
+	// Close releases the source's resources
+	Close() error
+}
+
+// EndpointProvider is implemented by sources that Carthooks can deliver to
+// directly. The Watcher registers the returned endpoint with StartWatchData.
+type EndpointProvider interface {
+	Endpoint() (endpointURL, endpointType string)
+}
+
+// VisibilityExtender is implemented by sources that hide received messages
+// from other consumers for a limited time. The Watcher uses it to keep a
+// message hidden while a long-running handler processes it.
+type VisibilityExtender interface {
+	ExtendVisibility(ctx context.Context, message *Message, timeout time.Duration) error
+}
+
+// BatchAcker is implemented by sources that can acknowledge several messages
+// in one call. The Watcher acknowledges each processed batch this way.
+type BatchAcker interface {
+	AckBatch(ctx context.Context, messages []*Message) error
+}
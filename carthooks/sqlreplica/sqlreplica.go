@@ -0,0 +1,137 @@
+// Package sqlreplica provides a database/sql backed carthooks.ReplicaStore
+// for SQLite and Postgres. It works with any driver; bring your own, e.g.
+// modernc.org/sqlite or github.com/jackc/pgx/v5/stdlib.
+//
+//	db, _ := sql.Open("sqlite", "replica.db")
+//	store := sqlreplica.New(db, sqlreplica.Config{Dialect: sqlreplica.SQLite})
+//	if err := store.CreateTable(ctx); err != nil { ... }
+//	sync, _ := carthooks.NewSync(store, watcherConfig)
+package sqlreplica
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// Dialect adapts the store's statements to a database
+type Dialect struct {
+	// Placeholder returns the bind parameter for the n-th argument,
+	// counting from 1
+	Placeholder func(n int) string
+}
+
+var (
+	// SQLite uses ? placeholders
+	SQLite = Dialect{Placeholder: func(int) string { return "?" }}
+	// Postgres uses $n placeholders
+	Postgres = Dialect{Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) }}
+)
+
+// Config holds configuration for a SQL replica store
+type Config struct {
+	// Table holds the records (default "carthooks_replica")
+	Table string
+	// Dialect defaults to SQLite
+	Dialect Dialect
+}
+
+// Store is a carthooks.ReplicaStore backed by a SQL table keyed by
+// collection and item ID. Fields are stored as JSON text.
+type Store struct {
+	db     *sql.DB
+	config Config
+}
+
+var _ carthooks.ReplicaStore = (*Store)(nil)
+
+// New creates a SQL replica store
+func New(db *sql.DB, config Config) *Store {
+	if config.Table == "" {
+		config.Table = "carthooks_replica"
+	}
+	if config.Dialect.Placeholder == nil {
+		config.Dialect = SQLite
+	}
+	return &Store{db: db, config: config}
+}
+
+// CreateTable creates the store's table if it does not exist
+func (s *Store) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	collection_id BIGINT NOT NULL,
+	item_id BIGINT NOT NULL,
+	title TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	updated_at BIGINT NOT NULL,
+	creator BIGINT NOT NULL,
+	fields TEXT NOT NULL,
+	deleted BOOLEAN NOT NULL,
+	PRIMARY KEY (collection_id, item_id)
+)`, s.config.Table))
+	if err != nil {
+		return fmt.Errorf("failed to create replica table: %w", err)
+	}
+	return nil
+}
+
+// Get implements carthooks.ReplicaStore
+func (s *Store) Get(ctx context.Context, collectionID, itemID uint) (*carthooks.ReplicaRecord, error) {
+	query := fmt.Sprintf(
+		"SELECT title, created_at, updated_at, creator, fields, deleted FROM %s WHERE collection_id = %s AND item_id = %s",
+		s.config.Table, s.placeholder(1), s.placeholder(2))
+
+	record := &carthooks.ReplicaRecord{CollectionID: collectionID}
+	record.ID = itemID
+	var fields string
+	err := s.db.QueryRowContext(ctx, query, collectionID, itemID).Scan(
+		&record.Title, &record.CreatedAt, &record.UpdatedAt, &record.Creator, &fields, &record.Deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replica record: %w", err)
+	}
+	if err := json.Unmarshal([]byte(fields), &record.Fields); err != nil {
+		return nil, fmt.Errorf("failed to decode replica fields: %w", err)
+	}
+	return record, nil
+}
+
+// Put implements carthooks.ReplicaStore. The upsert only replaces a stored
+// record that is older, or from the same second and not a tombstone, so the
+// check is atomic.
+func (s *Store) Put(ctx context.Context, record *carthooks.ReplicaRecord) error {
+	fields, err := json.Marshal(record.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode replica fields: %w", err)
+	}
+
+	columns := []string{"collection_id", "item_id", "title", "created_at", "updated_at", "creator", "fields", "deleted"}
+	placeholders := make([]string, len(columns))
+	updates := make([]string, 0, len(columns)-2)
+	for i, column := range columns {
+		placeholders[i] = s.placeholder(i + 1)
+		if i >= 2 {
+			updates = append(updates, column+" = excluded."+column)
+		}
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (collection_id, item_id) DO UPDATE SET %s WHERE %s.updated_at < excluded.updated_at OR (%s.updated_at = excluded.updated_at AND NOT %s.deleted)",
+		s.config.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", "), strings.Join(updates, ", "), s.config.Table, s.config.Table, s.config.Table)
+
+	_, err = s.db.ExecContext(ctx, query,
+		record.CollectionID, record.ID, record.Title, record.CreatedAt, record.UpdatedAt, record.Creator, string(fields), record.Deleted)
+	if err != nil {
+		return fmt.Errorf("failed to write replica record: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) placeholder(n int) string {
+	return s.config.Dialect.Placeholder(n)
+}
@@ -0,0 +1,194 @@
+package sqlreplica
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+// fakeDriver records statements and answers queries with canned rows, so
+// the store's SQL can be checked without a database
+type fakeDriver struct {
+	mu    sync.Mutex
+	execs []fakeStatement
+	rows  [][]driver.Value
+}
+
+type fakeStatement struct {
+	query string
+	args  []driver.Value
+}
+
+var (
+	driverMu  sync.Mutex
+	driverSeq int
+)
+
+// openFake registers a fresh fake driver and opens a database on it
+func openFake(t *testing.T) (*sql.DB, *fakeDriver) {
+	driverMu.Lock()
+	driverSeq++
+	name := fmt.Sprintf("sqlreplica-fake-%d", driverSeq)
+	driverMu.Unlock()
+
+	fake := &fakeDriver{}
+	sql.Register(name, fake)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+func (d *fakeDriver) statements() []fakeStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]fakeStatement(nil), d.execs...)
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.conn.driver
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.execs = append(d.execs, fakeStatement{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.conn.driver
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.execs = append(d.execs, fakeStatement{query: s.query, args: args})
+	return &fakeRows{rows: d.rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"title", "created_at", "updated_at", "creator", "fields", "deleted"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestStore_PutOnlyReplacesOlderRecords(t *testing.T) {
+	db, fake := openFake(t)
+	store := New(db, Config{})
+
+	record := &carthooks.ReplicaRecord{CollectionID: 2}
+	record.ID = 9
+	record.Title = "Widget"
+	record.UpdatedAt = 20
+	record.Fields = map[string]interface{}{"f_1": "a"}
+	if err := store.Put(context.Background(), record); err != nil {
+		t.Fatalf("Put() failed: %v", err)
+	}
+
+	statements := fake.statements()
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %d", len(statements))
+	}
+	query := statements[0].query
+	if !strings.Contains(query, "INSERT INTO carthooks_replica") || !strings.Contains(query, "VALUES (?, ?, ?, ?, ?, ?, ?, ?)") {
+		t.Errorf("Unexpected insert %q", query)
+	}
+	if !strings.HasSuffix(query, "WHERE carthooks_replica.updated_at < excluded.updated_at OR (carthooks_replica.updated_at = excluded.updated_at AND NOT carthooks_replica.deleted)") {
+		t.Errorf("Expected the upsert to keep newer records and tombstones on a tie, got %q", query)
+	}
+	args := statements[0].args
+	if len(args) != 8 || args[0] != int64(2) || args[1] != int64(9) || args[4] != int64(20) || args[6] != `{"f_1":"a"}` {
+		t.Errorf("Unexpected arguments %v", args)
+	}
+}
+
+func TestStore_PostgresPlaceholders(t *testing.T) {
+	db, fake := openFake(t)
+	store := New(db, Config{Table: "replica", Dialect: Postgres})
+
+	record := &carthooks.ReplicaRecord{CollectionID: 2}
+	record.ID = 9
+	store.Put(context.Background(), record)
+	store.Get(context.Background(), 2, 9)
+
+	statements := fake.statements()
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+	if !strings.Contains(statements[0].query, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8)") || !strings.Contains(statements[0].query, "WHERE replica.updated_at < excluded.updated_at OR (replica.updated_at = excluded.updated_at AND NOT replica.deleted)") {
+		t.Errorf("Unexpected insert %q", statements[0].query)
+	}
+	if !strings.HasSuffix(statements[1].query, "FROM replica WHERE collection_id = $1 AND item_id = $2") {
+		t.Errorf("Unexpected select %q", statements[1].query)
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	db, fake := openFake(t)
+	store := New(db, Config{})
+	ctx := context.Background()
+
+	if record, err := store.Get(ctx, 2, 9); record != nil || err != nil {
+		t.Errorf("Expected no record, got %+v, %v", record, err)
+	}
+
+	fake.rows = [][]driver.Value{{"Widget", int64(10), int64(20), int64(5), `{"f_1":"a"}`, true}}
+	record, err := store.Get(ctx, 2, 9)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if record.CollectionID != 2 || record.ID != 9 || record.Title != "Widget" || record.UpdatedAt != 20 || !record.Deleted || record.Fields["f_1"] != "a" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+}
+
+func TestStore_CreateTable(t *testing.T) {
+	db, fake := openFake(t)
+	if err := New(db, Config{Table: "replica"}).CreateTable(context.Background()); err != nil {
+		t.Fatalf("CreateTable() failed: %v", err)
+	}
+	statements := fake.statements()
+	if len(statements) != 1 || !strings.HasPrefix(statements[0].query, "CREATE TABLE IF NOT EXISTS replica") {
+		t.Errorf("Unexpected statements %+v", statements)
+	}
+}
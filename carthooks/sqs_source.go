@@ -0,0 +1,320 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Defaults and limits for SQS receive parameters
+const (
+	defaultSQSMaxMessages       = 5
+	defaultSQSVisibilityTimeout = 5 * time.Minute
+	defaultSQSWaitTime          = 20 * time.Second
+
+	maxSQSMaxMessages       = 10
+	maxSQSVisibilityTimeout = 12 * time.Hour
+	maxSQSWaitTime          = 20 * time.Second
+	maxSQSBatchSize         = 10
+)
+
+// SQSSource is a MessageSource backed by an SQS queue. For FIFO queues
+// (URLs ending in .fifo) each Message carries its MessageGroupId and
+// MessageDeduplicationId as GroupID and DeduplicationID.
+type SQSSource struct {
+	client            *sqs.Client
+	queueURL          string
+	fifo              bool
+	maxMessages       int32
+	visibilityTimeout time.Duration
+	waitTime          time.Duration
+}
+
+// SQSSourceConfig configures an SQSSource
+type SQSSourceConfig struct {
+	QueueURL string
+	Region   string
+
+	// Client, if set, is used as is; AWSConfig, Endpoint and Region are
+	// ignored
+	Client *sqs.Client
+	// AWSConfig replaces the default AWS credential chain, e.g. to assume
+	// an IAM role
+	AWSConfig *aws.Config
+	// Endpoint overrides the SQS endpoint, e.g. http://localhost:4566 for
+	// LocalStack
+	Endpoint string
+
+	// MaxMessages is the maximum number of messages per receive, 1-10
+	// (default 5)
+	MaxMessages int
+	// VisibilityTimeout hides received messages from other consumers, up
+	// to 12 hours (default 5 minutes)
+	VisibilityTimeout time.Duration
+	// WaitTime is how long a receive long-polls for messages, up to 20
+	// seconds (default 20 seconds)
+	WaitTime time.Duration
+}
+
+// validate checks the receive parameters against SQS limits and fills in
+// defaults
+func (c *SQSSourceConfig) validate() error {
+	if c.MaxMessages == 0 {
+		c.MaxMessages = defaultSQSMaxMessages
+	}
+	if c.MaxMessages < 1 || c.MaxMessages > maxSQSMaxMessages {
+		return fmt.Errorf("SQS MaxMessages must be between 1 and %d, got %d", maxSQSMaxMessages, c.MaxMessages)
+	}
+
+	if c.VisibilityTimeout == 0 {
+		c.VisibilityTimeout = defaultSQSVisibilityTimeout
+	}
+	if c.VisibilityTimeout < time.Second || c.VisibilityTimeout > maxSQSVisibilityTimeout {
+		return fmt.Errorf("SQS VisibilityTimeout must be between 1s and %s, got %s", maxSQSVisibilityTimeout, c.VisibilityTimeout)
+	}
+
+	if c.WaitTime == 0 {
+		c.WaitTime = defaultSQSWaitTime
+	}
+	if c.WaitTime < 0 || c.WaitTime > maxSQSWaitTime {
+		return fmt.Errorf("SQS WaitTime must be between 0 and %s, got %s", maxSQSWaitTime, c.WaitTime)
+	}
+
+	return nil
+}
+
+// NewSQSSource creates an SQS message source using the default AWS
+// credential chain
+func NewSQSSource(queueURL, region string) (*SQSSource, error) {
+	return NewSQSSourceFromConfig(SQSSourceConfig{
+		QueueURL: queueURL,
+		Region:   region,
+	})
+}
+
+// NewSQSSourceFromConfig creates an SQS message source from config
+func NewSQSSourceFromConfig(config SQSSourceConfig) (*SQSSource, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	client := config.Client
+	if client == nil {
+		var cfg aws.Config
+		if config.AWSConfig != nil {
+			cfg = config.AWSConfig.Copy()
+		} else {
+			loaded, err := awsConfig.LoadDefaultConfig(context.Background(),
+				awsConfig.WithRegion(config.Region),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config: %w", err)
+			}
+			cfg = loaded
+		}
+		if config.Region != "" {
+			cfg.Region = config.Region
+		}
+
+		client = sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+			if config.Endpoint != "" {
+				o.BaseEndpoint = aws.String(config.Endpoint)
+			}
+		})
+	}
+
+	return &SQSSource{
+		client:            client,
+		queueURL:          config.QueueURL,
+		fifo:              IsFIFOQueue(config.QueueURL),
+		maxMessages:       int32(config.MaxMessages),
+		visibilityTimeout: config.VisibilityTimeout,
+		waitTime:          config.WaitTime,
+	}, nil
+}
+
+// IsFIFOQueue reports whether queueURL names an SQS FIFO queue
+func IsFIFOQueue(queueURL string) bool {
+	return strings.HasSuffix(queueURL, ".fifo")
+}
+
+// FIFO reports whether the source reads from a FIFO queue
+func (s *SQSSource) FIFO() bool {
+	return s.fifo
+}
+
+// Receive long-polls the queue for up to WaitTime
+func (s *SQSSource) Receive(ctx context.Context) ([]*Message, error) {
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(s.queueURL),
+		MaxNumberOfMessages:   s.maxMessages,
+		VisibilityTimeout:     int32(s.visibilityTimeout / time.Second),
+		WaitTimeSeconds:       int32(s.waitTime / time.Second),
+		AttributeNames:        []types.QueueAttributeName{sqsAttributeApproximateReceiveCount},
+		MessageAttributeNames: []string{WebhookSignatureHeader},
+	}
+	if s.fifo {
+		input.AttributeNames = append(input.AttributeNames,
+			sqsAttributeMessageGroupID,
+			sqsAttributeMessageDeduplicationID,
+			sqsAttributeSequenceNumber,
+		)
+	}
+
+	result, err := s.client.ReceiveMessage(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0, len(result.Messages))
+	for _, m := range result.Messages {
+		messages = append(messages, sqsMessage(m))
+	}
+	return messages, nil
+}
+
+// Ack deletes the message from the queue
+func (s *SQSSource) Ack(ctx context.Context, message *Message) error {
+	raw, ok := message.Raw.(types.Message)
+	if !ok {
+		return fmt.Errorf("not an SQS message")
+	}
+
+	_, err := s.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(s.queueURL),
+		ReceiptHandle: raw.ReceiptHandle,
+	})
+	return err
+}
+
+// AckBatch deletes messages from the queue using DeleteMessageBatch, 10 at a
+// time
+func (s *SQSSource) AckBatch(ctx context.Context, messages []*Message) error {
+	var failed int
+	var firstErr error
+	fail := func(n int, err error) {
+		failed += n
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for start := 0; start < len(messages); start += maxSQSBatchSize {
+		end := start + maxSQSBatchSize
+		if end > len(messages) {
+			end = len(messages)
+		}
+
+		entries := make([]types.DeleteMessageBatchRequestEntry, 0, end-start)
+		for i, message := range messages[start:end] {
+			raw, ok := message.Raw.(types.Message)
+			if !ok {
+				fail(1, fmt.Errorf("not an SQS message"))
+				continue
+			}
+			entries = append(entries, types.DeleteMessageBatchRequestEntry{
+				Id:            aws.String(strconv.Itoa(i)),
+				ReceiptHandle: raw.ReceiptHandle,
+			})
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		result, err := s.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+			QueueUrl: aws.String(s.queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			fail(len(entries), err)
+			continue
+		}
+		for _, entry := range result.Failed {
+			fail(1, fmt.Errorf("%s: %s", aws.ToString(entry.Code), aws.ToString(entry.Message)))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d messages: %w", failed, len(messages), firstErr)
+	}
+	return nil
+}
+
+// Nack leaves the message on the queue; it becomes visible again once its
+// visibility timeout expires
+func (s *SQSSource) Nack(ctx context.Context, message *Message) error {
+	return nil
+}
+
+// ExtendVisibility keeps the message hidden for timeout from now
+func (s *SQSSource) ExtendVisibility(ctx context.Context, message *Message, timeout time.Duration) error {
+	raw, ok := message.Raw.(types.Message)
+	if !ok {
+		return fmt.Errorf("not an SQS message")
+	}
+
+	_, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(s.queueURL),
+		ReceiptHandle:     raw.ReceiptHandle,
+		VisibilityTimeout: int32(timeout / time.Second),
+	})
+	return err
+}
+
+// Close is a no-op for SQS
+func (s *SQSSource) Close() error {
+	return nil
+}
+
+// Endpoint returns the queue URL to register with StartWatchData
+func (s *SQSSource) Endpoint() (string, string) {
+	return s.queueURL, EndpointTypeSQS
+}
+
+// System attributes requested from SQS
+const (
+	sqsAttributeApproximateReceiveCount types.QueueAttributeName = "ApproximateReceiveCount"
+	sqsAttributeMessageGroupID          types.QueueAttributeName = "MessageGroupId"
+	sqsAttributeMessageDeduplicationID  types.QueueAttributeName = "MessageDeduplicationId"
+	sqsAttributeSequenceNumber          types.QueueAttributeName = "SequenceNumber"
+)
+
+// sqsMessage converts an SQS message into a Message
+func sqsMessage(m types.Message) *Message {
+	message := &Message{
+		ID:              aws.ToString(m.MessageId),
+		Attributes:      m.Attributes,
+		GroupID:         m.Attributes[string(sqsAttributeMessageGroupID)],
+		DeduplicationID: m.Attributes[string(sqsAttributeMessageDeduplicationID)],
+		Raw:             m,
+	}
+	if m.Body != nil {
+		message.Body = []byte(*m.Body)
+	}
+	if count, err := strconv.Atoi(m.Attributes[string(sqsAttributeApproximateReceiveCount)]); err == nil {
+		message.ReceiveCount = count
+	}
+
+	// String message attributes, e.g. the event signature, sit alongside
+	// the system attributes
+	if len(m.MessageAttributes) > 0 {
+		attributes := make(map[string]string, len(m.Attributes)+len(m.MessageAttributes))
+		for name, value := range m.Attributes {
+			attributes[name] = value
+		}
+		for name, value := range m.MessageAttributes {
+			if value.StringValue != nil {
+				attributes[name] = *value.StringValue
+			}
+		}
+		message.Attributes = attributes
+	}
+	return message
+}
@@ -0,0 +1,249 @@
+package carthooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// fakeSQS is a minimal SQS JSON-protocol endpoint
+type fakeSQS struct {
+	mu       sync.Mutex
+	requests map[string][]map[string]interface{}
+	messages []map[string]interface{}
+}
+
+func newFakeSQS() *fakeSQS {
+	return &fakeSQS{requests: map[string][]map[string]interface{}{}}
+}
+
+func (f *fakeSQS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operation := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.")
+	var input map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&input)
+
+	f.mu.Lock()
+	f.requests[operation] = append(f.requests[operation], input)
+	messages := f.messages
+	f.messages = nil
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	switch operation {
+	case "ReceiveMessage":
+		json.NewEncoder(w).Encode(map[string]interface{}{"Messages": messages})
+	default:
+		w.Write([]byte("{}"))
+	}
+}
+
+func (f *fakeSQS) calls(operation string) []map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[operation]
+}
+
+func newTestSQSSource(t *testing.T, fake *fakeSQS, config SQSSourceConfig) *SQSSource {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config.Endpoint = server.URL
+	config.AWSConfig = &aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("key", "secret", "")}
+	source, err := NewSQSSourceFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewSQSSourceFromConfig() failed: %v", err)
+	}
+	return source
+}
+
+func TestSQSSource_CustomEndpoint(t *testing.T) {
+	fake := newFakeSQS()
+	fake.messages = []map[string]interface{}{
+		{"MessageId": "m-1", "ReceiptHandle": "r-1", "Body": `{"payload":{"id":1}}`},
+	}
+	source := newTestSQSSource(t, fake, SQSSourceConfig{QueueURL: "http://localhost/queue/events"})
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].ID != "m-1" {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+
+	if err := source.Ack(context.Background(), messages[0]); err != nil {
+		t.Fatalf("Ack() failed: %v", err)
+	}
+	deletes := fake.calls("DeleteMessage")
+	if len(deletes) != 1 || deletes[0]["ReceiptHandle"] != "r-1" {
+		t.Errorf("Expected message deleted via the custom endpoint, got %v", deletes)
+	}
+}
+
+func TestSQSSource_ReceiveParameters(t *testing.T) {
+	fake := newFakeSQS()
+	source := newTestSQSSource(t, fake, SQSSourceConfig{
+		QueueURL:          "http://localhost/queue/events",
+		MaxMessages:       10,
+		VisibilityTimeout: 90 * time.Second,
+		WaitTime:          5 * time.Second,
+	})
+
+	if _, err := source.Receive(context.Background()); err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+
+	receive := fake.calls("ReceiveMessage")[0]
+	if receive["MaxNumberOfMessages"] != float64(10) || receive["VisibilityTimeout"] != float64(90) || receive["WaitTimeSeconds"] != float64(5) {
+		t.Errorf("Unexpected receive parameters %v", receive)
+	}
+}
+
+func TestSQSSourceConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SQSSourceConfig
+	}{
+		{"too many messages", SQSSourceConfig{MaxMessages: 11}},
+		{"negative messages", SQSSourceConfig{MaxMessages: -1}},
+		{"sub-second visibility", SQSSourceConfig{VisibilityTimeout: time.Millisecond}},
+		{"visibility over 12h", SQSSourceConfig{VisibilityTimeout: 13 * time.Hour}},
+		{"wait over 20s", SQSSourceConfig{WaitTime: 21 * time.Second}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			config.Client = &sqs.Client{}
+			if _, err := NewSQSSourceFromConfig(config); err == nil {
+				t.Error("Expected a validation error")
+			}
+		})
+	}
+}
+
+func TestWatcher_DeletesSQSMessagesInBatches(t *testing.T) {
+	fake := newFakeSQS()
+	for i := 1; i <= 12; i++ {
+		fake.messages = append(fake.messages, map[string]interface{}{
+			"MessageId":     fmt.Sprintf("m-%d", i),
+			"ReceiptHandle": fmt.Sprintf("r-%d", i),
+			"Body":          fmt.Sprintf(`{"payload":{"id":%d}}`, i),
+		})
+	}
+	source := newTestSQSSource(t, fake, SQSSourceConfig{QueueURL: "http://localhost/queue/events"})
+
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:  source,
+		Handler: func(ctx context.Context, event *EventMessage) error { return nil },
+	})
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	watcher.processBatch(context.Background(), messages)
+
+	if deletes := fake.calls("DeleteMessage"); len(deletes) != 0 {
+		t.Errorf("Expected no single deletes, got %d", len(deletes))
+	}
+	batches := fake.calls("DeleteMessageBatch")
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batch deletes, got %d", len(batches))
+	}
+	if n := len(batches[0]["Entries"].([]interface{})); n != 10 {
+		t.Errorf("Expected first batch of 10, got %d", n)
+	}
+	if n := len(batches[1]["Entries"].([]interface{})); n != 2 {
+		t.Errorf("Expected second batch of 2, got %d", n)
+	}
+}
+
+func TestWatcher_DeadLettersPoisonMessages(t *testing.T) {
+	fake := newFakeSQS()
+	fake.messages = []map[string]interface{}{{
+		"MessageId":     "m-1",
+		"ReceiptHandle": "r-1",
+		"Body":          `not json`,
+		"Attributes":    map[string]string{"ApproximateReceiveCount": "3"},
+	}}
+	source := newTestSQSSource(t, fake, SQSSourceConfig{QueueURL: "http://localhost/queue/events"})
+
+	var poisoned []*Message
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:          source,
+		MaxAttempts:     3,
+		DeadLetter:      source.DeadLetterTo("http://localhost/queue/events-dlq"),
+		OnPoisonMessage: func(message *Message, err error) { poisoned = append(poisoned, message) },
+	})
+
+	messages, err := source.Receive(context.Background())
+	if err != nil {
+		t.Fatalf("Receive() failed: %v", err)
+	}
+	if messages[0].ReceiveCount != 3 {
+		t.Fatalf("Expected receive count 3, got %d", messages[0].ReceiveCount)
+	}
+	watcher.processBatch(context.Background(), messages)
+
+	sends := fake.calls("SendMessage")
+	if len(sends) != 1 || sends[0]["QueueUrl"] != "http://localhost/queue/events-dlq" || sends[0]["MessageBody"] != "not json" {
+		t.Fatalf("Expected message forwarded to the DLQ, got %v", sends)
+	}
+	if len(poisoned) != 1 {
+		t.Errorf("Expected OnPoisonMessage to be called once, got %d", len(poisoned))
+	}
+	if len(fake.calls("DeleteMessageBatch")) != 1 {
+		t.Error("Expected the poison message to be deleted from the source queue")
+	}
+}
+
+func TestWatcher_CountsAttemptsLocally(t *testing.T) {
+	source := &recordingSource{}
+	var poisoned int
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:          source,
+		MaxAttempts:     2,
+		OnPoisonMessage: func(message *Message, err error) { poisoned++ },
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			return fmt.Errorf("always fails")
+		},
+	})
+
+	message := fifoMessage("1", "", 1)
+	watcher.processBatch(context.Background(), []*Message{message})
+	watcher.processBatch(context.Background(), []*Message{message})
+
+	if poisoned != 1 || len(source.nacked) != 1 || len(source.acked) != 1 {
+		t.Errorf("Expected one nack then dead-letter, got poisoned=%d acked=%v nacked=%v", poisoned, source.acked, source.nacked)
+	}
+}
+
+func TestWatcher_ForgetsStaleFailureCounts(t *testing.T) {
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:      &recordingSource{},
+		MaxAttempts: 5,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			return fmt.Errorf("always fails")
+		},
+	})
+
+	// A message that failed here long ago and was handled elsewhere since
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "", 1)})
+	watcher.failures["1"].failed = time.Now().Add(-failureTTL - time.Minute)
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("2", "", 2)})
+	if _, ok := watcher.failures["1"]; ok || len(watcher.failures) != 1 {
+		t.Errorf("Expected the stale failure count dropped, got %v", watcher.failures)
+	}
+}
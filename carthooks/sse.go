@@ -0,0 +1,190 @@
+package carthooks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sseInitialRetry = time.Second
+	sseMaxRetry     = 30 * time.Second
+)
+
+// sseEvent is a single event parsed from a text/event-stream
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// SubscribeSSE streams change events for a collection over Server-Sent Events
+// and calls handler for each one. It reconnects automatically with backoff,
+// resuming from the last received event via Last-Event-ID, and only returns
+// when ctx is cancelled. Handler errors are logged and do not stop the stream.
+func (c *Client) SubscribeSSE(ctx context.Context, appID, collectionID uint, filters map[string]interface{}, handler func(event *EventMessage) error) error {
+	path := fmt.Sprintf("/v1/apps/%d/collections/%d/stream", appID, collectionID)
+
+	params := map[string]string{}
+	if len(filters) > 0 {
+		filtersJSON, err := json.Marshal(filters)
+		if err != nil {
+			return fmt.Errorf("failed to marshal filters: %w", err)
+		}
+		params["filters"] = string(filtersJSON)
+	}
+
+	// Streams are long-lived, so don't apply the client's request timeout
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+
+	lastEventID := ""
+	retry := sseInitialRetry
+	for {
+		connected, serverRetry, err := c.streamSSE(ctx, streamClient, path, params, &lastEventID, handler)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if connected {
+			retry = sseInitialRetry
+		}
+		if serverRetry > 0 {
+			retry = serverRetry
+		}
+		c.logger.Warn("event stream disconnected, reconnecting", "retry_in", retry, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retry):
+		}
+
+		if serverRetry == 0 {
+			retry *= 2
+			if retry > sseMaxRetry {
+				retry = sseMaxRetry
+			}
+		}
+	}
+}
+
+// streamSSE opens one stream connection and reads events until it ends. It
+// reports whether the connection was established and any retry interval
+// requested by the server.
+func (c *Client) streamSSE(ctx context.Context, streamClient *http.Client, path string, params map[string]string, lastEventID *string, handler func(event *EventMessage) error) (bool, time.Duration, error) {
+	if err := c.EnsureValidToken(); err != nil {
+		return false, 0, fmt.Errorf("token refresh failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.ActiveEndpoint()+path, nil)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	q := req.URL.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	requestID := setRequestID(req)
+	c.propagateTrace(req)
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	c.logger.Debug("opening event stream", "url", c.redact.url(req.URL.String()), "request_id", requestID, "last_event_id", *lastEventID)
+
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return false, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, 0, fmt.Errorf("unexpected status %s: %s", resp.Status, string(body))
+	}
+
+	var serverRetry time.Duration
+	err = readSSE(resp.Body, func(event sseEvent, retry time.Duration) {
+		if retry > 0 {
+			serverRetry = retry
+			return
+		}
+		if event.ID != "" {
+			*lastEventID = event.ID
+		}
+		if event.Data == "" || (event.Event != "" && event.Event != "message") {
+			return
+		}
+
+		var message EventMessage
+		if err := json.Unmarshal([]byte(event.Data), &message); err != nil {
+			c.logger.Warn("failed to parse event", "event_id", event.ID, "error", err)
+			return
+		}
+		if err := handler(&message); err != nil {
+			c.logger.Warn("event handler failed", "event_id", event.ID, "error", err)
+		}
+	})
+	if err == nil {
+		err = io.EOF
+	}
+	return true, serverRetry, err
+}
+
+// readSSE parses a text/event-stream, calling emit for each complete event
+// and for each retry field
+func readSSE(r io.Reader, emit func(event sseEvent, retry time.Duration)) error {
+	reader := bufio.NewReader(r)
+	var event sseEvent
+	var data []string
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if len(data) > 0 || event.ID != "" {
+				event.Data = strings.Join(data, "\n")
+				emit(event, 0)
+			}
+			event = sseEvent{}
+			data = nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment / keep-alive
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				emit(sseEvent{}, time.Duration(ms)*time.Millisecond)
+			}
+		}
+	}
+}
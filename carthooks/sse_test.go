@@ -0,0 +1,92 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	stream := ": keep-alive\n" +
+		"retry: 2500\n" +
+		"id: 1\n" +
+		"data: {\"a\":\n" +
+		"data: 1}\n" +
+		"\n" +
+		"event: ping\n" +
+		"data: x\n" +
+		"\n"
+
+	var events []sseEvent
+	var retry time.Duration
+	readSSE(strings.NewReader(stream), func(event sseEvent, r time.Duration) {
+		if r > 0 {
+			retry = r
+			return
+		}
+		events = append(events, event)
+	})
+
+	if retry != 2500*time.Millisecond {
+		t.Errorf("Expected retry 2.5s, got %s", retry)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].ID != "1" || events[0].Data != "{\"a\":\n1}" {
+		t.Errorf("Unexpected first event %+v", events[0])
+	}
+	if events[1].Event != "ping" {
+		t.Errorf("Expected ping event, got %+v", events[1])
+	}
+}
+
+func TestSubscribeSSE_ResumesWithLastEventID(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/apps/1/collections/2/stream" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "retry: 10\n\n")
+		fmt.Fprintf(w, "id: evt-%d\ndata: {\"version\":\"1\",\"meta\":{\"event\":\"%s\"}}\n\n", attempt, EventCodeRecordUpdated)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := 0
+	err := client.SubscribeSSE(ctx, 1, 2, nil, func(event *EventMessage) error {
+		if event.Meta.Event != EventCodeRecordUpdated {
+			t.Errorf("Unexpected event %s", event.Meta.Event)
+		}
+		received++
+		if received == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(lastEventIDs) < 2 || lastEventIDs[0] != "" || lastEventIDs[1] != "evt-1" {
+		t.Errorf("Expected reconnect to resume from evt-1, got %v", lastEventIDs)
+	}
+}
@@ -0,0 +1,83 @@
+package carthooks
+
+import "fmt"
+
+// TenantRole is a user's role in a tenant
+type TenantRole string
+
+const (
+	TenantRoleOwner  TenantRole = "owner"
+	TenantRoleAdmin  TenantRole = "admin"
+	TenantRoleMember TenantRole = "member"
+	TenantRoleGuest  TenantRole = "guest"
+)
+
+// Tenant user status values reported in TenantUser.Status
+const (
+	TenantUserStatusActive  = "active"
+	TenantUserStatusInvited = "invited"
+)
+
+// TenantUser is a user's seat in the current tenant
+type TenantUser struct {
+	UserID   uint       `json:"user_id"`
+	Name     string     `json:"name"`
+	Email    string     `json:"email"`
+	Role     TenantRole `json:"role"`
+	Status   string     `json:"status"`
+	JoinedAt int64      `json:"joined_at"` // Unix timestamp in seconds; 0 while invited
+}
+
+// InviteUserRequest represents the request body for inviting a user to the tenant
+type InviteUserRequest struct {
+	Email string     `json:"email"`
+	Name  string     `json:"name,omitempty"`
+	Role  TenantRole `json:"role"`
+}
+
+// ListTenantUsers lists the users of the token's tenant, including pending
+// invitations. The result's data decodes into []TenantUser.
+func (c *Client) ListTenantUsers(pagination *PaginationOptions) *Result {
+	resp, err := c.makeRequest("GET", "/v1/tenant/users", nil, paginationParams(pagination))
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// InviteUser invites a user to the tenant by email. The result's data is
+// the TenantUser, with status TenantUserStatusInvited until they accept.
+func (c *Client) InviteUser(request *InviteUserRequest) *Result {
+	resp, err := c.makeRequest("POST", "/v1/tenant/users", request, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// RemoveUser removes a user from the tenant, freeing their seat, or
+// cancels their invitation
+func (c *Client) RemoveUser(userID uint) *Result {
+	path := fmt.Sprintf("/v1/tenant/users/%d", userID)
+
+	resp, err := c.makeRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// UpdateUserRole changes a user's role in the tenant
+func (c *Client) UpdateUserRole(userID uint, role TenantRole) *Result {
+	path := fmt.Sprintf("/v1/tenant/users/%d", userID)
+
+	resp, err := c.makeRequest("PUT", path, map[string]interface{}{"role": role}, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
@@ -0,0 +1,60 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_TenantUsers(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/tenant/users":
+			if r.URL.Query().Get("pagination[pageSize]") != "50" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"data":[{"user_id":1,"email":"owner@example.com","role":"owner","status":"active"}]}`)
+		case "POST /v1/tenant/users":
+			fmt.Fprint(w, `{"data":{"user_id":2,"email":"new@example.com","role":"member","status":"invited"}}`)
+		case "PUT /v1/tenant/users/2":
+			fmt.Fprint(w, `{"data":{"user_id":2,"email":"new@example.com","role":"admin","status":"invited"}}`)
+		case "DELETE /v1/tenant/users/2":
+			fmt.Fprint(w, `{"data":null}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var users []TenantUser
+	if err := client.ListTenantUsers(&PaginationOptions{PageSize: 50}).Decode(&users); err != nil || len(users) != 1 || users[0].Role != TenantRoleOwner {
+		t.Fatalf("ListTenantUsers() = %+v, %v", users, err)
+	}
+
+	var user TenantUser
+	if err := client.InviteUser(&InviteUserRequest{Email: "new@example.com", Role: TenantRoleMember}).Decode(&user); err != nil || user.Status != TenantUserStatusInvited {
+		t.Fatalf("InviteUser() = %+v, %v", user, err)
+	}
+	if bodies[1]["email"] != "new@example.com" || bodies[1]["role"] != "member" {
+		t.Errorf("Unexpected invitation %v", bodies[1])
+	}
+
+	if err := client.UpdateUserRole(user.UserID, TenantRoleAdmin).Decode(&user); err != nil || user.Role != TenantRoleAdmin {
+		t.Fatalf("UpdateUserRole() = %+v, %v", user, err)
+	}
+	if bodies[2]["role"] != "admin" {
+		t.Errorf("Unexpected role update %v", bodies[2])
+	}
+
+	if err := client.RemoveUser(user.UserID).Err(); err != nil {
+		t.Errorf("RemoveUser() returned %v", err)
+	}
+}
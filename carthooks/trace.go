@@ -0,0 +1,52 @@
+package carthooks
+
+import (
+	"context"
+	"net/http"
+)
+
+// TraceIDHeader carries a correlation ID set with WithTraceID
+const TraceIDHeader = "X-Trace-ID"
+
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying a trace or correlation ID, which
+// is sent as the X-Trace-ID header of requests made with the context
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceIDFromContext returns the trace ID set with WithTraceID, or ""
+func TraceIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// TracePropagator connects API requests to the caller's tracing system, e.g.
+// OpenTelemetry. Implementations must be safe for concurrent use.
+type TracePropagator interface {
+	// Inject adds the headers for the trace carried by ctx, e.g. traceparent
+	Inject(ctx context.Context, header http.Header)
+	// ServerTraceID is called with the trace_id the API returned for a
+	// request made with ctx, e.g. to add it to the active span's attributes
+	ServerTraceID(ctx context.Context, traceID string)
+}
+
+// propagateTrace adds the trace headers for the request's context to req
+func (c *Client) propagateTrace(req *http.Request) {
+	if id := TraceIDFromContext(req.Context()); id != "" {
+		req.Header.Set(TraceIDHeader, id)
+	}
+	if c.tracer != nil {
+		c.tracer.Inject(req.Context(), req.Header)
+	}
+}
+
+// reportServerTraceID passes the trace_id returned for resp to the trace
+// propagator
+func (c *Client) reportServerTraceID(resp *http.Response, traceID string) {
+	if c.tracer == nil || traceID == "" || resp.Request == nil {
+		return
+	}
+	c.tracer.ServerTraceID(resp.Request.Context(), traceID)
+}
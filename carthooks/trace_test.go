@@ -0,0 +1,64 @@
+package carthooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type spanKey struct{}
+
+type recordingPropagator struct {
+	serverTraceIDs []string
+}
+
+func (p *recordingPropagator) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanKey{}).(string); ok {
+		header.Set("traceparent", span)
+	}
+}
+
+func (p *recordingPropagator) ServerTraceID(ctx context.Context, traceID string) {
+	span, _ := ctx.Value(spanKey{}).(string)
+	p.serverTraceIDs = append(p.serverTraceIDs, span+"="+traceID)
+}
+
+func TestClient_TracePropagation(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		fmt.Fprint(w, `{"data":{},"trace_id":"server-trace"}`)
+	}))
+	defer server.Close()
+
+	propagator := &recordingPropagator{}
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Tracing: propagator})
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "00-abc-def-01")
+	ctx = WithTraceID(ctx, "corr-1")
+	ctx = WithRequestID(ctx, "req-1")
+	client.WithContext(ctx).GetApps()
+
+	if got := headers.Get("traceparent"); got != "00-abc-def-01" {
+		t.Errorf("traceparent = %q", got)
+	}
+	if got := headers.Get(TraceIDHeader); got != "corr-1" {
+		t.Errorf("%s = %q, want corr-1", TraceIDHeader, got)
+	}
+	if got := headers.Get(RequestIDHeader); got != "req-1" {
+		t.Errorf("%s = %q, want req-1", RequestIDHeader, got)
+	}
+	if len(propagator.serverTraceIDs) != 1 || propagator.serverTraceIDs[0] != "00-abc-def-01=server-trace" {
+		t.Errorf("ServerTraceID calls = %q", propagator.serverTraceIDs)
+	}
+
+	client.GetApps()
+	if len(propagator.serverTraceIDs) != 2 || propagator.serverTraceIDs[1] != "=server-trace" {
+		t.Errorf("ServerTraceID calls = %q", propagator.serverTraceIDs)
+	}
+	if got := headers.Get(TraceIDHeader); got != "" {
+		t.Errorf("%s = %q without a trace in context", TraceIDHeader, got)
+	}
+}
@@ -0,0 +1,240 @@
+package carthooks
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ProxyConfig routes the client's requests through an HTTP, HTTPS or
+// SOCKS5 proxy, independently of the HTTP_PROXY environment variables
+type ProxyConfig struct {
+	// URL of the proxy, e.g. "http://proxy.corp:3128" or
+	// "socks5://proxy.corp:1080"
+	URL string
+	// Username and Password authenticate with the proxy, overriding any
+	// credentials in URL
+	Username string
+	Password string
+	// NoProxy lists hosts reached directly: exact hosts, domain suffixes
+	// (".corp.example" or "corp.example" also match subdomains), IPs, CIDR
+	// ranges, or "*" for all
+	NoProxy []string
+}
+
+// TLSConfig customizes TLS for self-hosted Carthooks instances, e.g. ones
+// with certificates issued by an internal CA
+type TLSConfig struct {
+	// RootCAs, CAFile and CAPEM add trusted CA certificates to the system
+	// pool
+	RootCAs *x509.CertPool
+	CAFile  string
+	CAPEM   []byte
+	// InsecureSkipVerify disables certificate verification. Only use it in
+	// development.
+	InsecureSkipVerify bool
+	// MinVersion is the minimum TLS version (default tls.VersionTLS12)
+	MinVersion uint16
+	// ServerName overrides the name used to verify the server certificate
+	ServerName string
+
+	// Certificates, or the PEM encoded CertFile and KeyFile, are presented
+	// to servers that require client certificates (mutual TLS)
+	Certificates []tls.Certificate
+	CertFile     string
+	KeyFile      string
+}
+
+// build returns the tls.Config for c
+func (c *TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         c.MinVersion,
+		ServerName:         c.ServerName,
+		RootCAs:            c.RootCAs,
+		Certificates:       c.Certificates,
+	}
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = append(append([]tls.Certificate(nil), c.Certificates...), certificate)
+	}
+
+	if c.CAFile != "" || len(c.CAPEM) > 0 {
+		if config.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			config.RootCAs = pool
+		} else {
+			config.RootCAs = config.RootCAs.Clone()
+		}
+
+		if c.CAFile != "" {
+			pem, err := os.ReadFile(c.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in CA file %s", c.CAFile)
+			}
+		}
+		if len(c.CAPEM) > 0 && !config.RootCAs.AppendCertsFromPEM(c.CAPEM) {
+			return nil, fmt.Errorf("no certificates found in CAPEM")
+		}
+	}
+	return config, nil
+}
+
+// PoolConfig tunes connection reuse for high request rates. Zero values
+// keep the http.DefaultTransport settings.
+type PoolConfig struct {
+	// MaxIdleConns caps idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections kept to the API host; the
+	// Go default of 2 forces new TLS handshakes under concurrent load
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps all connections to the API host
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer
+	IdleConnTimeout time.Duration
+	// KeepAlive is the TCP keep-alive probe interval
+	KeepAlive time.Duration
+	// DisableKeepAlives uses each connection for a single request
+	DisableKeepAlives bool
+}
+
+// apply sets the pool options on transport
+func (p *PoolConfig) apply(transport *http.Transport) {
+	if p.MaxIdleConns > 0 {
+		transport.MaxIdleConns = p.MaxIdleConns
+	}
+	if p.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = p.MaxIdleConnsPerHost
+	}
+	if p.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = p.MaxConnsPerHost
+	}
+	if p.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = p.IdleConnTimeout
+	}
+	if p.KeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: p.KeepAlive}
+		transport.DialContext = dialer.DialContext
+	}
+	transport.DisableKeepAlives = p.DisableKeepAlives
+}
+
+// errorTransport fails every request with a configuration error, since
+// NewClient cannot return one
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// newTransport builds the client's transport from the connection options
+// in config, or returns config.Transport if none are set
+func newTransport(config *ClientConfig) http.RoundTripper {
+	if config.Proxy == nil && config.TLS == nil && config.Pool == nil {
+		return config.Transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Proxy != nil {
+		transport.Proxy = config.Proxy.proxyFunc()
+	}
+	if config.Pool != nil {
+		config.Pool.apply(transport)
+	}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.build()
+		if err != nil {
+			return errorTransport{fmt.Errorf("invalid TLS configuration: %w", err)}
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// proxyFunc returns an http.Transport Proxy function for the config
+func (p *ProxyConfig) proxyFunc() func(*http.Request) (*url.URL, error) {
+	proxyURL, err := url.Parse(p.URL)
+	if err == nil && (proxyURL.Scheme == "" || proxyURL.Host == "") {
+		err = fmt.Errorf("missing scheme or host")
+	}
+	if err != nil {
+		err = fmt.Errorf("invalid proxy URL %q: %w", p.URL, err)
+		return func(*http.Request) (*url.URL, error) { return nil, err }
+	}
+	if p.Username != "" {
+		proxyURL.User = url.UserPassword(p.Username, p.Password)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if p.bypass(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}
+}
+
+// bypass reports whether target matches the NoProxy list
+func (p *ProxyConfig) bypass(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	ip := net.ParseIP(host)
+
+	for _, entry := range p.NoProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		domain := strings.TrimPrefix(entryHost, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
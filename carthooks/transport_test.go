@@ -0,0 +1,205 @@
+package carthooks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingTransport counts the requests it forwards
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClient_CustomTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	NewClient(&ClientConfig{BaseURL: server.URL, Transport: transport}).GetApps()
+	if transport.requests != 1 {
+		t.Errorf("Expected request through the configured transport, got %d", transport.requests)
+	}
+
+	clientTransport := &countingTransport{}
+	httpClient := &http.Client{Transport: clientTransport}
+	client := NewClient(&ClientConfig{BaseURL: server.URL, HTTPClient: httpClient, Transport: transport})
+	client.GetApps()
+	if clientTransport.requests != 1 || transport.requests != 1 {
+		t.Errorf("Expected HTTPClient to take precedence, got %d/%d", clientTransport.requests, transport.requests)
+	}
+	if client.httpClient != httpClient {
+		t.Error("Expected the configured http.Client to be used as is")
+	}
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	var proxied []string
+	var auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		auth = r.Header.Get("Proxy-Authorization")
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer proxy.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL: "http://api.carthooks.test",
+		Proxy:   &ProxyConfig{URL: proxy.URL, Username: "user", Password: "pass"},
+	})
+	if result := client.GetApps(); !result.Success {
+		t.Fatalf("Expected request through the proxy, got %+v", result)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://api.carthooks.test/v1/apps" {
+		t.Errorf("Unexpected proxied requests %v", proxied)
+	}
+	if auth != "Basic dXNlcjpwYXNz" {
+		t.Errorf("Expected proxy credentials, got %q", auth)
+	}
+
+	client = NewClient(&ClientConfig{BaseURL: "http://api.carthooks.test", Proxy: &ProxyConfig{URL: "://bad"}})
+	if result := client.GetApps(); result.Success || !strings.Contains(result.Error, "invalid proxy URL") {
+		t.Errorf("Expected invalid proxy URL error, got %+v", result)
+	}
+}
+
+func TestProxyConfig_NoProxy(t *testing.T) {
+	config := &ProxyConfig{NoProxy: []string{"localhost", ".corp.example", "internal.example:8443", "10.0.0.0/8", "192.168.1.5"}}
+
+	tests := []struct {
+		target string
+		bypass bool
+	}{
+		{"http://localhost/v1", true},
+		{"https://api.corp.example/v1", true},
+		{"https://corp.example/v1", true},
+		{"https://notcorp.example/v1", false},
+		{"https://internal.example:8443/v1", true},
+		{"https://internal.example/v1", false},
+		{"http://10.1.2.3/v1", true},
+		{"http://192.168.1.5/v1", true},
+		{"https://api.carthooks.com/v1", false},
+	}
+	for _, tt := range tests {
+		target, _ := url.Parse(tt.target)
+		if got := config.bypass(target); got != tt.bypass {
+			t.Errorf("bypass(%s) = %v, want %v", tt.target, got, tt.bypass)
+		}
+	}
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, caPEM, 0o600)
+
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		success bool
+	}{
+		{"untrusted", nil, false},
+		{"CA PEM", &TLSConfig{CAPEM: caPEM}, true},
+		{"CA file", &TLSConfig{CAFile: caFile}, true},
+		{"insecure", &TLSConfig{InsecureSkipVerify: true}, true},
+		{"missing CA file", &TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, false},
+	}
+	for _, tt := range tests {
+		result := NewClient(&ClientConfig{BaseURL: server.URL, TLS: tt.tls}).GetApps()
+		if result.Success != tt.success {
+			t.Errorf("%s: expected success=%v, got %+v", tt.name, tt.success, result)
+		}
+	}
+}
+
+func TestNewClient_MutualTLS(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "inventory-sync"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	clientCAs := x509.NewCertPool()
+	clientCert, _ := x509.ParseCertificate(der)
+	clientCAs.AddCert(clientCert)
+
+	var subject string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = r.TLS.PeerCertificates[0].Subject.CommonName
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	without := NewClient(&ClientConfig{BaseURL: server.URL, TLS: &TLSConfig{InsecureSkipVerify: true}})
+	if result := without.GetApps(); result.Success {
+		t.Error("Expected the server to reject a client without a certificate")
+	}
+
+	with := NewClient(&ClientConfig{BaseURL: server.URL, TLS: &TLSConfig{
+		InsecureSkipVerify: true,
+		CertFile:           certFile,
+		KeyFile:            keyFile,
+	}})
+	if result := with.GetApps(); !result.Success || subject != "inventory-sync" {
+		t.Errorf("Expected mutual TLS to succeed, got %+v (subject %q)", result, subject)
+	}
+}
+
+func TestNewClient_Pool(t *testing.T) {
+	client := NewClient(&ClientConfig{Pool: &PoolConfig{
+		MaxIdleConns:        200,
+		MaxIdleConnsPerHost: 64,
+		MaxConnsPerHost:     128,
+		IdleConnTimeout:     2 * time.Minute,
+		KeepAlive:           15 * time.Second,
+	}})
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", client.httpClient.Transport)
+	}
+	if transport.MaxIdleConns != 200 || transport.MaxIdleConnsPerHost != 64 || transport.MaxConnsPerHost != 128 || transport.IdleConnTimeout != 2*time.Minute {
+		t.Errorf("Unexpected pool settings %+v", transport)
+	}
+	if transport.TLSHandshakeTimeout == 0 || transport.Proxy == nil {
+		t.Error("Expected unset options to keep the http.DefaultTransport settings")
+	}
+}
@@ -30,21 +30,46 @@ type EventMessage struct {
 	Version string           `json:"version"`
 	Meta    EventMessageMeta `json:"meta"`
 	Payload any              `json:"payload"`
+
+	// Signature is set when the delivery's signature was verified, e.g. by
+	// WebhookHandler or a watcher with a SigningSecret
+	Signature *EventSignature `json:"-"`
+}
+
+// Record returns the payload as a map, or nil if it is not a JSON object
+func (e *EventMessage) Record() map[string]interface{} {
+	record, _ := e.Payload.(map[string]interface{})
+	return record
 }
 
 type EventCode string
 
 const (
-	EventCodeRecordCreated EventCode = "collection.item.created"
-	EventCodeRecordUpdated EventCode = "collection.item.updated"
+	EventCodeRecordCreated     EventCode = "collection.item.created"
+	EventCodeRecordUpdated     EventCode = "collection.item.updated"
+	EventCodeRecordDeleted     EventCode = "collection.item.deleted"
+	EventCodeFieldChanged      EventCode = "collection.item.field_changed"
+	EventCodeCommentAdded      EventCode = "collection.item.comment_added"
+	EventCodeWorkflowStarted   EventCode = "workflow.started"
+	EventCodeWorkflowCompleted EventCode = "workflow.completed"
+	EventCodeWorkflowFailed    EventCode = "workflow.failed"
 )
 
+// Is reports whether the event has the given code
+func (e *EventMessage) Is(code EventCode) bool {
+	return e.Meta.Event == code
+}
+
 type EventMessageMeta struct {
 	TenantID     uint      `json:"tenant_id"`
 	CollectionID uint      `json:"collection_id"`
 	Event        EventCode `json:"event"`
 	TriggerType  string    `json:"trigger_type"`
 	TriggerName  string    `json:"trigger_name,omitempty"`
+	// Timestamp (Unix seconds) and Nonce identify a delivery for replay
+	// protection; see ReplayGuard
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Nonce     string `json:"nonce,omitempty"`
 }
 
 func (e *EventMessageMeta) ToMap() map[string]string {
@@ -109,6 +134,21 @@ type ConnectionUsage struct {
 	CreatedAt    string `json:"created_at"`
 }
 
+// UsageGranularity is the bucket size of aggregated connection usage
+type UsageGranularity string
+
+const (
+	UsageGranularityHour  UsageGranularity = "hour"
+	UsageGranularityDay   UsageGranularity = "day"
+	UsageGranularityMonth UsageGranularity = "month"
+)
+
+// ConnectionUsagePoint is a connection's total usage over one period
+type ConnectionUsagePoint struct {
+	PeriodStart int64 `json:"period_start"` // Unix timestamp in seconds
+	Usage       int64 `json:"usage"`
+}
+
 // CreateConnectionRequest represents the request body for creating a connection
 type CreateConnectionRequest struct {
 	HookletID    string `json:"hooklet_id"`
@@ -165,6 +205,8 @@ type OAuthTokenRequest struct {
 	Code            string `json:"code,omitempty"`
 	RedirectURI     string `json:"redirect_uri,omitempty"`
 	RefreshToken    string `json:"refresh_token,omitempty"`
+	// ConnectionID requests a token scoped to a connection's tenant
+	ConnectionID uint `json:"connection_id,omitempty"`
 }
 
 // OAuthAuthorizeCodeRequest represents OAuth authorization code request
@@ -0,0 +1,100 @@
+package carthooks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// QuotaMetric names a metered resource
+type QuotaMetric string
+
+const (
+	QuotaMetricAPICalls           QuotaMetric = "api_calls"
+	QuotaMetricStorageBytes       QuotaMetric = "storage_bytes"
+	QuotaMetricRecords            QuotaMetric = "records"
+	QuotaMetricWatchSubscriptions QuotaMetric = "watch_subscriptions"
+)
+
+// TenantUsage is the tenant's consumption over a period
+type TenantUsage struct {
+	PeriodStart        int64 `json:"period_start"` // Unix timestamp in seconds
+	PeriodEnd          int64 `json:"period_end"`   // Unix timestamp in seconds
+	APICalls           int64 `json:"api_calls"`
+	StorageBytes       int64 `json:"storage_bytes"`
+	Records            int64 `json:"records"`
+	WatchSubscriptions int64 `json:"watch_subscriptions"`
+}
+
+// Quota is the current use and limit of one metric
+type Quota struct {
+	Metric QuotaMetric `json:"metric"`
+	Used   int64       `json:"used"`
+	// Limit is the most the plan allows; 0 means unlimited
+	Limit    int64 `json:"limit"`
+	ResetsAt int64 `json:"resets_at,omitempty"` // Unix timestamp in seconds; 0 for quotas that don't reset
+}
+
+// Remaining returns how much of the quota is left, or -1 if it is unlimited
+func (q *Quota) Remaining() int64 {
+	if q.Limit == 0 {
+		return -1
+	}
+	if q.Used >= q.Limit {
+		return 0
+	}
+	return q.Limit - q.Used
+}
+
+// QuotaStatus is the tenant's quotas
+type QuotaStatus struct {
+	Quotas []Quota `json:"quotas"`
+}
+
+// Quota returns the quota of a metric, or nil if the tenant has none
+func (s *QuotaStatus) Quota(metric QuotaMetric) *Quota {
+	for i := range s.Quotas {
+		if s.Quotas[i].Metric == metric {
+			return &s.Quotas[i]
+		}
+	}
+	return nil
+}
+
+// Check returns an error matching ErrQuotaExceeded if using n more of
+// metric would exceed its quota, e.g. before a bulk create. Metrics without
+// a quota pass.
+func (s *QuotaStatus) Check(metric QuotaMetric, n int64) error {
+	quota := s.Quota(metric)
+	if quota == nil || quota.Limit == 0 || quota.Used+n <= quota.Limit {
+		return nil
+	}
+	return fmt.Errorf("%w: %s needs %d, %d of %d left", ErrQuotaExceeded, metric, n, quota.Remaining(), quota.Limit)
+}
+
+// GetTenantUsage returns the tenant's consumption from from (inclusive) to
+// to (exclusive). The result's data decodes into TenantUsage.
+func (c *Client) GetTenantUsage(from, to time.Time) *Result {
+	params := map[string]string{
+		"from": strconv.FormatInt(from.Unix(), 10),
+		"to":   strconv.FormatInt(to.Unix(), 10),
+	}
+
+	resp, err := c.makeRequest("GET", "/v1/tenant/usage", nil, params)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// GetQuotaStatus returns the tenant's current use against its plan's
+// limits. The result's data decodes into QuotaStatus.
+func (c *Client) GetQuotaStatus() *Result {
+	resp, err := c.makeRequest("GET", "/v1/tenant/quotas", nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
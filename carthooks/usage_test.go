@@ -0,0 +1,67 @@
+package carthooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_GetTenantUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/v1/tenant/usage" || query.Get("from") != "1700000000" || query.Get("to") != "1700086400" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"data":{"period_start":1700000000,"period_end":1700086400,"api_calls":5400,"records":120}}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var usage TenantUsage
+	from := time.Unix(1700000000, 0)
+	if err := client.GetTenantUsage(from, from.Add(24*time.Hour)).Decode(&usage); err != nil {
+		t.Fatalf("Decode() returned %v", err)
+	}
+	if usage.APICalls != 5400 || usage.Records != 120 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+}
+
+func TestQuotaStatus_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"quotas":[{"metric":"records","used":9900,"limit":10000},{"metric":"api_calls","used":50,"limit":0}]}}`)
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var status QuotaStatus
+	if err := client.GetQuotaStatus().Decode(&status); err != nil {
+		t.Fatalf("Decode() returned %v", err)
+	}
+
+	if got := status.Quota(QuotaMetricRecords).Remaining(); got != 100 {
+		t.Errorf("Remaining() = %d, want 100", got)
+	}
+	if got := status.Quota(QuotaMetricAPICalls).Remaining(); got != -1 {
+		t.Errorf("Remaining() of unlimited quota = %d, want -1", got)
+	}
+	if err := status.Check(QuotaMetricRecords, 100); err != nil {
+		t.Errorf("Check(100) returned %v", err)
+	}
+	if err := status.Check(QuotaMetricRecords, 101); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Check(101) = %v, want ErrQuotaExceeded", err)
+	}
+	if err := status.Check(QuotaMetricStorageBytes, 1<<40); err != nil {
+		t.Errorf("Check() of a metric without quota returned %v", err)
+	}
+}
+
+func TestAPIError_QuotaExceeded(t *testing.T) {
+	err := &APIError{StatusCode: http.StatusForbidden, Code: ErrorCodeQuotaExceeded, Message: "record limit reached"}
+	if !errors.Is(err, ErrQuotaExceeded) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected %v to match ErrQuotaExceeded and ErrForbidden", err)
+	}
+}
@@ -0,0 +1,85 @@
+package vcr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/token":
+			fmt.Fprint(w, `{"data":{"access_token":"live-access-token","refresh_token":"live-refresh-token","expires_in":3600}}`)
+		case "/v1/apps/1/collections/2/items/3":
+			fmt.Fprint(w, `{"data":{"id":3,"title":"Widget"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "items.json")
+
+	// Record against the live server
+	recorder, err := New(Config{Path: path, Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	client := carthooks.NewClient(&carthooks.ClientConfig{
+		BaseURL:   server.URL,
+		Transport: recorder,
+		OAuth:     &carthooks.OAuthConfig{ClientID: "id", ClientSecret: "live-client-secret"},
+	})
+	if result := client.InitializeOAuth(); !result.Success {
+		t.Fatalf("InitializeOAuth() failed: %s", result.Error)
+	}
+	if result := client.GetItemByID(1, 2, 3, nil); !result.Success {
+		t.Fatalf("GetItemByID() failed: %s", result.Error)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	server.Close()
+
+	data, _ := os.ReadFile(path)
+	for _, secret := range []string{"live-access-token", "live-refresh-token", "live-client-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %s to be redacted from the cassette:\n%s", secret, data)
+		}
+	}
+
+	// Replay with the server gone
+	replayer, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	client = carthooks.NewClient(&carthooks.ClientConfig{
+		BaseURL:   server.URL,
+		Transport: replayer,
+		OAuth:     &carthooks.OAuthConfig{ClientID: "id", ClientSecret: "other-secret"},
+	})
+	if result := client.InitializeOAuth(); !result.Success {
+		t.Fatalf("Replayed InitializeOAuth() failed: %s", result.Error)
+	}
+	result := client.GetItemByID(1, 2, 3, nil)
+	if !result.Success || result.Data.(map[string]interface{})["title"] != "Widget" {
+		t.Fatalf("Unexpected replayed result %+v", result)
+	}
+
+	if _, err := replayer.RoundTrip(httptest.NewRequest("GET", server.URL+"/v1/apps", nil)); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("Expected ErrNoRecording for an unrecorded request, got %v", err)
+	}
+}
+
+func TestNew_MissingCassette(t *testing.T) {
+	if _, err := New(Config{Path: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("Expected replay mode to require a cassette")
+	}
+}
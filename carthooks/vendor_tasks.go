@@ -0,0 +1,85 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Vendor task status values reported in VendorTask.Status
+const (
+	VendorTaskStatusPending   = "pending"
+	VendorTaskStatusRunning   = "running"
+	VendorTaskStatusCompleted = "completed"
+	VendorTaskStatusFailed    = "failed"
+)
+
+// VendorTask is an asynchronous task a hooklet vendor runs on behalf of a
+// tenant, such as provisioning an installation. CreateConnectionRequest
+// links a connection to the task that set it up through VendorTaskID.
+type VendorTask struct {
+	TaskID    string                 `json:"task_id"`
+	HookletID string                 `json:"hooklet_id"`
+	TenantID  uint                   `json:"tenant_id"`
+	Status    string                 `json:"status"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	CreatedAt int64                  `json:"created_at"` // Unix timestamp in seconds
+	UpdatedAt int64                  `json:"updated_at"` // Unix timestamp in seconds
+}
+
+// Done reports whether the task has completed or failed
+func (t *VendorTask) Done() bool {
+	return t.Status == VendorTaskStatusCompleted || t.Status == VendorTaskStatusFailed
+}
+
+// CreateVendorTaskRequest represents the request body for creating a vendor task
+type CreateVendorTaskRequest struct {
+	HookletID string                 `json:"hooklet_id"`
+	TenantID  uint                   `json:"tenant_id,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+}
+
+// CompleteVendorTaskRequest represents the request body for finishing a vendor task
+type CompleteVendorTaskRequest struct {
+	// Status is VendorTaskStatusCompleted or VendorTaskStatusFailed
+	Status string                 `json:"status"`
+	Result map[string]interface{} `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// CreateVendorTask creates a vendor task. The result's data is the
+// VendorTask, whose TaskID is passed to CreateConnection as VendorTaskID.
+func (c *Client) CreateVendorTask(request *CreateVendorTaskRequest) *Result {
+	resp, err := c.makeRequest("POST", "/v1/vendor-tasks", request, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// GetVendorTask retrieves a vendor task
+func (c *Client) GetVendorTask(taskID string) *Result {
+	path := fmt.Sprintf("/v1/vendor-tasks/%s", url.PathEscape(taskID))
+
+	resp, err := c.makeRequest("GET", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// CompleteVendorTask finishes a vendor task with its outcome and result
+// payload
+func (c *Client) CompleteVendorTask(taskID string, request *CompleteVendorTaskRequest) *Result {
+	path := fmt.Sprintf("/v1/vendor-tasks/%s/complete", url.PathEscape(taskID))
+
+	resp, err := c.makeRequest("POST", path, request, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
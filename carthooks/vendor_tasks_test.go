@@ -0,0 +1,47 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_VendorTaskLifecycle(t *testing.T) {
+	var completed map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/vendor-tasks":
+			fmt.Fprint(w, `{"data":{"task_id":"task-1","hooklet_id":"h-1","status":"pending"}}`)
+		case "GET /v1/vendor-tasks/task-1":
+			fmt.Fprint(w, `{"data":{"task_id":"task-1","hooklet_id":"h-1","status":"running"}}`)
+		case "POST /v1/vendor-tasks/task-1/complete":
+			json.NewDecoder(r.Body).Decode(&completed)
+			fmt.Fprint(w, `{"data":{"task_id":"task-1","status":"completed","result":{"installed":true}}}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+
+	var task VendorTask
+	if err := client.CreateVendorTask(&CreateVendorTaskRequest{HookletID: "h-1"}).Decode(&task); err != nil || task.TaskID != "task-1" {
+		t.Fatalf("CreateVendorTask() = %+v, %v", task, err)
+	}
+	if err := client.GetVendorTask(task.TaskID).Decode(&task); err != nil || task.Status != VendorTaskStatusRunning || task.Done() {
+		t.Fatalf("GetVendorTask() = %+v, %v", task, err)
+	}
+
+	err := client.CompleteVendorTask(task.TaskID, &CompleteVendorTaskRequest{
+		Status: VendorTaskStatusCompleted,
+		Result: map[string]interface{}{"installed": true},
+	}).Decode(&task)
+	if err != nil || !task.Done() || task.Result["installed"] != true {
+		t.Fatalf("CompleteVendorTask() = %+v, %v", task, err)
+	}
+	if completed["status"] != "completed" || completed["result"].(map[string]interface{})["installed"] != true {
+		t.Errorf("Unexpected completion body %v", completed)
+	}
+}
@@ -0,0 +1,14 @@
+package carthooks
+
+// Version is the SDK version, sent in the User-Agent header
+const Version = "0.1.0"
+
+// userAgent returns the User-Agent header value, with the application's
+// suffix appended if set
+func userAgent(suffix string) string {
+	agent := "carthooks-sdk-go/v" + Version
+	if suffix != "" {
+		agent += " " + suffix
+	}
+	return agent
+}
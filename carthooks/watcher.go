@@ -3,14 +3,15 @@ package carthooks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
-	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
 // WatcherConfig holds configuration for the watcher
@@ -22,15 +23,151 @@ type WatcherConfig struct {
 	SQSQueueURL  string
 	AWSRegion    string
 	Filters      map[string]interface{}
-	Handler      func(ctx interface{}, record map[string]interface{})
+	// Handler is called for each event. Returning an error leaves the
+	// message unacknowledged so the source redelivers it.
+	Handler Handler
+	// Middleware wraps Handler, outermost first. It does not apply to
+	// BatchHandler.
+	Middleware []Middleware
+	// BatchHandler, if set, replaces Handler and receives each received
+	// batch at once, e.g. to commit it in one transaction. An error leaves
+	// the whole batch for redelivery.
+	BatchHandler func(ctx context.Context, events []EventMessage) error
+	// OnError is called for every message that fails processing, including
+	// handler panics (as *PanicError), and with a nil message for receive
+	// errors
+	OnError func(err error, message *Message)
+
+	// SQSClient, AWSConfig and SQSEndpoint customize the default SQS
+	// source, e.g. for LocalStack or an assumed IAM role. See
+	// SQSSourceConfig.
+	SQSClient   *sqs.Client
+	AWSConfig   *aws.Config
+	SQSEndpoint string
+	// SQSMaxMessages, SQSVisibilityTimeout and SQSWaitTime tune the default
+	// SQS source's receive calls (defaults 5, 5 minutes and 20 seconds)
+	SQSMaxMessages       int
+	SQSVisibilityTimeout time.Duration
+	SQSWaitTime          time.Duration
+
+	// Source delivers messages to the watcher; defaults to an SQSSource for
+	// SQSQueueURL
+	Source MessageSource
+	// EndpointURL and EndpointType override the endpoint registered with
+	// StartWatchData, e.g. when events are routed to Source by other means
+	EndpointURL  string
+	EndpointType string
+
+	// Age is the watch registration lifetime in seconds (default 5 days)
+	Age int
+	// RenewalMargin is how long before expiry the watch is re-registered
+	// (default 1 hour). It must be shorter than Age; a default margin
+	// longer than half the registration's lifetime is cut to half of it.
+	RenewalMargin time.Duration
+	// OnRenewal is called after each renewal attempt with the new expiry
+	OnRenewal func(expiresAt time.Time, err error)
+
+	// MessageGroupBy is the record field Carthooks uses as the
+	// MessageGroupId when delivering to a FIFO queue (default: item id)
+	MessageGroupBy string
+	// ParallelGroups processes different message groups of a batch
+	// concurrently; messages within a group are always handled in order.
+	// Concurrency, if set, bounds how many groups run at once.
+	ParallelGroups bool
+
+	// Concurrency is the number of workers processing messages of a batch
+	// (default 1). Messages of the same group are never processed
+	// concurrently.
+	Concurrency int
+	// OrderByRecord keeps messages for the same record in order when
+	// processing concurrently, by treating the record ID as the group of
+	// messages without a GroupID
+	OrderByRecord bool
+
+	// VisibilityExtension, when set, keeps a message hidden from other
+	// consumers while its handler runs by extending its visibility timeout
+	// by this amount every half period. Only sources implementing
+	// VisibilityExtender (e.g. SQS) support it.
+	VisibilityExtension time.Duration
+
+	// MaxAttempts is how many times a message may fail before it is treated
+	// as poison: passed to DeadLetter and OnPoisonMessage and removed from
+	// the source. Zero retries forever.
+	MaxAttempts int
+	// DeadLetter receives poison messages, e.g. SQSSource.DeadLetterTo
+	DeadLetter DeadLetterSink
+	// OnPoisonMessage is called for each poison message
+	OnPoisonMessage func(message *Message, err error)
+
+	// Backfill delivers every existing record matching Filters to the
+	// handler as a record created event with TriggerTypeBackfill before
+	// consuming live changes. It is skipped when resuming from a checkpoint.
+	Backfill bool
+	// BackfillPageSize is the QueryItems page size for backfill (default 100)
+	BackfillPageSize int
+
+	// Checkpoints persists the time of the newest processed event, keyed by
+	// WatcherID, so a restarted watcher registers from there instead of now
+	Checkpoints CheckpointStore
+
+	// Metrics receives instrumentation, e.g. from promwatcher.New
+	Metrics WatcherMetrics
+	// Logger receives the watcher's logs (default slog.Default())
+	Logger *slog.Logger
+
+	// DedupeStore skips messages that were already processed, e.g. SQS
+	// redeliveries (default: in-memory store of the last 1000 messages)
+	DedupeStore DedupeStore
+
+	// DrainTimeout is how long Run waits for in-flight messages to finish
+	// after it is stopped (default 30 seconds)
+	DrainTimeout time.Duration
+
+	// SigningSecret, if set, rejects messages without a valid
+	// WebhookSignatureHeader attribute; see VerifyEventSignature
+	SigningSecret string
+
+	// InvalidateCache evicts Client's cached copy of the item each record
+	// event refers to before the event is handled. With RefreshCache,
+	// changed items are refetched into the cache instead.
+	InvalidateCache bool
+	RefreshCache    bool
 }
 
+const (
+	defaultWatchAge      = 432000 // 5 days
+	defaultRenewalMargin = time.Hour
+	renewalRetryInterval = time.Minute
+	defaultDrainTimeout  = 30 * time.Second
+	reauthMinBackoff     = time.Second
+	reauthMaxBackoff     = 30 * time.Second
+)
+
+// ErrDrainTimeout is returned by Run when in-flight messages were still being
+// processed when the drain timeout elapsed
+var ErrDrainTimeout = errors.New("watcher drain timed out")
+
 // Watcher represents a data change watcher
 type Watcher struct {
 	config    *WatcherConfig
-	sqsClient *sqs.Client
+	source    MessageSource
+	mu        sync.Mutex
+	cancel    context.CancelFunc
 	running   bool
-	stopChan  chan bool
+	expiresAt time.Time
+	health    watcherHealth
+
+	// startTime is the WatchStartTime of the next subscription
+	startTime    int64
+	checkpointMu sync.Mutex
+	checkpoint   int64
+	dedupe       DedupeStore
+	metrics      WatcherMetrics
+	logger       *slog.Logger
+
+	failuresMu sync.Mutex
+	failures   map[string]*failureCount
+	handler    Handler
 }
 
 // SQSMessageBody represents the expected SQS message structure
@@ -42,150 +179,691 @@ type SQSMessageBody struct {
 
 // NewWatcher creates a new watcher instance
 func NewWatcher(config *WatcherConfig) (*Watcher, error) {
-	// Load AWS configuration
-	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(),
-		awsConfig.WithRegion(config.AWSRegion),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	age := config.Age
+	if age <= 0 {
+		age = defaultWatchAge
+	}
+	if config.RenewalMargin >= time.Duration(age)*time.Second {
+		return nil, fmt.Errorf("renewal margin %s must be shorter than the watch age of %ds", config.RenewalMargin, age)
+	}
+
+	source := config.Source
+	if source == nil {
+		sqsSource, err := NewSQSSourceFromConfig(SQSSourceConfig{
+			QueueURL:  config.SQSQueueURL,
+			Region:    config.AWSRegion,
+			Client:    config.SQSClient,
+			AWSConfig: config.AWSConfig,
+			Endpoint:  config.SQSEndpoint,
+
+			MaxMessages:       config.SQSMaxMessages,
+			VisibilityTimeout: config.SQSVisibilityTimeout,
+			WaitTime:          config.SQSWaitTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		source = sqsSource
+	}
+
+	dedupe := config.DedupeStore
+	if dedupe == nil {
+		dedupe = NewMemoryDedupeStore(0)
 	}
 
-	sqsClient := sqs.NewFromConfig(cfg)
+	metrics := config.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
 
 	return &Watcher{
-		config:    config,
-		sqsClient: sqsClient,
-		running:   false,
-		stopChan:  make(chan bool),
+		config:  config,
+		source:  source,
+		dedupe:  dedupe,
+		metrics: metrics,
+		logger:  orDefaultLogger(config.Logger),
+		handler: chainHandler(config),
 	}, nil
 }
 
+// chainHandler wraps the configured handler in its middleware
+func chainHandler(config *WatcherConfig) Handler {
+	if config.Handler == nil {
+		return nil
+	}
+	return Chain(config.Handler, config.Middleware...)
+}
+
+// endpoint returns the endpoint to register with StartWatchData
+func (w *Watcher) endpoint() (string, string) {
+	if w.config.EndpointURL != "" {
+		return w.config.EndpointURL, w.config.EndpointType
+	}
+	if provider, ok := w.source.(EndpointProvider); ok {
+		return provider.Endpoint()
+	}
+	return "", ""
+}
+
 // Subscribe sets up the watch data subscription
 func (w *Watcher) Subscribe() error {
 	// Start watch data
 	watchName := fmt.Sprintf("watch-%d-%d", w.config.AppID, w.config.CollectionID)
 
+	age := w.config.Age
+	if age <= 0 {
+		age = defaultWatchAge
+	}
+
+	endpointURL, endpointType := w.endpoint()
+	if endpointURL == "" {
+		w.logger.Info("no endpoint to register, assuming events are routed to the source externally", "watch", watchName)
+		return nil
+	}
+
 	options := &WatchDataOptions{
-		EndpointURL:    w.config.SQSQueueURL,
-		EndpointType:   "sqs",
+		EndpointURL:    endpointURL,
+		EndpointType:   endpointType,
 		Name:           watchName,
 		AppID:          w.config.AppID,
 		CollectionID:   w.config.CollectionID,
 		Filters:        w.config.Filters,
-		Age:            432000, // 5 days
-		WatchStartTime: 0,
+		Age:            age,
+		WatchStartTime: w.startTime,
+		MessageGroupBy: w.config.MessageGroupBy,
 	}
 
+	registeredAt := time.Now()
 	result := w.config.Client.StartWatchData(options)
 	if !result.Success {
-		return fmt.Errorf("failed to start watch data: %s", result.Error)
+		return fmt.Errorf("failed to start watch data: %w", result.Err())
 	}
 
-	log.Printf("✅ Monitoring task registered successfully: %s", watchName)
+	// Prefer the expiry reported by the API over our own estimate
+	expiresAt := registeredAt.Add(time.Duration(age) * time.Second)
+	var info WatchInfo
+	if err := result.GetData(&info); err == nil && info.ExpiresAt > 0 {
+		expiresAt = info.ExpiresTime()
+	}
+
+	w.mu.Lock()
+	w.expiresAt = expiresAt
+	w.mu.Unlock()
+
+	// Only the first subscription resumes from the checkpoint; renewals
+	// continue from now
+	w.startTime = 0
+
+	w.logger.Info("watch registered", "watch", watchName)
 	return nil
 }
 
-// Run starts the watcher and begins listening for messages
-func (w *Watcher) Run() error {
+// subscribe calls Subscribe, and if the access token was rejected obtains a
+// new one and retries with backoff until it succeeds, fails for another
+// reason or ctx is cancelled
+func (w *Watcher) subscribe(ctx context.Context) error {
+	backoff := reauthMinBackoff
+	for {
+		err := w.Subscribe()
+		if !errors.Is(err, ErrUnauthorized) {
+			return err
+		}
+
+		w.logger.Warn("access token rejected, re-authorizing", "error", err)
+		if authErr := w.reauthorize(); authErr != nil {
+			w.logger.Error("re-authorization failed", "error", authErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, reauthMaxBackoff)
+	}
+}
+
+// reauthorize refreshes the client's OAuth token, falling back to a new
+// client credentials grant
+func (w *Watcher) reauthorize() error {
+	client := w.config.Client
+	if client.oauthConfig == nil {
+		return fmt.Errorf("OAuth configuration not provided")
+	}
+
+	if result := client.RefreshOAuthToken(); result.Success {
+		return nil
+	}
+	if result := client.InitializeOAuth(); !result.Success {
+		return fmt.Errorf("failed to obtain token: %s", result.Error)
+	}
+	return nil
+}
+
+// ExpiresAt returns when the current watch registration lapses
+func (w *Watcher) ExpiresAt() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.expiresAt
+}
+
+// renewLoop re-registers the watch before it expires until the watcher stops
+func (w *Watcher) renewLoop(ctx context.Context) {
+	margin := w.config.RenewalMargin
+	if margin <= 0 {
+		margin = defaultRenewalMargin
+	}
+
+	wait := renewalWait(w.ExpiresAt(), margin)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		err := w.subscribe(ctx)
+		if err != nil {
+			w.logger.Error("watch renewal failed", "error", err)
+			wait = renewalRetryInterval
+		} else {
+			w.logger.Info("watch renewed", "expires_at", w.ExpiresAt())
+			wait = renewalWait(w.ExpiresAt(), margin)
+		}
+
+		if w.config.OnRenewal != nil {
+			w.config.OnRenewal(w.ExpiresAt(), err)
+		}
+	}
+}
+
+// renewalWait returns how long to wait before renewing a registration
+// expiring at expiresAt. The margin is cut to half the remaining lifetime,
+// so a short-lived registration, e.g. a small Age with the default margin,
+// is renewed midway instead of immediately and over and over.
+func renewalWait(expiresAt time.Time, margin time.Duration) time.Duration {
+	remaining := time.Until(expiresAt)
+	if remaining <= 0 {
+		return 0
+	}
+	if margin > remaining/2 {
+		margin = remaining / 2
+	}
+	return remaining - margin
+}
+
+// Run subscribes and processes messages until ctx is cancelled or Stop is
+// called. On shutdown it stops receiving, waits up to DrainTimeout for
+// in-flight messages to finish, then closes the source. It returns nil after
+// a clean shutdown and ErrDrainTimeout if messages were still in flight; the
+// source is then closed once they finish, as they may still ack or nack.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
 	if w.running {
+		w.mu.Unlock()
 		return fmt.Errorf("watcher is already running")
 	}
+	runCtx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.running = true
+	w.mu.Unlock()
+
+	defer func() {
+		cancel()
+		w.mu.Lock()
+		w.running = false
+		w.cancel = nil
+		w.mu.Unlock()
+	}()
 
-	// Subscribe to watch data
-	if err := w.Subscribe(); err != nil {
+	// Subscribe to watch data, resuming from the last checkpoint
+	w.loadCheckpoint(runCtx)
+	resumed := w.startTime != 0
+	if err := w.subscribe(runCtx); err != nil {
 		return err
 	}
 
-	w.running = true
-	log.Printf("🎯 SQS mode running...")
+	// Deliver existing records before live changes, unless resuming where a
+	// previous run left off
+	if w.config.Backfill && !resumed {
+		if err := w.backfill(runCtx); err != nil {
+			return err
+		}
+	}
+	w.logger.Info("watcher running")
 
-	// Start SQS message polling
-	go w.pollSQSMessages()
+	// Start message polling
+	polling := make(chan struct{})
+	go func() {
+		defer close(polling)
+		w.pollMessages(runCtx)
+	}()
 
-	// Wait for stop signal
-	<-w.stopChan
-	w.running = false
-	log.Printf("🛑 Watcher stopped")
+	// Keep the watch registered past its Age
+	if !w.ExpiresAt().IsZero() {
+		go w.renewLoop(runCtx)
+	}
 
-	return nil
+	<-runCtx.Done()
+	w.logger.Info("watcher stopping, draining in-flight messages")
+
+	drainTimeout := w.config.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+
+	select {
+	case <-polling:
+		w.closeSource()
+		w.logger.Info("watcher stopped")
+		return nil
+	case <-time.After(drainTimeout):
+		w.logger.Warn("in-flight messages did not finish in time", "drain_timeout", drainTimeout)
+		go func() {
+			<-polling
+			w.closeSource()
+		}()
+		return ErrDrainTimeout
+	}
 }
 
-// Stop stops the watcher
-func (w *Watcher) Stop() {
-	if w.running {
-		w.stopChan <- true
+// closeSource closes the message source, logging any error
+func (w *Watcher) closeSource() {
+	if err := w.source.Close(); err != nil {
+		w.logger.Warn("failed to close message source", "error", err)
 	}
 }
 
-// pollSQSMessages continuously polls SQS for messages
-func (w *Watcher) pollSQSMessages() {
-	for w.running {
-		// Receive messages from SQS
-		result, err := w.sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-			QueueUrl:            aws.String(w.config.SQSQueueURL),
-			MaxNumberOfMessages: 5,
-			VisibilityTimeout:   300, // 5 minutes
-			WaitTimeSeconds:     20,  // Long polling
-		})
+// Stop signals a running watcher to shut down; Run returns once in-flight
+// messages are drained. It is safe to call more than once or concurrently.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
 
+// pollMessages receives and processes messages until ctx is cancelled. A
+// batch already received is processed to completion with a context that
+// outlives ctx, so acks are not lost on shutdown.
+func (w *Watcher) pollMessages(ctx context.Context) {
+	processCtx := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		messages, err := w.source.Receive(ctx)
 		if err != nil {
-			log.Printf("❌ Error receiving SQS messages: %v", err)
-			time.Sleep(5 * time.Second)
+			if ctx.Err() != nil {
+				return
+			}
+			w.logger.Error("failed to receive messages", "error", err)
+			w.health.pollFailed()
+			w.metrics.PollError()
+			w.reportError(err, nil)
+			sleepContext(ctx, 5*time.Second)
+			continue
+		}
+
+		w.health.polled(len(messages))
+		if len(messages) == 0 {
+			w.metrics.EmptyPoll()
+		} else {
+			w.metrics.MessagesReceived(len(messages))
+		}
+
+		w.processBatch(processCtx, messages)
+
+		// Short sleep to prevent excessive polling
+		if len(messages) == 0 {
+			sleepContext(ctx, 1*time.Second)
+		}
+	}
+}
+
+// sleepContext sleeps for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+// processBatch hands a received batch to BatchHandler or, without one,
+// handles it group by group using a pool of Concurrency workers (or one per
+// group with ParallelGroups)
+func (w *Watcher) processBatch(ctx context.Context, messages []*Message) {
+	acks := w.newAcker()
+	defer acks.flush(ctx)
+
+	if w.config.BatchHandler != nil {
+		w.processEvents(ctx, messages, acks)
+		return
+	}
+
+	key := messageGroupID
+	if w.config.OrderByRecord {
+		key = messageRecordID
+	}
+	groups := groupMessages(messages, key)
+
+	workers := w.config.Concurrency
+	if workers <= 0 {
+		workers = 1
+		if w.config.ParallelGroups {
+			workers = len(groups)
+		}
+	}
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	if workers <= 1 {
+		for _, group := range groups {
+			w.processGroup(ctx, group, acks)
+		}
+		return
+	}
+
+	queue := make(chan *messageGroup)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				w.processGroup(ctx, group, acks)
+			}
+		}()
+	}
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// processGroup handles a group's messages in order. When a message of an
+// ordered group fails, the rest of the group is nacked so they are
+// redelivered after it rather than overtaking it.
+func (w *Watcher) processGroup(ctx context.Context, group *messageGroup, acks *acker) {
+	for i, message := range group.messages {
+		if w.seen(ctx, message) {
+			w.logger.Info("skipping duplicate message", "message_id", dedupeID(message))
+			acks.add(ctx, message)
 			continue
 		}
 
-		// Process each message
-		for _, message := range result.Messages {
-			if err := w.processMessage(message); err != nil {
-				log.Printf("⚠️ Message processing failed: %v", err)
+		if err := w.handle(ctx, message); err != nil {
+			w.logger.Warn("message processing failed", "message_id", message.ID, "error", err)
+			w.reportError(err, message)
+			if w.fail(ctx, message, err, acks) {
+				// Dead-lettered, so it no longer holds back its group
 				continue
 			}
+			if group.id != "" {
+				for _, rest := range group.messages[i+1:] {
+					w.nack(ctx, rest)
+				}
+				return
+			}
+			continue
+		}
+
+		// Acknowledge message after successful processing
+		w.mark(ctx, message)
+		w.clearFailures(message)
+		w.metrics.MessageProcessed()
+		acks.add(ctx, message)
+	}
+}
 
-			// Delete message after successful processing
-			_, err := w.sqsClient.DeleteMessage(context.TODO(), &sqs.DeleteMessageInput{
-				QueueUrl:      aws.String(w.config.SQSQueueURL),
-				ReceiptHandle: message.ReceiptHandle,
-			})
-			if err != nil {
-				log.Printf("⚠️ Failed to delete message: %v", err)
+// seen reports whether the message was already processed. If the dedupe
+// store is unavailable the message is processed rather than dropped.
+func (w *Watcher) seen(ctx context.Context, message *Message) bool {
+	id := dedupeID(message)
+	if id == "" {
+		return false
+	}
+	seen, err := w.dedupe.Seen(ctx, id)
+	if err != nil {
+		w.logger.Warn("failed to check dedupe store", "error", err)
+		return false
+	}
+	return seen
+}
+
+// mark records the message as processed in the dedupe store
+func (w *Watcher) mark(ctx context.Context, message *Message) {
+	id := dedupeID(message)
+	if id == "" {
+		return
+	}
+	if err := w.dedupe.Mark(ctx, id); err != nil {
+		w.logger.Warn("failed to record processed message", "error", err)
+	}
+}
+
+// acker collects the acknowledgements of a batch so sources implementing
+// BatchAcker can acknowledge them in as few calls as possible
+type acker struct {
+	watcher *Watcher
+	batch   BatchAcker
+
+	mu      sync.Mutex
+	pending []*Message
+	done    []*Message
+}
+
+func (w *Watcher) newAcker() *acker {
+	batch, _ := w.source.(BatchAcker)
+	return &acker{watcher: w, batch: batch}
+}
+
+// add acknowledges message, or queues it until flush for batch sources
+func (a *acker) add(ctx context.Context, message *Message) {
+	a.mu.Lock()
+	a.done = append(a.done, message)
+	if a.batch != nil {
+		a.pending = append(a.pending, message)
+	}
+	a.mu.Unlock()
+
+	if a.batch == nil {
+		a.watcher.ack(ctx, message)
+	}
+}
+
+// flush acknowledges the queued messages and advances the checkpoint past
+// every message acknowledged in the batch
+func (a *acker) flush(ctx context.Context) {
+	a.mu.Lock()
+	pending := a.pending
+	done := a.done
+	a.pending = nil
+	a.done = nil
+	a.mu.Unlock()
+
+	if len(pending) > 0 {
+		if err := a.batch.AckBatch(ctx, pending); err != nil {
+			a.watcher.logger.Warn("failed to acknowledge messages", "count", len(pending), "error", err)
+			return
+		}
+	}
+	a.watcher.saveCheckpoint(ctx, done)
+}
+
+func (w *Watcher) ack(ctx context.Context, message *Message) {
+	if err := w.source.Ack(ctx, message); err != nil {
+		w.logger.Warn("failed to acknowledge message", "message_id", message.ID, "error", err)
+	}
+}
+
+func (w *Watcher) nack(ctx context.Context, message *Message) {
+	if err := w.source.Nack(ctx, message); err != nil {
+		w.logger.Warn("failed to nack message", "message_id", message.ID, "error", err)
+	}
+}
+
+// handle processes a message, extending its visibility while the handler
+// runs when VisibilityExtension is configured
+func (w *Watcher) handle(ctx context.Context, message *Message) error {
+	extender, ok := w.source.(VisibilityExtender)
+	if ok && w.config.VisibilityExtension > 0 {
+		stop := make(chan struct{})
+		defer close(stop)
+		go w.extendVisibility(ctx, extender, message, stop)
+	}
+	return w.processMessage(ctx, message)
+}
+
+// extendVisibility periodically extends a message's visibility until stop
+// is closed
+func (w *Watcher) extendVisibility(ctx context.Context, extender VisibilityExtender, message *Message, stop <-chan struct{}) {
+	extension := w.config.VisibilityExtension
+	ticker := time.NewTicker(extension / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := extender.ExtendVisibility(ctx, message, extension); err != nil {
+				w.logger.Warn("failed to extend message visibility", "message_id", message.ID, "error", err)
 			}
 		}
+	}
+}
 
-		// Short sleep to prevent excessive polling
-		if len(result.Messages) == 0 {
-			time.Sleep(1 * time.Second)
+// processMessage processes a single message
+func (w *Watcher) processMessage(ctx context.Context, message *Message) error {
+	event, err := decodeEvent(message)
+	if err == nil {
+		err = w.verifyEvent(message, event)
+	}
+	if err != nil {
+		return err
+	}
+	w.syncCache(ctx, event)
+
+	// Call user handler
+	if w.handler != nil {
+		start := time.Now()
+		err := recoverHandler(func() error {
+			return w.handler(ctx, event)
+		})
+		w.metrics.HandlerDuration(time.Since(start))
+		if err != nil {
+			return fmt.Errorf("handler failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reportError passes a processing error to OnError, if configured
+func (w *Watcher) reportError(err error, message *Message) {
+	if w.config.OnError != nil {
+		w.config.OnError(err, message)
+	}
+}
+
+// PanicError is reported when a handler panics
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+// recoverHandler calls fn, converting a panic into a *PanicError
+func recoverHandler(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// processEvents passes a whole batch to BatchHandler, acknowledging every
+// message if it succeeds and none if it fails. Malformed messages are nacked
+// individually and left out of the batch.
+func (w *Watcher) processEvents(ctx context.Context, messages []*Message, acks *acker) {
+	events := make([]EventMessage, 0, len(messages))
+	var accepted []*Message
+	for _, message := range messages {
+		if w.seen(ctx, message) {
+			w.logger.Info("skipping duplicate message", "message_id", dedupeID(message))
+			acks.add(ctx, message)
+			continue
+		}
+
+		event, err := decodeEvent(message)
+		if err == nil {
+			err = w.verifyEvent(message, event)
+		}
+		if err != nil {
+			w.logger.Warn("message processing failed", "message_id", message.ID, "error", err)
+			w.reportError(err, message)
+			w.fail(ctx, message, err, acks)
+			continue
 		}
+		w.syncCache(ctx, event)
+		events = append(events, *event)
+		accepted = append(accepted, message)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	start := time.Now()
+	err := recoverHandler(func() error {
+		return w.config.BatchHandler(ctx, events)
+	})
+	w.metrics.HandlerDuration(time.Since(start))
+	if err != nil {
+		w.logger.Warn("batch handler failed", "count", len(events), "error", err)
+		for _, message := range accepted {
+			w.reportError(err, message)
+			w.fail(ctx, message, err, acks)
+		}
+		return
+	}
+
+	for _, message := range accepted {
+		w.mark(ctx, message)
+		w.clearFailures(message)
+		w.metrics.MessageProcessed()
+		acks.add(ctx, message)
 	}
 }
 
-// processMessage processes a single SQS message
-func (w *Watcher) processMessage(message types.Message) error {
+// decodeEvent parses and validates the EventMessage carried by a message
+func decodeEvent(message *Message) (*EventMessage, error) {
 	if message.Body == nil {
-		return fmt.Errorf("message body is nil")
+		return nil, fmt.Errorf("message body is nil")
 	}
 
 	// Parse message body
-	var messageBody SQSMessageBody
-	if err := json.Unmarshal([]byte(*message.Body), &messageBody); err != nil {
-		return fmt.Errorf("failed to parse message body: %w", err)
+	var event EventMessage
+	if err := json.Unmarshal(message.Body, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse message body: %w", err)
 	}
 
 	// Validate message format
-	if messageBody.Payload == nil {
-		return fmt.Errorf("message payload is nil")
+	payload, ok := event.Payload.(map[string]interface{})
+	if !ok || payload == nil {
+		return nil, fmt.Errorf("message payload is nil")
 	}
 
 	// Check if payload has ID
-	if _, exists := messageBody.Payload["id"]; !exists {
-		return fmt.Errorf("incorrect message format, missing payload.id")
+	if _, exists := payload["id"]; !exists {
+		return nil, fmt.Errorf("incorrect message format, missing payload.id")
 	}
 
-	// Call user handler
-	if w.config.Handler != nil {
-		w.config.Handler(nil, messageBody.Payload)
-	}
-
-	return nil
+	return &event, nil
 }
 
 // WatcherBuilder provides a fluent interface for building watchers
@@ -218,6 +896,27 @@ func (wb *WatcherBuilder) WithSQS(queueURL, region string) *WatcherBuilder {
 	return wb
 }
 
+// WithSQSClient uses a pre-built SQS client for the default SQS source
+func (wb *WatcherBuilder) WithSQSClient(client *sqs.Client) *WatcherBuilder {
+	wb.config.SQSClient = client
+	return wb
+}
+
+// WithSQSEndpoint overrides the SQS endpoint, e.g. for LocalStack
+func (wb *WatcherBuilder) WithSQSEndpoint(endpoint string) *WatcherBuilder {
+	wb.config.SQSEndpoint = endpoint
+	return wb
+}
+
+// WithSQSReceive tunes how many messages each SQS receive returns, how long
+// they stay hidden from other consumers and how long a receive long-polls
+func (wb *WatcherBuilder) WithSQSReceive(maxMessages int, visibilityTimeout, waitTime time.Duration) *WatcherBuilder {
+	wb.config.SQSMaxMessages = maxMessages
+	wb.config.SQSVisibilityTimeout = visibilityTimeout
+	wb.config.SQSWaitTime = waitTime
+	return wb
+}
+
 // WithFilters sets the data filters
 func (wb *WatcherBuilder) WithFilters(filters map[string]interface{}) *WatcherBuilder {
 	wb.config.Filters = filters
@@ -225,11 +924,119 @@ func (wb *WatcherBuilder) WithFilters(filters map[string]interface{}) *WatcherBu
 }
 
 // WithHandler sets the message handler
-func (wb *WatcherBuilder) WithHandler(handler func(ctx interface{}, record map[string]interface{})) *WatcherBuilder {
+func (wb *WatcherBuilder) WithHandler(handler Handler) *WatcherBuilder {
 	wb.config.Handler = handler
 	return wb
 }
 
+// WithRenewal sets how long before expiry the watch is renewed and a
+// callback invoked after each renewal attempt
+func (wb *WatcherBuilder) WithRenewal(margin time.Duration, onRenewal func(expiresAt time.Time, err error)) *WatcherBuilder {
+	wb.config.RenewalMargin = margin
+	wb.config.OnRenewal = onRenewal
+	return wb
+}
+
+// WithFIFO configures delivery to an SQS FIFO queue: groupBy is the record
+// field used as MessageGroupId and parallelGroups lets different groups be
+// processed concurrently
+func (wb *WatcherBuilder) WithFIFO(groupBy string, parallelGroups bool) *WatcherBuilder {
+	wb.config.MessageGroupBy = groupBy
+	wb.config.ParallelGroups = parallelGroups
+	return wb
+}
+
+// Use appends middleware around the handler; middleware added first runs
+// outermost
+func (wb *WatcherBuilder) Use(middlewares ...Middleware) *WatcherBuilder {
+	wb.config.Middleware = append(wb.config.Middleware, middlewares...)
+	return wb
+}
+
+// WithBatchHandler sets a handler that receives each batch of events at
+// once instead of one record at a time
+func (wb *WatcherBuilder) WithBatchHandler(handler func(ctx context.Context, events []EventMessage) error) *WatcherBuilder {
+	wb.config.BatchHandler = handler
+	return wb
+}
+
+// WithErrorHandler sets a callback for messages that fail processing
+func (wb *WatcherBuilder) WithErrorHandler(onError func(err error, message *Message)) *WatcherBuilder {
+	wb.config.OnError = onError
+	return wb
+}
+
+// WithDeadLetter removes messages that fail maxAttempts times from the
+// source and passes them to sink (which may be nil to only call
+// OnPoisonMessage)
+func (wb *WatcherBuilder) WithDeadLetter(maxAttempts int, sink DeadLetterSink) *WatcherBuilder {
+	wb.config.MaxAttempts = maxAttempts
+	wb.config.DeadLetter = sink
+	return wb
+}
+
+// WithBackfill delivers existing records to the handler before live changes
+func (wb *WatcherBuilder) WithBackfill(pageSize int) *WatcherBuilder {
+	wb.config.Backfill = true
+	wb.config.BackfillPageSize = pageSize
+	return wb
+}
+
+// WithCacheInvalidation evicts the client's cached copy of each changed
+// item before its event is handled, or refetches it with refresh
+func (wb *WatcherBuilder) WithCacheInvalidation(refresh bool) *WatcherBuilder {
+	wb.config.InvalidateCache = true
+	wb.config.RefreshCache = refresh
+	return wb
+}
+
+// WithSigningSecret rejects messages that are not signed with secret
+func (wb *WatcherBuilder) WithSigningSecret(secret string) *WatcherBuilder {
+	wb.config.SigningSecret = secret
+	return wb
+}
+
+// WithCheckpoints resumes the watcher from the newest processed event after
+// a restart
+func (wb *WatcherBuilder) WithCheckpoints(store CheckpointStore) *WatcherBuilder {
+	wb.config.Checkpoints = store
+	return wb
+}
+
+// WithMetrics sets the receiver of watcher instrumentation
+func (wb *WatcherBuilder) WithMetrics(metrics WatcherMetrics) *WatcherBuilder {
+	wb.config.Metrics = metrics
+	return wb
+}
+
+// WithDedupeStore sets the store used to skip already processed messages
+func (wb *WatcherBuilder) WithDedupeStore(store DedupeStore) *WatcherBuilder {
+	wb.config.DedupeStore = store
+	return wb
+}
+
+// WithConcurrency processes up to workers messages of a batch at once.
+// With orderByRecord, messages for the same record are still handled in
+// order.
+func (wb *WatcherBuilder) WithConcurrency(workers int, orderByRecord bool) *WatcherBuilder {
+	wb.config.Concurrency = workers
+	wb.config.OrderByRecord = orderByRecord
+	return wb
+}
+
+// WithVisibilityExtension keeps messages hidden from other consumers while
+// long-running handlers process them
+func (wb *WatcherBuilder) WithVisibilityExtension(extension time.Duration) *WatcherBuilder {
+	wb.config.VisibilityExtension = extension
+	return wb
+}
+
+// WithSource sets the message source, replacing the default SQS source
+func (wb *WatcherBuilder) WithSource(source MessageSource) *WatcherBuilder {
+	wb.config.Source = source
+	return wb
+}
+
 // Build creates the watcher
 func (wb *WatcherBuilder) Build() (*Watcher, error) {
 	return NewWatcher(wb.config)
@@ -0,0 +1,36 @@
+package carthooks
+
+import "context"
+
+// syncCache evicts, or with RefreshCache refetches, the client's cached copy
+// of the item a record event changed, so the handler reads it fresh
+func (w *Watcher) syncCache(ctx context.Context, event *EventMessage) {
+	if !w.config.InvalidateCache || w.config.Client == nil {
+		return
+	}
+	switch event.Meta.Event {
+	case EventCodeRecordCreated, EventCodeRecordUpdated, EventCodeRecordDeleted, EventCodeFieldChanged:
+	default:
+		return
+	}
+
+	var payload struct {
+		ID uint `json:"id"`
+	}
+	if err := event.DecodePayload(&payload); err != nil || payload.ID == 0 {
+		return
+	}
+	collectionID := event.Meta.CollectionID
+	if collectionID == 0 {
+		collectionID = w.config.CollectionID
+	}
+
+	client := w.config.Client
+	client.InvalidateItem(w.config.AppID, collectionID, payload.ID)
+	if !w.config.RefreshCache || event.Is(EventCodeRecordDeleted) {
+		return
+	}
+	if result := client.WithContext(ctx).GetItemByID(w.config.AppID, collectionID, payload.ID, nil); !result.Success {
+		w.logger.Warn("failed to refresh cached item", "item_id", payload.ID, "error", result.Error)
+	}
+}
@@ -0,0 +1,472 @@
+package carthooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// onceSource delivers a single batch, then nothing
+type onceSource struct {
+	recordingSource
+	batch []*Message
+}
+
+func (s *onceSource) Receive(ctx context.Context) ([]*Message, error) {
+	s.mu.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+	return batch, nil
+}
+
+func TestWatcher_RunDrainsInFlightMessages(t *testing.T) {
+	source := &onceSource{batch: []*Message{fifoMessage("1", "", 1)}}
+	started := make(chan struct{})
+
+	watcher, err := NewWatcher(&WatcherConfig{
+		Source: source,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- watcher.Run(ctx) }()
+
+	<-started
+	cancel()
+
+	if err := <-errs; err != nil {
+		t.Fatalf("Run() returned %v", err)
+	}
+	if len(source.acked) != 1 {
+		t.Errorf("Expected in-flight message to be acked, got %v", source.acked)
+	}
+}
+
+// closingSource fails a test if it is called after Close
+type closingSource struct {
+	onceSource
+	t      *testing.T
+	closed chan struct{}
+}
+
+func (s *closingSource) Ack(ctx context.Context, message *Message) error {
+	select {
+	case <-s.closed:
+		s.t.Error("Ack called after Close")
+	default:
+	}
+	return s.onceSource.Ack(ctx, message)
+}
+
+func (s *closingSource) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestWatcher_RunDrainTimeout(t *testing.T) {
+	source := &closingSource{
+		onceSource: onceSource{batch: []*Message{fifoMessage("1", "", 1)}},
+		t:          t,
+		closed:     make(chan struct{}),
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:       source,
+		DrainTimeout: 10 * time.Millisecond,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			close(started)
+			<-release
+			return nil
+		},
+	})
+
+	errs := make(chan error, 1)
+	go func() { errs <- watcher.Run(context.Background()) }()
+
+	<-started
+	watcher.Stop()
+	watcher.Stop()
+
+	if err := <-errs; err != ErrDrainTimeout {
+		t.Fatalf("Expected ErrDrainTimeout, got %v", err)
+	}
+
+	// The source stays open until the in-flight message is acked
+	select {
+	case <-source.closed:
+		t.Fatal("Expected the source left open while a handler was running")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-source.closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the source closed once the handler returned")
+	}
+	if len(source.acked) != 1 {
+		t.Errorf("Expected the in-flight message acked, got %v", source.acked)
+	}
+}
+
+func TestWatcher_StopBeforeRun(t *testing.T) {
+	watcher, _ := NewWatcher(&WatcherConfig{Source: &recordingSource{}})
+	watcher.Stop()
+	watcher.Stop()
+}
+
+// extendingSource records visibility extensions
+type extendingSource struct {
+	recordingSource
+	extensions int
+}
+
+func (s *extendingSource) ExtendVisibility(ctx context.Context, message *Message, timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.extensions++
+	return nil
+}
+
+func TestWatcher_HandlerErrorNacks(t *testing.T) {
+	source := &recordingSource{}
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source: source,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			return errors.New("database unavailable")
+		},
+	})
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "", 1)})
+
+	if len(source.acked) != 0 || len(source.nacked) != 1 {
+		t.Errorf("Expected message nacked, got acked=%v nacked=%v", source.acked, source.nacked)
+	}
+}
+
+func TestWatcher_ExtendsVisibilityWhileHandling(t *testing.T) {
+	source := &extendingSource{}
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:              source,
+		VisibilityExtension: 20 * time.Millisecond,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	})
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "", 1)})
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.extensions == 0 {
+		t.Error("Expected visibility to be extended while the handler ran")
+	}
+	if len(source.acked) != 1 {
+		t.Errorf("Expected message acked, got %v", source.acked)
+	}
+}
+
+func TestWatcher_ConcurrencyKeepsRecordOrder(t *testing.T) {
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	var order []string
+
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:        &recordingSource{},
+		Concurrency:   3,
+		OrderByRecord: true,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			order = append(order, fmt.Sprintf("%v/%v", event.Record()["id"], event.Record()["step"]))
+			mu.Unlock()
+			return nil
+		},
+	})
+
+	message := func(id, step int) *Message {
+		return &Message{Body: []byte(fmt.Sprintf(`{"payload":{"id":%d,"step":%d}}`, id, step))}
+	}
+	watcher.processBatch(context.Background(), []*Message{
+		message(1, 1), message(2, 1), message(1, 2), message(3, 1), message(1, 3),
+	})
+
+	if maxActive < 2 || maxActive > 3 {
+		t.Errorf("Expected 2-3 concurrent handlers, got %d", maxActive)
+	}
+
+	var record1 []string
+	for _, entry := range order {
+		if entry[:2] == "1/" {
+			record1 = append(record1, entry)
+		}
+	}
+	if fmt.Sprint(record1) != "[1/1 1/2 1/3]" {
+		t.Errorf("Expected record 1 changes in order, got %v", record1)
+	}
+}
+
+func TestWatcher_BatchHandler(t *testing.T) {
+	source := &recordingSource{}
+	var batches [][]EventMessage
+	fail := false
+
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source: source,
+		BatchHandler: func(ctx context.Context, events []EventMessage) error {
+			batches = append(batches, events)
+			if fail {
+				return errors.New("transaction aborted")
+			}
+			return nil
+		},
+	})
+
+	watcher.processBatch(context.Background(), []*Message{
+		fifoMessage("1", "", 1),
+		{ID: "bad", Body: []byte("not json")},
+		fifoMessage("2", "", 2),
+	})
+
+	if len(batches) != 1 || len(batches[0]) != 2 {
+		t.Fatalf("Expected one batch of 2 events, got %v", batches)
+	}
+	if len(source.acked) != 2 || len(source.nacked) != 1 || source.nacked[0] != "bad" {
+		t.Errorf("Expected 2 acked and the malformed message nacked, got acked=%v nacked=%v", source.acked, source.nacked)
+	}
+
+	fail = true
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("3", "", 3), fifoMessage("4", "", 4)})
+	if len(source.acked) != 2 || len(source.nacked) != 3 {
+		t.Errorf("Expected failed batch to be nacked, got acked=%v nacked=%v", source.acked, source.nacked)
+	}
+}
+
+func TestWatcher_RecoversHandlerPanics(t *testing.T) {
+	source := &recordingSource{}
+	var reported []error
+	var reportedMessages []*Message
+
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source:      source,
+		Concurrency: 2,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			if event.Record()["id"] == float64(1) {
+				panic("boom")
+			}
+			return nil
+		},
+		OnError: func(err error, message *Message) {
+			reported = append(reported, err)
+			reportedMessages = append(reportedMessages, message)
+		},
+	})
+
+	watcher.processBatch(context.Background(), []*Message{fifoMessage("1", "", 1), fifoMessage("2", "", 2)})
+
+	if len(reported) != 1 || reportedMessages[0].ID != "1" {
+		t.Fatalf("Expected one error for message 1, got %v", reported)
+	}
+	var panicErr *PanicError
+	if !errors.As(reported[0], &panicErr) || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("Expected a PanicError with stack, got %v", reported[0])
+	}
+	if len(source.acked) != 1 || len(source.nacked) != 1 {
+		t.Errorf("Expected 1 acked and 1 nacked, got acked=%v nacked=%v", source.acked, source.nacked)
+	}
+}
+
+func TestWatcher_DeliversTypedEvent(t *testing.T) {
+	var received *EventMessage
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Source: &recordingSource{},
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			received = event
+			return nil
+		},
+	})
+
+	body := `{"version":"1","meta":{"tenant_id":3,"collection_id":456,"event":"collection.item.created","trigger_type":"api"},"payload":{"id":9}}`
+	watcher.processBatch(context.Background(), []*Message{{ID: "1", Body: []byte(body)}})
+
+	if received == nil {
+		t.Fatal("Expected handler to be called")
+	}
+	if received.Meta.TenantID != 3 || received.Meta.CollectionID != 456 || received.Meta.Event != EventCodeRecordCreated || received.Meta.TriggerType != "api" {
+		t.Errorf("Unexpected meta %+v", received.Meta)
+	}
+	if received.Record()["id"] != float64(9) {
+		t.Errorf("Unexpected payload %v", received.Payload)
+	}
+}
+
+func TestWatcher_ResubscribesAfterUnauthorized(t *testing.T) {
+	var mu sync.Mutex
+	var authorizations []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			fmt.Fprint(w, `{"data":{"access_token":"fresh","token_type":"Bearer","expires_in":3600}}`)
+		case "/v1/watch-data":
+			mu.Lock()
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			mu.Unlock()
+			if r.Header.Get("Authorization") != "Bearer fresh" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":{"message":"token expired"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{}}`)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{
+		BaseURL:     server.URL,
+		AccessToken: "expired",
+		OAuth:       &OAuthConfig{ClientID: "id", ClientSecret: "secret"},
+	})
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Client:       client,
+		Source:       &recordingSource{},
+		EndpointURL:  "https://example.com/hook",
+		EndpointType: "webhook",
+	})
+
+	if err := watcher.subscribe(context.Background()); err != nil {
+		t.Fatalf("subscribe() failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(authorizations) != 2 || authorizations[1] != "Bearer fresh" {
+		t.Errorf("Expected a retry with the new token, got %v", authorizations)
+	}
+}
+
+func TestWatcher_InvalidatesCachedItem(t *testing.T) {
+	var mu sync.Mutex
+	version := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, `{"data":{"id":9,"title":"v%d"}}`, version)
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL, Cache: NewMemoryCache(0), CacheTTL: time.Hour})
+	client.GetItemByID(1, 456, 9, nil)
+
+	var title string
+	watcher, _ := NewWatcher(&WatcherConfig{
+		Client:          client,
+		AppID:           1,
+		Source:          &recordingSource{},
+		InvalidateCache: true,
+		Handler: func(ctx context.Context, event *EventMessage) error {
+			record, _ := client.GetItemByID(1, 456, 9, nil).GetRecord()
+			title = record.Title
+			return nil
+		},
+	})
+
+	mu.Lock()
+	version = 2
+	mu.Unlock()
+	body := `{"version":"1","meta":{"collection_id":456,"event":"collection.item.updated"},"payload":{"id":9}}`
+	watcher.processBatch(context.Background(), []*Message{{ID: "1", Body: []byte(body)}})
+
+	if title != "v2" {
+		t.Errorf("Expected handler to read the updated item, got %q", title)
+	}
+}
+
+func TestWatcher_RenewsShortLivedWatch(t *testing.T) {
+	var mu sync.Mutex
+	registrations := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/watch-data" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		mu.Lock()
+		registrations++
+		mu.Unlock()
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	var renewals []time.Time
+	watcher, err := NewWatcher(&WatcherConfig{
+		Client:       NewClient(&ClientConfig{BaseURL: server.URL}),
+		Source:       &recordingSource{},
+		EndpointURL:  "https://example.com/hook",
+		EndpointType: "webhook",
+		// Shorter than the default 1 hour renewal margin
+		Age: 1,
+		OnRenewal: func(expiresAt time.Time, err error) {
+			if err != nil {
+				t.Errorf("Renewal failed: %v", err)
+			}
+			renewals = append(renewals, expiresAt)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() failed: %v", err)
+	}
+	if err := watcher.Subscribe(); err != nil {
+		t.Fatalf("Subscribe() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1200*time.Millisecond)
+	defer cancel()
+	watcher.renewLoop(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Renewed midway through each 1s registration: at about 0.5s and 1s
+	if registrations < 2 || registrations > 4 {
+		t.Errorf("Expected 1 to 3 renewals in 1.2s, got %d registrations", registrations)
+	}
+	if len(renewals) == 0 || !renewals[len(renewals)-1].After(time.Now()) {
+		t.Errorf("Expected the registration renewed before expiry, got %v", renewals)
+	}
+}
+
+func TestNewWatcher_RejectsRenewalMarginPastAge(t *testing.T) {
+	_, err := NewWatcher(&WatcherConfig{
+		Source:        &recordingSource{},
+		Age:           3600,
+		RenewalMargin: time.Hour,
+	})
+	if err == nil {
+		t.Error("Expected an error for a renewal margin as long as the watch age")
+	}
+}
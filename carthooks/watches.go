@@ -0,0 +1,117 @@
+package carthooks
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// WatchInfo describes a registered watch
+type WatchInfo struct {
+	WatchID      string                 `json:"watch_id"`
+	Name         string                 `json:"name"`
+	AppID        uint                   `json:"app_id"`
+	CollectionID uint                   `json:"collection_id"`
+	EndpointURL  string                 `json:"endpoint_url"`
+	EndpointType string                 `json:"endpoint_type"`
+	Filters      map[string]interface{} `json:"filters,omitempty"`
+	Age          int                    `json:"age"`
+	ExpiresAt    int64                  `json:"expires_at"` // Unix timestamp in seconds
+	Status       string                 `json:"status"`
+}
+
+// Watch status values reported in WatchInfo.Status
+const (
+	WatchStatusActive = "active"
+	WatchStatusPaused = "paused"
+)
+
+// ExpiresTime returns the watch expiry as a time.Time
+func (w *WatchInfo) ExpiresTime() time.Time {
+	if w.ExpiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(w.ExpiresAt, 0)
+}
+
+// ListWatches lists the watches registered on a collection. Zero IDs are not
+// used as filters, so ListWatches(0, 0) lists every watch visible to the token.
+func (c *Client) ListWatches(appID, collectionID uint) *Result {
+	params := map[string]string{}
+	if appID != 0 {
+		params["app_id"] = strconv.FormatUint(uint64(appID), 10)
+	}
+	if collectionID != 0 {
+		params["collection_id"] = strconv.FormatUint(uint64(collectionID), 10)
+	}
+
+	resp, err := c.makeRequest("GET", "/v1/watch-data", nil, params)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// GetWatch retrieves a watch by ID
+func (c *Client) GetWatch(watchID string) *Result {
+	path := fmt.Sprintf("/v1/watch-data/%s", url.PathEscape(watchID))
+
+	resp, err := c.makeRequest("GET", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// UpdateWatchData updates an existing watch in place, e.g. to change its
+// filters or endpoint, without registering a new watch under a new name
+func (c *Client) UpdateWatchData(watchID string, options *WatchDataOptions) *Result {
+	path := fmt.Sprintf("/v1/watch-data/%s", url.PathEscape(watchID))
+
+	resp, err := c.makeRequest("PUT", path, options, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// PauseWatch suspends event delivery for a watch, e.g. during a maintenance
+// window. The watch keeps its registration and start time.
+func (c *Client) PauseWatch(watchID string) *Result {
+	path := fmt.Sprintf("/v1/watch-data/%s/pause", url.PathEscape(watchID))
+
+	resp, err := c.makeRequest("POST", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// ResumeWatch resumes event delivery for a paused watch
+func (c *Client) ResumeWatch(watchID string) *Result {
+	path := fmt.Sprintf("/v1/watch-data/%s/resume", url.PathEscape(watchID))
+
+	resp, err := c.makeRequest("POST", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
+
+// DeleteWatch deletes a watch by ID
+func (c *Client) DeleteWatch(watchID string) *Result {
+	path := fmt.Sprintf("/v1/watch-data/%s", url.PathEscape(watchID))
+
+	resp, err := c.makeRequest("DELETE", path, nil, nil)
+	if err != nil {
+		return requestFailed(err)
+	}
+
+	return c.parseResponse(resp)
+}
@@ -0,0 +1,145 @@
+package carthooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// watchServer checks each request's method and escaped path and replies
+// with body
+func watchServer(t *testing.T, method, path, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method || r.URL.EscapedPath() != path {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestClient_ListWatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/watch-data" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("app_id") != "123" || r.URL.Query().Has("collection_id") {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"watch_id": "w-1", "name": "orders", "app_id": 123, "collection_id": 456, "status": "active", "expires_at": 4102444800}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	var watches []WatchInfo
+	if err := client.ListWatches(123, 0).GetData(&watches); err != nil {
+		t.Fatalf("ListWatches() failed: %v", err)
+	}
+	if len(watches) != 1 || watches[0].WatchID != "w-1" || watches[0].CollectionID != 456 || watches[0].Status != WatchStatusActive {
+		t.Errorf("Unexpected watches %+v", watches)
+	}
+	if !watches[0].ExpiresTime().Equal(time.Unix(4102444800, 0)) {
+		t.Errorf("Unexpected expiry %v", watches[0].ExpiresTime())
+	}
+}
+
+func TestClient_GetWatch(t *testing.T) {
+	server := watchServer(t, "GET", "/v1/watch-data/team%2Fw-1", `{"data": {"watch_id": "team/w-1", "endpoint_url": "https://example.com/hook", "endpoint_type": "webhook", "age": 3600, "filters": {"status": "open"}}}`)
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	var watch WatchInfo
+	if err := client.GetWatch("team/w-1").GetData(&watch); err != nil {
+		t.Fatalf("GetWatch() failed: %v", err)
+	}
+	if watch.WatchID != "team/w-1" || watch.EndpointType != "webhook" || watch.Age != 3600 || watch.Filters["status"] != "open" {
+		t.Errorf("Unexpected watch %+v", watch)
+	}
+	if !watch.ExpiresTime().IsZero() {
+		t.Errorf("Expected zero expiry without expires_at, got %v", watch.ExpiresTime())
+	}
+}
+
+func TestClient_DeleteWatch(t *testing.T) {
+	server := watchServer(t, "DELETE", "/v1/watch-data/w%201", `{"data": null}`)
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	if result := client.DeleteWatch("w 1"); !result.Success {
+		t.Errorf("DeleteWatch() failed: %s", result.Error)
+	}
+}
+
+func TestClient_GetWatchNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"message": "watch not found", "code": "NOT_FOUND"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	if result := client.GetWatch("missing"); !result.IsNotFound() {
+		t.Errorf("Expected not found, got %+v", result)
+	}
+}
+
+func TestClient_UpdateWatchData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.EscapedPath() != "/v1/watch-data/team%2Fw-1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		var options WatchDataOptions
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			t.Errorf("Failed to decode body: %v", err)
+		}
+		if options.EndpointURL != "https://example.com/v2" || options.Filters["status"] != "open" {
+			t.Errorf("Unexpected options %+v", options)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"watch_id": "team/w-1", "endpoint_url": "https://example.com/v2", "filters": {"status": "open"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientConfig{BaseURL: server.URL})
+	result := client.UpdateWatchData("team/w-1", &WatchDataOptions{
+		EndpointURL: "https://example.com/v2",
+		Filters:     map[string]interface{}{"status": "open"},
+	})
+	var watch WatchInfo
+	if err := result.GetData(&watch); err != nil {
+		t.Fatalf("UpdateWatchData() failed: %v", err)
+	}
+	if watch.EndpointURL != "https://example.com/v2" {
+		t.Errorf("Unexpected watch %+v", watch)
+	}
+}
+
+func TestClient_PauseAndResumeWatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		call   func(*Client, string) *Result
+		path   string
+		status string
+	}{
+		{"PauseWatch", (*Client).PauseWatch, "/v1/watch-data/team%2Fw-1/pause", WatchStatusPaused},
+		{"ResumeWatch", (*Client).ResumeWatch, "/v1/watch-data/team%2Fw-1/resume", WatchStatusActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := watchServer(t, "POST", tt.path, `{"data": {"watch_id": "team/w-1", "status": "`+tt.status+`"}}`)
+			defer server.Close()
+
+			client := NewClient(&ClientConfig{BaseURL: server.URL})
+			var watch WatchInfo
+			if err := tt.call(client, "team/w-1").GetData(&watch); err != nil {
+				t.Fatalf("%s() failed: %v", tt.name, err)
+			}
+			if watch.Status != tt.status {
+				t.Errorf("Expected status %q, got %q", tt.status, watch.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package carthooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Watch endpoint types accepted in WatchDataOptions.EndpointType
+const (
+	EndpointTypeSQS         = "sqs"
+	EndpointTypeWebhook     = "webhook"
+	EndpointTypeEventBridge = "eventbridge"
+	EndpointTypeSNS         = "sns"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of a webhook body,
+// formatted as "sha256=<hex>"
+const WebhookSignatureHeader = "X-Carthooks-Signature"
+
+// maxWebhookBodySize bounds how much of a webhook request body is read
+const maxWebhookBodySize = 1 << 20 // 1 MiB
+
+// SignWebhookPayload returns the signature header value for body
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is a valid HMAC-SHA256
+// signature of body under secret. The comparison is constant-time.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	expected := SignWebhookPayload(secret, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// WebhookHandler is an http.Handler that receives webhook deliveries. It
+// validates signatures, decodes the EventMessage and dispatches it to Router.
+//
+// Responses: 204 when handled, 202 when no handler is registered for the
+// event (so it is not redelivered), 400 for malformed payloads, 401 for bad
+// signatures or events rejected by Replay, 405 for non-POST requests, 413 for oversized bodies, and 500
+// when the handler fails so the sender retries.
+type WebhookHandler struct {
+	Secret string
+	Router EventDispatcher
+	// Replay, if set, rejects stale and replayed deliveries
+	Replay *ReplayGuard
+	// Logger receives handler failures (default slog.Default())
+	Logger *slog.Logger
+}
+
+// NewWebhookHandler creates a WebhookHandler for the given shared secret.
+// Pass an *EventRouter to dispatch per EventCode, or an EventHandlerFunc to
+// receive every event.
+func NewWebhookHandler(secret string, router EventDispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		Secret: secret,
+		Router: router,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxWebhookBodySize {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	signature, err := VerifyEventSignature(r.Header, body, h.Secret)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event EventMessage
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, fmt.Sprintf("invalid event payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	event.Signature = signature
+
+	if h.Replay != nil {
+		if err := h.Replay.Check(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if h.Router == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if err := h.Router.Dispatch(&event); err != nil {
+		if errors.Is(err, ErrNoEventHandler) {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		// The sender retries failed deliveries; let the retry through
+		if h.Replay != nil {
+			h.Replay.Forget(&event)
+		}
+		orDefaultLogger(h.Logger).Warn("webhook handler failed", "event", event.Meta.Event, "error", err)
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package carthooks
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"version":"1"}`)
+	signature := SignWebhookPayload("secret", body)
+
+	if !VerifyWebhookSignature("secret", body, signature) {
+		t.Error("Expected valid signature to verify")
+	}
+	if VerifyWebhookSignature("other-secret", body, signature) {
+		t.Error("Expected signature with wrong secret to fail")
+	}
+	if VerifyWebhookSignature("secret", []byte(`{"version":"2"}`), signature) {
+		t.Error("Expected signature over modified body to fail")
+	}
+	if VerifyWebhookSignature("", body, SignWebhookPayload("", body)) {
+		t.Error("Expected empty secret to be rejected")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received *EventMessage
+	router := NewEventRouter().On(EventCodeRecordCreated, func(event *EventMessage) error {
+		received = event
+		return nil
+	})
+	handler := NewWebhookHandler("secret", router)
+
+	body := []byte(`{"version":"1","meta":{"collection_id":456,"event":"collection.item.created"},"payload":{"id":1}}`)
+
+	req := httptest.NewRequest("POST", "/events", bytes.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, "sha256=deadbeef")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || received != nil {
+		t.Fatalf("Expected 401 for bad signature, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/events", bytes.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", body))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", rec.Code)
+	}
+	if received == nil || received.Meta.Event != EventCodeRecordCreated || received.Meta.CollectionID != 456 {
+		t.Errorf("Unexpected event %+v", received)
+	}
+	if received.Signature == nil || !received.Signature.Verified || received.Signature.Algorithm != "sha256" {
+		t.Errorf("Expected verified signature on event, got %+v", received.Signature)
+	}
+}
+
+func TestWebhookHandler_Statuses(t *testing.T) {
+	router := NewEventRouter().On(EventCodeRecordUpdated, func(event *EventMessage) error {
+		return errors.New("database unavailable")
+	})
+	handler := NewWebhookHandler("secret", router)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"wrong method", "GET", `{}`, http.StatusMethodNotAllowed},
+		{"malformed payload", "POST", `not json`, http.StatusBadRequest},
+		{"unrouted event", "POST", `{"meta":{"event":"collection.item.deleted"}}`, http.StatusAccepted},
+		{"handler error", "POST", `{"meta":{"event":"collection.item.updated"}}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(tt.body)
+			req := httptest.NewRequest(tt.method, "/events", bytes.NewReader(body))
+			req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Command coverage-check diffs the SDK endpoint coverage manifest against the
+// platform OpenAPI spec (JSON) and exits non-zero when the spec contains
+// endpoints the Go SDK doesn't wrap.
+//
+// Usage:
+//
+//	go run ./cmd/coverage-check -spec openapi.json [-prefix /open/api] [-json]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/carthooks/carthooks-sdk-go/carthooks"
+)
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "patch": true, "head": true, "options": true,
+}
+
+type openAPISpec struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+func main() {
+	specPath := flag.String("spec", "", "path to the platform OpenAPI spec (JSON)")
+	prefix := flag.String("prefix", "", "path prefix to strip from spec paths, e.g. /open/api")
+	jsonOutput := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	if *specPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("failed to read spec: %v", err)
+	}
+
+	var spec openAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		log.Fatalf("failed to parse spec: %v", err)
+	}
+
+	var endpoints []carthooks.CoverageEndpoint
+	for path, operations := range spec.Paths {
+		path = strings.TrimPrefix(path, *prefix)
+		for method := range operations {
+			if httpMethods[strings.ToLower(method)] {
+				endpoints = append(endpoints, carthooks.CoverageEndpoint{
+					Method: strings.ToUpper(method),
+					Path:   path,
+				})
+			}
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	missing := carthooks.Coverage().Missing(endpoints)
+
+	if *jsonOutput {
+		report := map[string]interface{}{
+			"spec_endpoints": len(endpoints),
+			"covered":        len(endpoints) - len(missing),
+			"missing":        missing,
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	} else {
+		fmt.Printf("%d/%d spec endpoints wrapped by the Go SDK\n", len(endpoints)-len(missing), len(endpoints))
+		for _, endpoint := range missing {
+			fmt.Printf("  missing: %s %s\n", endpoint.Method, endpoint.Path)
+		}
+	}
+
+	if len(missing) > 0 {
+		os.Exit(1)
+	}
+}
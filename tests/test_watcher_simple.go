@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
 
 	"github.com/joho/godotenv"
@@ -11,11 +13,13 @@ import (
 	"github.com/carthooks/carthooks-sdk-go/carthooks"
 )
 
-func processHandler(ctx interface{}, record map[string]interface{}) {
+func processHandler(ctx context.Context, event *carthooks.EventMessage) error {
 	fmt.Println("=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=")
 	fmt.Println("New data item received:")
-	fmt.Printf("Record: %+v\n", record)
+	fmt.Printf("Event: %s (collection %d)\n", event.Meta.Event, event.Meta.CollectionID)
+	fmt.Printf("Record: %+v\n", event.Record())
 	fmt.Println("=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=" + "=")
+	return nil
 }
 
 func main() {
@@ -100,8 +104,11 @@ func main() {
 
 	fmt.Println("Starting to listen for data...")
 
-	// Run watcher (this will block)
-	if err := watcher.Run(); err != nil {
+	// Run watcher until interrupted (this will block)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := watcher.Run(ctx); err != nil {
 		log.Fatalf("Watcher error: %v", err)
 	}
 }